@@ -10,8 +10,22 @@ import (
 	docs "github.com/felipe1496/open-wallet/docs"
 
 	"github.com/felipe1496/open-wallet/internal/resources/auth"
+	"github.com/felipe1496/open-wallet/internal/resources/boletos"
+	"github.com/felipe1496/open-wallet/internal/resources/budgets"
+	"github.com/felipe1496/open-wallet/internal/resources/calendar"
 	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/envelopes"
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	"github.com/felipe1496/open-wallet/internal/resources/imports"
+	"github.com/felipe1496/open-wallet/internal/resources/journal"
+	"github.com/felipe1496/open-wallet/internal/resources/networth"
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	"github.com/felipe1496/open-wallet/internal/resources/reports"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+	"github.com/felipe1496/open-wallet/internal/resources/simulations"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
 	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/resources/userdata"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -71,6 +85,20 @@ func main() {
 	auth.Router(r)
 	transactions.Router(r)
 	categories.Router(r)
+	reports.Router(r)
+	summary.Router(r)
+	budgets.Router(r)
+	envelopes.Router(r)
+	recurring.Router(r)
+	forecast.Router(r)
+	simulations.Router(r)
+	networth.Router(r)
+	rules.Router(r)
+	imports.Router(r)
+	boletos.Router(r)
+	journal.Router(r)
+	userdata.Router(r)
+	calendar.Router(r)
 
 	port := os.Getenv("PORT")
 	if port == "" {
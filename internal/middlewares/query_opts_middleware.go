@@ -3,6 +3,7 @@ package middlewares
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -28,8 +29,14 @@ func QueryOptsMiddleware() gin.HandlerFunc {
 			perPageNum = 10
 		}
 
+		// the presence of cursor (even empty, for the first page) switches from
+		// offset to keyset pagination
+		cursor, useCursor := ctx.GetQuery("cursor")
+
 		queryOpts := utils.QueryOpts()
-		queryOpts.Offset((pageNum - 1) * perPageNum)
+		if !useCursor {
+			queryOpts.Offset((pageNum - 1) * perPageNum)
+		}
 		queryOpts.Limit(perPageNum + 1)
 
 		if orderBy != "" {
@@ -68,6 +75,26 @@ func QueryOptsMiddleware() gin.HandlerFunc {
 			}
 		}
 
+		if useCursor {
+			queryOpts.CursorPagination = true
+
+			// id breaks ties so rows with equal order values are never skipped
+			if !queryOpts.HasOrder("id") {
+				queryOpts.OrderBy("id", queryOpts.LastOrderDir())
+			}
+
+			if cursor != "" {
+				decoded, err := utils.DecodeCursor(cursor)
+				if err != nil || !slices.Equal(decoded.Orders, queryOpts.OrderKeys()) || len(decoded.Values) != len(queryOpts.Orders) {
+					apiErr := utils.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+					ctx.JSON(apiErr.StatusCode, apiErr)
+					ctx.Abort()
+					return
+				}
+				queryOpts.After(decoded)
+			}
+		}
+
 		filter, _ := ctx.GetQuery("filter")
 
 		if filter != "" {
@@ -92,12 +119,13 @@ func parseFilter(filter string, query *utils.QueryOptsBuilder) (*utils.QueryOpts
 	splitted := strings.Split(filter, " and ")
 
 	allowedOperators := map[string]bool{
-		"eq": true,
-		"ne": true,
-		"gt": true,
-		"ge": true,
-		"lt": true,
-		"le": true,
+		"eq":   true,
+		"ne":   true,
+		"gt":   true,
+		"ge":   true,
+		"lt":   true,
+		"le":   true,
+		"like": true,
 	}
 
 	for _, filter := range splitted {
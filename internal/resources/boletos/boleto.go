@@ -0,0 +1,298 @@
+package boletos
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// Names of the most common banks by their FEBRABAN code
+var bankNames = map[string]string{
+	"001": "Banco do Brasil",
+	"004": "Banco do Nordeste",
+	"021": "Banestes",
+	"033": "Santander",
+	"037": "Banpará",
+	"041": "Banrisul",
+	"070": "BRB",
+	"077": "Banco Inter",
+	"104": "Caixa Econômica Federal",
+	"208": "BTG Pactual",
+	"212": "Banco Original",
+	"237": "Bradesco",
+	"260": "Nubank",
+	"290": "PagBank",
+	"323": "Mercado Pago",
+	"336": "C6 Bank",
+	"341": "Itaú",
+	"380": "PicPay",
+	"403": "Cora",
+	"422": "Banco Safra",
+	"655": "Banco Votorantim",
+	"748": "Sicredi",
+	"756": "Sicoob",
+}
+
+// Segments of utility (arrecadação) boletos, by the second digit
+var segmentNames = map[byte]string{
+	'1': "Prefeituras",
+	'2': "Saneamento",
+	'3': "Energia elétrica e gás",
+	'4': "Telecomunicações",
+	'5': "Órgãos governamentais",
+	'6': "Carnês e assemelhados",
+	'7': "Multas de trânsito",
+	'9': "Uso exclusivo do banco",
+}
+
+// The due factor counts days from 1997-10-07 and, after reaching 9999 on
+// 2025-02-21, restarted at 1000 on 2025-02-22
+var (
+	factorBase      = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+	factorRestart   = time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC)
+	maxDueDistance  = 365 * 5 * 24 * time.Hour
+	utilityDueStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// mod10 is the check digit of the fields of a typed line, digits are
+// weighted 2 and 1 from the right and two digit products are summed up
+func mod10(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// mod11 weights digits from 2 to 9 from the right
+func mod11(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	return sum % 11
+}
+
+// bankCheckDigit is the general check digit of a banking barcode, which is
+// never 0
+func bankCheckDigit(digits string) int {
+	dv := 11 - mod11(digits)
+	if dv == 0 || dv >= 10 {
+		return 1
+	}
+	return dv
+}
+
+// utilityCheckDigit follows the value type of a utility barcode, 6 and 7 use
+// mod 10 and 8 and 9 use mod 11
+func utilityCheckDigit(valueType byte, digits string) int {
+	if valueType == '6' || valueType == '7' {
+		return mod10(digits)
+	}
+	dv := 11 - mod11(digits)
+	if dv >= 10 {
+		return 0
+	}
+	return dv
+}
+
+func invalidCheckDigit(field string) error {
+	return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("check digit of %s is invalid", field))
+}
+
+// dueDate reads the due factor, picking the cycle closest to now
+func dueDate(factor int, now time.Time) *string {
+	if factor == 0 {
+		return nil
+	}
+
+	date := factorBase.AddDate(0, 0, factor)
+	if factor >= 1000 {
+		restarted := factorRestart.AddDate(0, 0, factor-1000)
+		if restarted.Sub(now).Abs() < date.Sub(now).Abs() {
+			date = restarted
+		}
+	}
+
+	formatted := date.Format("2006-01-02")
+	return &formatted
+}
+
+func amountOf(digits string) *float64 {
+	cents, _ := strconv.ParseInt(digits, 10, 64)
+	if cents == 0 {
+		return nil
+	}
+	amount := float64(cents) / 100
+	return &amount
+}
+
+// bankBarcode checks the general check digit of a banking barcode
+func bankBarcode(barcode string) error {
+	if int(barcode[4]-'0') != bankCheckDigit(barcode[:4]+barcode[5:]) {
+		return invalidCheckDigit("the barcode")
+	}
+	return nil
+}
+
+// bankTypedLine turns the 47 digits of a banking typed line into the barcode,
+// checking the digits of its three fields
+func bankTypedLine(line string) (string, error) {
+	fields := []string{line[0:9], line[10:20], line[21:31]}
+	checkDigits := []byte{line[9], line[20], line[31]}
+	for i, field := range fields {
+		if int(checkDigits[i]-'0') != mod10(field) {
+			return "", invalidCheckDigit(fmt.Sprintf("field %d", i+1))
+		}
+	}
+
+	barcode := line[0:4] + line[32:47] + line[4:9] + line[10:20] + line[21:31]
+	if err := bankBarcode(barcode); err != nil {
+		return "", invalidCheckDigit("field 4")
+	}
+	return barcode, nil
+}
+
+func formatBankTypedLine(barcode string) string {
+	field1 := barcode[0:4] + barcode[19:24]
+	field2 := barcode[24:34]
+	field3 := barcode[34:44]
+	field1 += strconv.Itoa(mod10(field1))
+	field2 += strconv.Itoa(mod10(field2))
+	field3 += strconv.Itoa(mod10(field3))
+
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s",
+		field1[:5], field1[5:], field2[:5], field2[5:], field3[:5], field3[5:], barcode[4:5], barcode[5:19])
+}
+
+// utilityBarcode checks the general check digit of a utility barcode
+func utilityBarcode(barcode string) error {
+	if int(barcode[3]-'0') != utilityCheckDigit(barcode[2], barcode[:3]+barcode[4:]) {
+		return invalidCheckDigit("the barcode")
+	}
+	return nil
+}
+
+// utilityTypedLine turns the 48 digits of a utility typed line, four blocks
+// of eleven digits and their check digit, into the barcode
+func utilityTypedLine(line string) (string, error) {
+	var barcode strings.Builder
+	for i := 0; i < 4; i++ {
+		block := line[i*12 : i*12+11]
+		if int(line[i*12+11]-'0') != utilityCheckDigit(line[2], block) {
+			return "", invalidCheckDigit(fmt.Sprintf("block %d", i+1))
+		}
+		barcode.WriteString(block)
+	}
+
+	if err := utilityBarcode(barcode.String()); err != nil {
+		return "", err
+	}
+	return barcode.String(), nil
+}
+
+func formatUtilityTypedLine(barcode string) string {
+	blocks := make([]string, 4)
+	for i := range blocks {
+		block := barcode[i*11 : i*11+11]
+		blocks[i] = fmt.Sprintf("%s-%d", block, utilityCheckDigit(barcode[2], block))
+	}
+	return strings.Join(blocks, " ")
+}
+
+// utilityDueDate looks for a YYYYMMDD date at the start of the free field,
+// where most issuers put it, since the layout doesn't define one
+func utilityDueDate(barcode string, now time.Time) *string {
+	start := 19
+	if barcode[1] == '6' {
+		// carnês identify the company by the 8 first digits of the CNPJ
+		start = 23
+	}
+	if len(barcode) < start+8 {
+		return nil
+	}
+
+	date, err := time.Parse("20060102", barcode[start:start+8])
+	if err != nil || date.Before(utilityDueStart) || date.Sub(now).Abs() > maxDueDistance {
+		return nil
+	}
+
+	formatted := date.Format("2006-01-02")
+	return &formatted
+}
+
+// ParseBoleto reads the typed line (linha digitável) or the barcode number
+// of a banking or utility boleto, ignoring anything that is not a digit, and
+// validates its check digits
+func ParseBoleto(code string, now time.Time) (Boleto, error) {
+	var digits strings.Builder
+	for _, r := range code {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+
+	utility := strings.HasPrefix(number, "8")
+	var barcode string
+	var err error
+	switch {
+	case len(number) == 44 && utility:
+		barcode, err = number, utilityBarcode(number)
+	case len(number) == 48 && utility:
+		barcode, err = utilityTypedLine(number)
+	case len(number) == 44:
+		barcode, err = number, bankBarcode(number)
+	case len(number) == 47:
+		barcode, err = bankTypedLine(number)
+	default:
+		return Boleto{}, ErrInvalidCode
+	}
+
+	if err != nil {
+		return Boleto{}, err
+	}
+
+	if utility {
+		segment := segmentNames[barcode[1]]
+		boleto := Boleto{
+			Kind:      KindUtility,
+			Barcode:   barcode,
+			TypedLine: formatUtilityTypedLine(barcode),
+			DueDate:   utilityDueDate(barcode, now),
+		}
+		if segment != "" {
+			boleto.Segment = &segment
+		}
+		// 7 and 9 carry a reference quantity instead of the amount
+		if barcode[2] == '6' || barcode[2] == '8' {
+			boleto.Amount = amountOf(barcode[4:15])
+		}
+		return boleto, nil
+	}
+
+	factor, _ := strconv.Atoi(barcode[5:9])
+	bankCode := barcode[0:3]
+	boleto := Boleto{
+		Kind:      KindBank,
+		Barcode:   barcode,
+		TypedLine: formatBankTypedLine(barcode),
+		BankCode:  &bankCode,
+		Amount:    amountOf(barcode[9:19]),
+		DueDate:   dueDate(factor, now),
+	}
+	if name, ok := bankNames[bankCode]; ok {
+		boleto.BankName = &name
+	}
+	return boleto, nil
+}
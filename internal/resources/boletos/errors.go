@@ -0,0 +1,15 @@
+package boletos
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrInvalidCode        = utils.NewHTTPError(http.StatusBadRequest, "code must be a typed line of 47 or 48 digits or a barcode of 44 digits")
+	ErrMissingAmount      = utils.NewHTTPError(http.StatusBadRequest, "boleto has no amount, send it in amount")
+	ErrMissingDueDate     = utils.NewHTTPError(http.StatusBadRequest, "boleto has no due date, send it in due_date")
+	ErrBillAlreadyCreated = utils.NewHTTPError(http.StatusConflict, "a bill was already created for this boleto")
+	ErrFailedToCheckBills = utils.NewHTTPError(http.StatusInternalServerError, "failed to check created bills")
+)
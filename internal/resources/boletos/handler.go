@@ -0,0 +1,120 @@
+package boletos
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	boletosUseCase BoletosUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	categoriesUseCase := categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db)
+	return &API{
+		boletosUseCase: NewBoletosUseCase(NewBoletosRepo(db),
+			transactions.NewTransactionsUseCase(transactions.NewTransactionsRepo(db),
+				categoriesUseCase,
+				rules.NewRulesUseCase(rules.NewRulesRepo(db), categoriesUseCase, db),
+				db),
+			db),
+	}
+}
+
+// @Summary Parse a boleto
+// @Description Read the typed line (linha digitável) or barcode of a banking or utility (arrecadação) boleto, validating its check digits, and get its amount, due date and bank along with a prefilled transaction paying it
+// @Tags boletos
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body ParseBoletoRequest true "Typed line or barcode"
+// @Success 200 {object} ParseBoletoResponse "Boleto and prefilled transaction"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Router /boletos/parse [post]
+func (api *API) Parse(ctx *gin.Context) {
+	var body ParseBoletoRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	boleto, err := api.boletosUseCase.Parse(body.Code)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	transaction, missing := Prefill(boleto)
+
+	ctx.JSON(http.StatusOK, ParseBoletoResponse{
+		Data: ParseBoletoResponseData{
+			Boleto:      boleto,
+			Transaction: transaction,
+			Missing:     missing,
+		},
+	})
+}
+
+// @Summary Create a bill from a boleto
+// @Description Create the expense paying a boleto on its due date, tagged as boleto. Amount and due date are required only when the boleto doesn't have them, and a boleto can only be created once
+// @Tags boletos
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateBillRequest true "Bill payload"
+// @Success 201 {object} CreateBillResponse "Bill created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 409 {object} utils.HTTPError "Bill already created"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /boletos/bills [post]
+func (api *API) CreateBill(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateBillRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	boleto, transaction, err := api.boletosUseCase.CreateBill(CreateBillDTO{
+		UserID:     userID,
+		Code:       body.Code,
+		Name:       body.Name,
+		CategoryID: body.CategoryID,
+		Payee:      body.Payee,
+		Account:    body.Account,
+		Amount:     body.Amount,
+		DueDate:    body.DueDate,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateBillResponse{
+		Data: CreateBillResponseData{
+			Boleto:      boleto,
+			Transaction: transaction,
+		},
+	})
+}
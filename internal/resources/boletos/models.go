@@ -0,0 +1,82 @@
+package boletos
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type ParseBoletoRequest struct {
+	Code string `json:"code" binding:"required,min=44,max=60"`
+}
+
+type ParseBoletoResponse struct {
+	Data ParseBoletoResponseData `json:"data"`
+}
+
+// The transaction is prefilled with what the boleto tells, missing lists
+// what must still be filled before creating it
+type ParseBoletoResponseData struct {
+	Boleto      Boleto                                `json:"boleto"`
+	Transaction transactions.CreateTransactionRequest `json:"transaction"`
+	Missing     []string                              `json:"missing"`
+}
+
+type CreateBillRequest struct {
+	Code       string   `json:"code" binding:"required,min=44,max=60"`
+	Name       *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	CategoryID *string  `json:"category_id" binding:"omitempty"`
+	Payee      *string  `json:"payee" binding:"omitempty,min=1,max=100"`
+	Account    *string  `json:"account" binding:"omitempty,min=1,max=100"`
+	Amount     *float64 `json:"amount" binding:"omitempty,gt=0,lte=999999"`
+	DueDate    *string  `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type CreateBillResponse struct {
+	Data CreateBillResponseData `json:"data"`
+}
+
+type CreateBillResponseData struct {
+	Boleto      Boleto                   `json:"boleto"`
+	Transaction transactions.Transaction `json:"transaction"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type BoletoKind string
+
+const (
+	KindBank    BoletoKind = "bank"
+	KindUtility BoletoKind = "utility"
+)
+
+// A banking boleto or a utility one (arrecadação), like water, power and
+// taxes. The amount and the due date are missing when the boleto doesn't have
+// them, and only banking boletos tell the bank
+type Boleto struct {
+	Kind      BoletoKind `json:"kind"`
+	Barcode   string     `json:"barcode"`
+	TypedLine string     `json:"typed_line"`
+	BankCode  *string    `json:"bank_code"`
+	BankName  *string    `json:"bank_name"`
+	Segment   *string    `json:"segment"`
+	Amount    *float64   `json:"amount"`
+	DueDate   *string    `json:"due_date"`
+}
+
+type CreateBillDTO struct {
+	UserID     string
+	Code       string
+	Name       *string
+	CategoryID *string
+	Payee      *string
+	Account    *string
+	Amount     *float64
+	DueDate    *string
+}
@@ -0,0 +1,39 @@
+package boletos
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type BoletosRepo interface {
+	CountBills(db utils.Executer, userID string, externalID string) (int, error)
+}
+
+type BoletosRepoImpl struct {
+}
+
+func NewBoletosRepo(db utils.Executer) BoletosRepo {
+	return &BoletosRepoImpl{}
+}
+
+// CountBills counts the transactions created from a boleto
+func (r *BoletosRepoImpl) CountBills(db utils.Executer, userID string, externalID string) (int, error) {
+	sql, args, err := squirrel.Select("COUNT(*)").
+		From("transactions").
+		Where(squirrel.Eq{"user_id": userID, "external_id": externalID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
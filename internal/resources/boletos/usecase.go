@@ -0,0 +1,136 @@
+package boletos
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+type BoletosUseCase interface {
+	Parse(code string) (Boleto, error)
+	CreateBill(payload CreateBillDTO) (Boleto, transactions.Transaction, error)
+}
+
+type BoletosUseCaseImpl struct {
+	repo                BoletosRepo
+	transactionsUseCase transactions.TransactionsUseCase
+	db                  *sql.DB
+}
+
+func NewBoletosUseCase(repo BoletosRepo, transactionsUseCase transactions.TransactionsUseCase, db *sql.DB) BoletosUseCase {
+	return &BoletosUseCaseImpl{
+		repo:                repo,
+		transactionsUseCase: transactionsUseCase,
+		db:                  db,
+	}
+}
+
+// billName names a bill after the bank or the utility segment
+func billName(boleto Boleto) string {
+	switch {
+	case boleto.BankName != nil:
+		return "Boleto " + *boleto.BankName
+	case boleto.Segment != nil:
+		return "Boleto " + *boleto.Segment
+	default:
+		return "Boleto"
+	}
+}
+
+// Prefill builds the expense paying a boleto on its due date and lists the
+// fields the boleto doesn't tell
+func Prefill(boleto Boleto) (transactions.CreateTransactionRequest, []string) {
+	note := boleto.TypedLine
+	request := transactions.CreateTransactionRequest{
+		Name:    billName(boleto),
+		Note:    &note,
+		Type:    constants.SimpleExpense,
+		Tags:    []string{"boleto"},
+		Entries: make([]transactions.CreateEntryRequest, 0),
+	}
+
+	missing := make([]string, 0)
+	if boleto.Amount == nil {
+		missing = append(missing, "amount")
+	}
+	if boleto.DueDate == nil {
+		missing = append(missing, "due_date")
+	}
+
+	if len(missing) == 0 {
+		request.Entries = append(request.Entries, transactions.CreateEntryRequest{
+			Amount:        -*boleto.Amount,
+			ReferenceDate: *boleto.DueDate,
+		})
+	}
+
+	return request, missing
+}
+
+func (uc *BoletosUseCaseImpl) Parse(code string) (Boleto, error) {
+	return ParseBoleto(code, time.Now())
+}
+
+// CreateBill creates the expense of a boleto on its due date, which shows up
+// as scheduled until then. The amount and due date sent take precedence over
+// the boleto ones, and a boleto is only created once
+func (uc *BoletosUseCaseImpl) CreateBill(payload CreateBillDTO) (Boleto, transactions.Transaction, error) {
+	boleto, err := ParseBoleto(payload.Code, time.Now())
+	if err != nil {
+		return Boleto{}, transactions.Transaction{}, err
+	}
+
+	amount, dueDate := boleto.Amount, boleto.DueDate
+	if payload.Amount != nil {
+		amount = payload.Amount
+	}
+	if payload.DueDate != nil {
+		dueDate = payload.DueDate
+	}
+
+	if amount == nil {
+		return Boleto{}, transactions.Transaction{}, ErrMissingAmount
+	}
+
+	if dueDate == nil {
+		return Boleto{}, transactions.Transaction{}, ErrMissingDueDate
+	}
+
+	externalID := "boleto:" + boleto.Barcode
+	created, err := uc.repo.CountBills(uc.db, payload.UserID, externalID)
+	if err != nil {
+		return Boleto{}, transactions.Transaction{}, ErrFailedToCheckBills
+	}
+
+	if created > 0 {
+		return Boleto{}, transactions.Transaction{}, ErrBillAlreadyCreated
+	}
+
+	request, _ := Prefill(boleto)
+	name := request.Name
+	if payload.Name != nil {
+		name = *payload.Name
+	}
+
+	transaction, err := uc.transactionsUseCase.CreateTransaction(transactions.CreateTransactionDTO{
+		UserID:     payload.UserID,
+		Name:       name,
+		Note:       request.Note,
+		Type:       request.Type,
+		CategoryID: payload.CategoryID,
+		Payee:      payload.Payee,
+		Account:    payload.Account,
+		Tags:       request.Tags,
+		ExternalID: &externalID,
+		Entries: []transactions.CreateEntryDTO{
+			{Amount: -*amount, ReferenceDate: *dueDate},
+		},
+	})
+	if err != nil {
+		return Boleto{}, transactions.Transaction{}, err
+	}
+
+	return boleto, transaction, nil
+}
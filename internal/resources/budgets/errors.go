@@ -0,0 +1,21 @@
+package budgets
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrBudgetNotFound             = utils.NewHTTPError(http.StatusNotFound, "budget not found")
+	ErrCategoryNotFound           = utils.NewHTTPError(http.StatusNotFound, "category not found")
+	ErrBudgetAlreadyExists        = utils.NewHTTPError(http.StatusConflict, "category already has a budget for this period")
+	ErrInvalidPeriod              = utils.NewHTTPError(http.StatusBadRequest, "period must be in the YYYYMM format")
+	ErrFailedToCreateBudget       = utils.NewHTTPError(http.StatusInternalServerError, "failed to create budget")
+	ErrFailedToListBudgets        = utils.NewHTTPError(http.StatusInternalServerError, "failed to list budgets")
+	ErrFailedToCountBudgets       = utils.NewHTTPError(http.StatusInternalServerError, "failed to count budgets")
+	ErrFailedToUpdateBudget       = utils.NewHTTPError(http.StatusInternalServerError, "failed to update budget")
+	ErrFailedToDeleteBudget       = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete budget")
+	ErrFailedToCheckCategory      = utils.NewHTTPError(http.StatusInternalServerError, "failed to check if category exists")
+	ErrFailedToListCategorySpends = utils.NewHTTPError(http.StatusInternalServerError, "failed to list category amounts per period")
+)
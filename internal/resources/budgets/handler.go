@@ -0,0 +1,225 @@
+package budgets
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	budgetsUseCase BudgetsUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		budgetsUseCase: NewBudgetsUseCase(NewBudgetsRepo(db),
+			categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+			db),
+	}
+}
+
+// @Summary Create a budget
+// @Description Create the budget of a category for a period, or its monthly default when period is omitted, which applies from the period it was created in
+// @Tags budgets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateBudgetRequest true "Budget payload"
+// @Success 201 {object} CreateBudgetResponse "Budget created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Category not found"
+// @Failure 409 {object} utils.HTTPError "Budget already exists"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /budgets [post]
+func (api *API) Create(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateBudgetRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	budget, err := api.budgetsUseCase.Create(CreateBudgetDTO{
+		UserID:     userID,
+		CategoryID: body.CategoryID,
+		Period:     body.Period,
+		Amount:     body.Amount,
+		Rollover:   body.Rollover,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateBudgetResponse{
+		Data: CreateBudgetResponseData{
+			Budget: budget,
+		},
+	})
+}
+
+// @Summary List budgets
+// @Description List budgets
+// @Tags budgets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(period:desc)
+// @Param filter query string false "Budget filter"
+// @Success 200 {object} ListBudgetsResponse "List of budgets"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /budgets [get]
+func (api *API) List(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	budgets, err := api.budgetsUseCase.List(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	budgets, meta, err := utils.Paginate(budgets, queryOpts, page, perPage, api.budgetsUseCase.Count)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListBudgetsResponse{
+		Data: ListBudgetsResponseData{
+			Budgets: budgets,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary List budget progress of a period
+// @Description List planned, spent, remaining and percentage used of each budgeted category in a period
+// @Tags budgets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param period path string true "period" example(202501)
+// @Success 200 {object} ListBudgetProgressResponse "Budget progress per category"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /budgets/{period} [get]
+func (api *API) ListProgress(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	period := ctx.Param("period")
+
+	progress, err := api.budgetsUseCase.ListProgress(userID, period)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListBudgetProgressResponse{
+		Data: ListBudgetProgressResponseData{
+			Budgets: progress,
+		},
+	})
+}
+
+// @Summary Update Budget By ID
+// @Description Update the amount or rollover of a budget
+// @Tags budgets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param budget_id path string true "budget ID"
+// @Param body body UpdateBudgetRequest true "Budget payload"
+// @Success 200 {object} UpdateBudgetResponse "Budget updated"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /budgets/{budget_id} [patch]
+func (api *API) Update(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("budget_id")
+	var body UpdateBudgetRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	if !utils.HasAtLeastOneField(body) {
+		apiErr := utils.NewHTTPError(
+			http.StatusBadRequest,
+			"At least one field must be provided for update",
+		)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	budget, err := api.budgetsUseCase.Update(id, userID, UpdateBudgetDTO{
+		Amount:   body.Amount,
+		Rollover: body.Rollover,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, UpdateBudgetResponse{
+		Data: UpdateBudgetResponseData{
+			Budget: budget,
+		},
+	})
+}
+
+// @Summary Delete Budget By ID
+// @Description Delete a budget
+// @Tags budgets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param budget_id path string true "budget ID"
+// @Success 204 "Budget deleted"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /budgets/{budget_id} [delete]
+func (api *API) DeleteByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("budget_id")
+
+	err := api.budgetsUseCase.DeleteByID(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/budgets"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBudgetsRepo struct {
+	mock.Mock
+}
+
+func (m *MockBudgetsRepo) Create(db utils.Executer, payload budgets.CreateBudgetDTO) (budgets.Budget, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(budgets.Budget), args.Error(1)
+}
+
+func (m *MockBudgetsRepo) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]budgets.Budget, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]budgets.Budget), args.Error(1)
+}
+
+func (m *MockBudgetsRepo) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBudgetsRepo) Update(db utils.Executer, id string, payload budgets.UpdateBudgetDTO) (budgets.Budget, error) {
+	args := m.Called(db, id, payload)
+	return args.Get(0).(budgets.Budget), args.Error(1)
+}
+
+func (m *MockBudgetsRepo) DeleteByID(db utils.Executer, id string) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
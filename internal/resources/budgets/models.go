@@ -0,0 +1,107 @@
+package budgets
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type CreateBudgetRequest struct {
+	CategoryID string  `json:"category_id" binding:"required"`
+	Period     *string `json:"period" binding:"omitempty,len=6,numeric"`
+	Amount     float64 `json:"amount" binding:"required,gt=0,lte=999999"`
+	Rollover   bool    `json:"rollover"`
+}
+
+type CreateBudgetResponse struct {
+	Data CreateBudgetResponseData `json:"data"`
+}
+
+type CreateBudgetResponseData struct {
+	Budget Budget `json:"budget"`
+}
+
+type UpdateBudgetRequest struct {
+	Amount   *float64 `json:"amount" binding:"omitempty,gt=0,lte=999999"`
+	Rollover *bool    `json:"rollover" binding:"omitempty"`
+}
+
+type UpdateBudgetResponse struct {
+	Data UpdateBudgetResponseData `json:"data"`
+}
+
+type UpdateBudgetResponseData struct {
+	Budget Budget `json:"budget"`
+}
+
+type ListBudgetsResponse struct {
+	Data  ListBudgetsResponseData `json:"data"`
+	Query utils.QueryMeta         `json:"query"`
+}
+
+type ListBudgetsResponseData struct {
+	Budgets []Budget `json:"budgets"`
+}
+
+type ListBudgetProgressResponse struct {
+	Data ListBudgetProgressResponseData `json:"data"`
+}
+
+type ListBudgetProgressResponseData struct {
+	Budgets []BudgetProgress `json:"budgets"`
+}
+
+// Planned against actual spending of a category in a period, available is
+// the planned amount plus what was rolled over from previous periods
+type BudgetProgress struct {
+	BudgetID       string  `json:"budget_id"`
+	CategoryID     string  `json:"category_id"`
+	CategoryName   string  `json:"category_name"`
+	CategoryColor  string  `json:"category_color"`
+	Period         string  `json:"period"`
+	Planned        float64 `json:"planned"`
+	RolledOver     float64 `json:"rolled_over"`
+	Available      float64 `json:"available"`
+	Spent          float64 `json:"spent"`
+	Remaining      float64 `json:"remaining"`
+	PercentageUsed float64 `json:"percentage_used"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type CreateBudgetDTO struct {
+	UserID     string
+	CategoryID string
+	Period     *string
+	Amount     float64
+	Rollover   bool
+}
+
+type UpdateBudgetDTO struct {
+	Amount   *float64
+	Rollover *bool
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Budgets table record, a budget without period is the monthly default of the category
+type Budget struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	CategoryID string    `json:"category_id"`
+	Period     *string   `json:"period"`
+	Amount     float64   `json:"amount"`
+	Rollover   bool      `json:"rollover"`
+	CreatedAt  time.Time `json:"created_at"`
+}
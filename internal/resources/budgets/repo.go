@@ -0,0 +1,157 @@
+package budgets
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/oklog/ulid/v2"
+)
+
+type BudgetsRepo interface {
+	Create(db utils.Executer, payload CreateBudgetDTO) (Budget, error)
+	List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Budget, error)
+	Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	Update(db utils.Executer, id string, payload UpdateBudgetDTO) (Budget, error)
+	DeleteByID(db utils.Executer, id string) error
+}
+
+type BudgetsRepoImpl struct {
+}
+
+func NewBudgetsRepo(db utils.Executer) BudgetsRepo {
+	return &BudgetsRepoImpl{}
+}
+
+func (r *BudgetsRepoImpl) Create(db utils.Executer, payload CreateBudgetDTO) (Budget, error) {
+	query, args, err := squirrel.Insert("budgets").
+		Columns("id", "user_id", "category_id", "period", "amount", "rollover").
+		Values(ulid.Make().String(), payload.UserID, payload.CategoryID, payload.Period, payload.Amount, payload.Rollover).
+		Suffix("RETURNING id, user_id, category_id, period, amount, rollover, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return Budget{}, err
+	}
+
+	var budget Budget
+	err = db.QueryRow(query, args...).Scan(
+		&budget.ID,
+		&budget.UserID,
+		&budget.CategoryID,
+		&budget.Period,
+		&budget.Amount,
+		&budget.Rollover,
+		&budget.CreatedAt,
+	)
+	return budget, err
+}
+
+func (r *BudgetsRepoImpl) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Budget, error) {
+	query := squirrel.Select("id", "user_id", "category_id", "period", "amount", "rollover", "created_at").
+		From("budgets").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := make([]Budget, 0)
+	for rows.Next() {
+		var budget Budget
+		if err := rows.Scan(
+			&budget.ID,
+			&budget.UserID,
+			&budget.CategoryID,
+			&budget.Period,
+			&budget.Amount,
+			&budget.Rollover,
+			&budget.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, nil
+}
+
+func (r *BudgetsRepoImpl) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("budgets").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *BudgetsRepoImpl) Update(db utils.Executer, id string, payload UpdateBudgetDTO) (Budget, error) {
+	query := squirrel.Update("budgets").Suffix("RETURNING id, user_id, category_id, period, amount, rollover, created_at")
+
+	if payload.Amount != nil {
+		query = query.Set("amount", payload.Amount)
+	}
+
+	if payload.Rollover != nil {
+		query = query.Set("rollover", payload.Rollover)
+	}
+
+	sql, args, err := query.
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return Budget{}, err
+	}
+
+	var budget Budget
+	err = db.QueryRow(sql, args...).Scan(
+		&budget.ID,
+		&budget.UserID,
+		&budget.CategoryID,
+		&budget.Period,
+		&budget.Amount,
+		&budget.Rollover,
+		&budget.CreatedAt,
+	)
+
+	return budget, err
+}
+
+func (r *BudgetsRepoImpl) DeleteByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("budgets").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
@@ -0,0 +1,277 @@
+package budgets
+
+import (
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type BudgetsUseCase interface {
+	Create(payload CreateBudgetDTO) (Budget, error)
+	List(filter *utils.QueryOptsBuilder) ([]Budget, error)
+	Count(filter *utils.QueryOptsBuilder) (int, error)
+	Update(id string, userID string, payload UpdateBudgetDTO) (Budget, error)
+	DeleteByID(id string, userID string) error
+	ListProgress(userID string, period string) ([]BudgetProgress, error)
+}
+
+type BudgetsUseCaseImpl struct {
+	repo              BudgetsRepo
+	categoriesUseCase categories.CategoriesUseCase
+	db                *sql.DB
+}
+
+func NewBudgetsUseCase(repo BudgetsRepo, categoriesUseCase categories.CategoriesUseCase, db *sql.DB) BudgetsUseCase {
+	return &BudgetsUseCaseImpl{
+		repo:              repo,
+		categoriesUseCase: categoriesUseCase,
+		db:                db,
+	}
+}
+
+func (uc *BudgetsUseCaseImpl) Create(payload CreateBudgetDTO) (Budget, error) {
+	if payload.Period != nil {
+		if _, err := time.Parse("200601", *payload.Period); err != nil {
+			return Budget{}, ErrInvalidPeriod
+		}
+	}
+
+	categoryExists, err := uc.categoriesUseCase.Count(utils.QueryOpts().
+		And("id", "eq", payload.CategoryID).
+		And("user_id", "eq", payload.UserID))
+	if err != nil {
+		return Budget{}, ErrFailedToCheckCategory
+	}
+
+	if categoryExists == 0 {
+		return Budget{}, ErrCategoryNotFound
+	}
+
+	// a nil period is compared with IS NULL, matching the monthly default
+	budgetExists, err := uc.repo.Count(uc.db, utils.QueryOpts().
+		And("category_id", "eq", payload.CategoryID).
+		And("period", "eq", payload.Period))
+	if err != nil {
+		return Budget{}, ErrFailedToCountBudgets
+	}
+
+	if budgetExists > 0 {
+		return Budget{}, ErrBudgetAlreadyExists
+	}
+
+	budget, err := uc.repo.Create(uc.db, payload)
+	if err != nil {
+		return Budget{}, ErrFailedToCreateBudget
+	}
+
+	return budget, nil
+}
+
+func (uc *BudgetsUseCaseImpl) List(filter *utils.QueryOptsBuilder) ([]Budget, error) {
+	budgets, err := uc.repo.List(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListBudgets
+	}
+	return budgets, nil
+}
+
+func (uc *BudgetsUseCaseImpl) Count(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.Count(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountBudgets
+	}
+	return count, nil
+}
+
+func (uc *BudgetsUseCaseImpl) Update(id string, userID string, payload UpdateBudgetDTO) (Budget, error) {
+	exists, err := uc.repo.Count(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return Budget{}, ErrFailedToCountBudgets
+	}
+
+	if exists == 0 {
+		return Budget{}, ErrBudgetNotFound
+	}
+
+	budget, err := uc.repo.Update(uc.db, id, payload)
+	if err != nil {
+		return Budget{}, ErrFailedToUpdateBudget
+	}
+
+	return budget, nil
+}
+
+func (uc *BudgetsUseCaseImpl) DeleteByID(id string, userID string) error {
+	exists, err := uc.repo.Count(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountBudgets
+	}
+
+	if exists == 0 {
+		return ErrBudgetNotFound
+	}
+
+	if err := uc.repo.DeleteByID(uc.db, id); err != nil {
+		return ErrFailedToDeleteBudget
+	}
+
+	return nil
+}
+
+func nextPeriod(period string) string {
+	date, _ := time.Parse("200601", period)
+	return date.AddDate(0, 1, 0).Format("200601")
+}
+
+// categoryBudgets holds every budget of a category to resolve which one
+// applies to a period, the explicit one or else the monthly default
+type categoryBudgets struct {
+	byPeriod       map[string]Budget
+	monthlyDefault *Budget
+}
+
+// defaultSince is the period the monthly default was created in, it only
+// applies from then on so it doesn't rewrite the budgets of past periods
+func (cb categoryBudgets) defaultSince() string {
+	return cb.monthlyDefault.CreatedAt.Format("200601")
+}
+
+func (cb categoryBudgets) applicable(period string) (Budget, bool) {
+	if budget, ok := cb.byPeriod[period]; ok {
+		return budget, true
+	}
+	if cb.monthlyDefault != nil && period >= cb.defaultSince() {
+		return *cb.monthlyDefault, true
+	}
+	return Budget{}, false
+}
+
+// firstPeriod is where rollover starts accumulating, nothing is carried from
+// before the category had its first budget
+func (cb categoryBudgets) firstPeriod() string {
+	first := ""
+	if cb.monthlyDefault != nil {
+		first = cb.defaultSince()
+	}
+	for period := range cb.byPeriod {
+		if first == "" || period < first {
+			first = period
+		}
+	}
+	return first
+}
+
+// rolledOver accumulates planned minus spent of the consecutive previous
+// periods whose budget has rollover, so both savings and overspending are carried
+func (cb categoryBudgets) rolledOver(period string, spent map[string]float64) float64 {
+	rolled := 0.0
+	for current := cb.firstPeriod(); current < period; current = nextPeriod(current) {
+		budget, ok := cb.applicable(current)
+		if !ok || !budget.Rollover {
+			rolled = 0
+			continue
+		}
+		rolled += budget.Amount - spent[current]
+	}
+	return rolled
+}
+
+func (uc *BudgetsUseCaseImpl) ListProgress(userID string, period string) ([]BudgetProgress, error) {
+	if _, err := time.Parse("200601", period); err != nil {
+		return nil, ErrInvalidPeriod
+	}
+
+	budgets, err := uc.repo.List(uc.db, utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return nil, ErrFailedToListBudgets
+	}
+
+	progress := make([]BudgetProgress, 0)
+	if len(budgets) == 0 {
+		return progress, nil
+	}
+
+	// expenses are negative amounts, so spending is the opposite of the category total
+	amounts, err := uc.categoriesUseCase.ListCategoryAmountPerPeriod(utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("period", "lte", period))
+	if err != nil {
+		return nil, ErrFailedToListCategorySpends
+	}
+
+	spent := make(map[string]map[string]float64)
+	for _, amount := range amounts {
+		if spent[amount.ID] == nil {
+			spent[amount.ID] = make(map[string]float64)
+		}
+		spent[amount.ID][amount.Period] = -amount.TotalAmount
+	}
+
+	userCategories, err := uc.categoriesUseCase.List(utils.QueryOpts().
+		And("user_id", "eq", userID).
+		OrderBy("name", "asc"))
+	if err != nil {
+		return nil, ErrFailedToCheckCategory
+	}
+
+	byCategory := make(map[string]*categoryBudgets)
+	for _, budget := range budgets {
+		cb, ok := byCategory[budget.CategoryID]
+		if !ok {
+			cb = &categoryBudgets{byPeriod: make(map[string]Budget)}
+			byCategory[budget.CategoryID] = cb
+		}
+		if budget.Period == nil {
+			cb.monthlyDefault = &budget
+		} else {
+			cb.byPeriod[*budget.Period] = budget
+		}
+	}
+
+	for _, category := range userCategories {
+		cb, ok := byCategory[category.ID]
+		if !ok {
+			continue
+		}
+
+		budget, ok := cb.applicable(period)
+		if !ok {
+			continue
+		}
+
+		rolledOver := 0.0
+		if budget.Rollover {
+			rolledOver = cb.rolledOver(period, spent[category.ID])
+		}
+
+		available := budget.Amount + rolledOver
+		categorySpent := spent[category.ID][period]
+		percentageUsed := 0.0
+		if available > 0 {
+			percentageUsed = math.Round(categorySpent/available*10000) / 100
+		}
+
+		progress = append(progress, BudgetProgress{
+			BudgetID:       budget.ID,
+			CategoryID:     category.ID,
+			CategoryName:   category.Name,
+			CategoryColor:  category.Color,
+			Period:         period,
+			Planned:        budget.Amount,
+			RolledOver:     rolledOver,
+			Available:      available,
+			Spent:          categorySpent,
+			Remaining:      available - categorySpent,
+			PercentageUsed: percentageUsed,
+		})
+	}
+
+	return progress, nil
+}
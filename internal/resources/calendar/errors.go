@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrFeedNotFound        = utils.NewHTTPError(http.StatusNotFound, "calendar feed not found")
+	ErrInvalidReminderTime = utils.NewHTTPError(http.StatusBadRequest, "reminder time must be in the format HH:MM")
+	ErrFailedToGetFeed     = utils.NewHTTPError(http.StatusInternalServerError, "failed to get calendar feed")
+	ErrFailedToSaveFeed    = utils.NewHTTPError(http.StatusInternalServerError, "failed to save calendar feed")
+	ErrFailedToDeleteFeed  = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete calendar feed")
+	ErrFailedToCreateToken = utils.NewHTTPError(http.StatusInternalServerError, "failed to create calendar feed token")
+	ErrFailedToListEntries = utils.NewHTTPError(http.StatusInternalServerError, "failed to list entries")
+)
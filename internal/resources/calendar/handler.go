@@ -0,0 +1,191 @@
+package calendar
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	calendarUseCase CalendarUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	categoriesUseCase := categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db)
+	return &API{
+		calendarUseCase: NewCalendarUseCase(NewCalendarRepo(db),
+			transactions.NewTransactionsUseCase(transactions.NewTransactionsRepo(db),
+				categoriesUseCase,
+				rules.NewRulesUseCase(rules.NewRulesRepo(db), categoriesUseCase, db),
+				db),
+			db),
+	}
+}
+
+// feedURL is the address calendar apps subscribe to, as the request reached
+// the api, behind a proxy that terminates TLS too
+func feedURL(ctx *gin.Context, token string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + ctx.Request.Host + "/api/v1/calendar/feeds/" + token + ".ics"
+}
+
+// @Summary Get the calendar feed
+// @Description Get the reminders of the calendar feed of the user, the token of its url is only shown when it's created
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} FeedResponse "Calendar feed"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Calendar feed not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /calendar/feed [get]
+func (api *API) GetFeed(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	feed, err := api.calendarUseCase.GetFeed(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, FeedResponse{
+		Data: FeedResponseData{
+			Feed: feed,
+		},
+	})
+}
+
+// @Summary Create or rotate the calendar feed token
+// @Description Create the calendar feed of the user, or give it a new secret url. The url of the previous token stops working right away, the reminders are kept
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} RotateTokenResponse "Feed token and url"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /calendar/feed/token [post]
+func (api *API) RotateToken(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	feed, token, err := api.calendarUseCase.RotateToken(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RotateTokenResponse{
+		Data: RotateTokenResponseData{
+			Feed:  feed,
+			Token: token,
+			URL:   feedURL(ctx, token),
+		},
+	})
+}
+
+// @Summary Update the calendar feed reminders
+// @Description Replace the reminders of every event of the feed, up to 5, each some days before the date at a time of the day. An empty list has no reminders
+// @Tags calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body UpdateFeedRequest true "Reminders payload"
+// @Success 200 {object} FeedResponse "Calendar feed updated"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Calendar feed not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /calendar/feed [put]
+func (api *API) UpdateFeed(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body UpdateFeedRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	feed, err := api.calendarUseCase.UpdateReminders(userID, body.Reminders)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, FeedResponse{
+		Data: FeedResponseData{
+			Feed: feed,
+		},
+	})
+}
+
+// @Summary Delete the calendar feed
+// @Description Delete the calendar feed of the user, its url stops working
+// @Tags calendar
+// @Security BearerAuth
+// @Success 204 "No content"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Calendar feed not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /calendar/feed [delete]
+func (api *API) DeleteFeed(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	err := api.calendarUseCase.DeleteFeed(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Subscribe to the calendar feed
+// @Description iCalendar feed of the upcoming expenses of the owner of the token, up to 12 months ahead and 1000 events, each an all-day event on its date with the amount and installment in the summary. The secret url is the only authentication, calendar apps can't send a token
+// @Tags calendar
+// @Produce plain
+// @Param file path string true "Feed token followed by .ics"
+// @Success 200 {file} file "iCalendar feed"
+// @Failure 404 {object} utils.HTTPError "Calendar feed not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /calendar/feeds/{file} [get]
+func (api *API) ServeFeed(ctx *gin.Context) {
+	token, ok := strings.CutSuffix(ctx.Param("file"), ".ics")
+
+	if !ok || token == "" {
+		apiErr := ErrFeedNotFound
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	data, err := api.calendarUseCase.Render(token, time.Now())
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=3600")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
@@ -0,0 +1,170 @@
+package calendar
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+// the longest line, in octets, before it's folded as RFC 5545 asks
+const maxLineLength = 75
+
+var reminderTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// ValidateReminders checks the time of every reminder, the days before are
+// checked when the request is bound
+func ValidateReminders(reminders []Reminder) error {
+	for _, reminder := range reminders {
+		if !reminderTimePattern.MatchString(reminder.Time) {
+			return ErrInvalidReminderTime
+		}
+	}
+	return nil
+}
+
+// icsWriter ends lines with CRLF and folds the long ones
+type icsWriter struct {
+	b strings.Builder
+}
+
+func (w *icsWriter) line(line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		// never split a character in two
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// a continuation starts with a space, which counts in its length
+		limit = maxLineLength - 1
+	}
+	w.b.WriteString(line + "\r\n")
+}
+
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// formatBRL writes an amount the way it's read in Brazil, like R$ 1.234,50
+func formatBRL(amount float64) string {
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	units := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("R$ %s,%02d", grouped.String(), cents%100)
+}
+
+func eventSummary(entry transactions.ViewEntry) string {
+	summary := fmt.Sprintf("%s · %s", entry.Name, formatBRL(entry.Amount))
+	if entry.TotalInstallments > 1 {
+		summary += fmt.Sprintf(" (%d/%d)", entry.Installment, entry.TotalInstallments)
+	}
+	return summary
+}
+
+func eventDescription(entry transactions.ViewEntry) string {
+	fields := []struct {
+		label string
+		value *string
+	}{
+		{"Category", entry.CategoryName},
+		{"Payee", entry.Payee},
+		{"Account", entry.Account},
+		{"Note", entry.Description},
+	}
+
+	lines := []string{}
+	for _, field := range fields {
+		if field.value != nil && strings.TrimSpace(*field.value) != "" {
+			lines = append(lines, field.label+": "+strings.TrimSpace(*field.value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reminderTrigger is the offset of a reminder from the start of the all-day
+// event, which is the midnight of its date
+func reminderTrigger(reminder Reminder) string {
+	clock, _ := time.Parse("15:04", reminder.Time)
+	minutes := clock.Hour()*60 + clock.Minute() - reminder.DaysBefore*24*60
+
+	sign := ""
+	if minutes < 0 {
+		sign = "-"
+		minutes = -minutes
+	}
+
+	if minutes == 0 {
+		return "PT0M"
+	}
+
+	trigger := sign + "PT"
+	if minutes/60 > 0 {
+		trigger += fmt.Sprintf("%dH", minutes/60)
+	}
+	if minutes%60 > 0 {
+		trigger += fmt.Sprintf("%dM", minutes%60)
+	}
+	return trigger
+}
+
+// WriteFeed writes each entry as an all-day event on its date with the
+// reminders of the feed. The stamp is when the feed was generated
+func WriteFeed(entries []transactions.ViewEntry, reminders []Reminder, stamp time.Time) []byte {
+	w := &icsWriter{}
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//open-wallet//calendar feed//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:Open Wallet")
+
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.ReferenceDate)
+		if err != nil {
+			continue
+		}
+
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + entry.ID + "@open-wallet")
+		w.line("DTSTAMP:" + dtstamp)
+		w.line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+		w.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+		w.line("SUMMARY:" + escapeText(eventSummary(entry)))
+		if description := eventDescription(entry); description != "" {
+			w.line("DESCRIPTION:" + escapeText(description))
+		}
+		w.line("TRANSP:TRANSPARENT")
+
+		for _, reminder := range reminders {
+			w.line("BEGIN:VALARM")
+			w.line("ACTION:DISPLAY")
+			w.line("DESCRIPTION:" + escapeText(eventSummary(entry)))
+			w.line("TRIGGER:" + reminderTrigger(reminder))
+			w.line("END:VALARM")
+		}
+
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+
+	return []byte(w.b.String())
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/calendar"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCalendarRepo struct {
+	mock.Mock
+}
+
+func (m *MockCalendarRepo) GetFeed(db utils.Executer, userID string) (calendar.Feed, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(calendar.Feed), args.Error(1)
+}
+
+func (m *MockCalendarRepo) GetFeedByTokenHash(db utils.Executer, tokenHash string) (calendar.Feed, error) {
+	args := m.Called(db, tokenHash)
+	return args.Get(0).(calendar.Feed), args.Error(1)
+}
+
+func (m *MockCalendarRepo) SaveToken(db utils.Executer, userID string, tokenHash string) (calendar.Feed, error) {
+	args := m.Called(db, userID, tokenHash)
+	return args.Get(0).(calendar.Feed), args.Error(1)
+}
+
+func (m *MockCalendarRepo) UpdateReminders(db utils.Executer, userID string, reminders []calendar.Reminder) (calendar.Feed, error) {
+	args := m.Called(db, userID, reminders)
+	return args.Get(0).(calendar.Feed), args.Error(1)
+}
+
+func (m *MockCalendarRepo) DeleteFeed(db utils.Executer, userID string) error {
+	args := m.Called(db, userID)
+	return args.Error(0)
+}
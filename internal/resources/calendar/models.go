@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"time"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type UpdateFeedRequest struct {
+	Reminders []Reminder `json:"reminders" binding:"required,max=5,dive"`
+}
+
+type FeedResponse struct {
+	Data FeedResponseData `json:"data"`
+}
+
+type FeedResponseData struct {
+	Feed Feed `json:"feed"`
+}
+
+// The token is only shown when it's created, the feed keeps just its hash
+type RotateTokenResponse struct {
+	Data RotateTokenResponseData `json:"data"`
+}
+
+type RotateTokenResponseData struct {
+	Feed  Feed   `json:"feed"`
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// Reminder of an event, the days before its date at a time of the day
+type Reminder struct {
+	DaysBefore int    `json:"days_before" binding:"gte=0,lte=30" example:"1"`
+	Time       string `json:"time" binding:"required" example:"09:00"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Calendar feeds table record
+type Feed struct {
+	UserID    string     `json:"user_id"`
+	Reminders []Reminder `json:"reminders"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt time.Time  `json:"rotated_at"`
+}
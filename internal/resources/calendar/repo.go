@@ -0,0 +1,123 @@
+package calendar
+
+import (
+	"encoding/json"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type CalendarRepo interface {
+	GetFeed(db utils.Executer, userID string) (Feed, error)
+	GetFeedByTokenHash(db utils.Executer, tokenHash string) (Feed, error)
+	SaveToken(db utils.Executer, userID string, tokenHash string) (Feed, error)
+	UpdateReminders(db utils.Executer, userID string, reminders []Reminder) (Feed, error)
+	DeleteFeed(db utils.Executer, userID string) error
+}
+
+type CalendarRepoImpl struct {
+}
+
+func NewCalendarRepo(db utils.Executer) CalendarRepo {
+	return &CalendarRepoImpl{}
+}
+
+var feedColumns = []string{"user_id", "reminders", "created_at", "rotated_at"}
+
+const feedReturning = "RETURNING user_id, reminders, created_at, rotated_at"
+
+type feedScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFeed(row feedScanner) (Feed, error) {
+	var feed Feed
+	var reminders []byte
+	if err := row.Scan(&feed.UserID, &reminders, &feed.CreatedAt, &feed.RotatedAt); err != nil {
+		return Feed{}, err
+	}
+
+	if err := json.Unmarshal(reminders, &feed.Reminders); err != nil {
+		return Feed{}, err
+	}
+
+	return feed, nil
+}
+
+func (r *CalendarRepoImpl) GetFeed(db utils.Executer, userID string) (Feed, error) {
+	query, args, err := squirrel.Select(feedColumns...).
+		From("calendar_feeds").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return scanFeed(db.QueryRow(query, args...))
+}
+
+func (r *CalendarRepoImpl) GetFeedByTokenHash(db utils.Executer, tokenHash string) (Feed, error) {
+	query, args, err := squirrel.Select(feedColumns...).
+		From("calendar_feeds").
+		Where(squirrel.Eq{"token_hash": tokenHash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return scanFeed(db.QueryRow(query, args...))
+}
+
+// SaveToken creates the feed of a user or replaces its token, keeping the
+// reminders already configured
+func (r *CalendarRepoImpl) SaveToken(db utils.Executer, userID string, tokenHash string) (Feed, error) {
+	query, args, err := squirrel.Insert("calendar_feeds").
+		Columns("user_id", "token_hash").
+		Values(userID, tokenHash).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET
+			token_hash = excluded.token_hash,
+			rotated_at = now()
+		` + feedReturning).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return scanFeed(db.QueryRow(query, args...))
+}
+
+func (r *CalendarRepoImpl) UpdateReminders(db utils.Executer, userID string, reminders []Reminder) (Feed, error) {
+	data, err := json.Marshal(reminders)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	query, args, err := squirrel.Update("calendar_feeds").
+		Set("reminders", data).
+		Where(squirrel.Eq{"user_id": userID}).
+		Suffix(feedReturning).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return scanFeed(db.QueryRow(query, args...))
+}
+
+func (r *CalendarRepoImpl) DeleteFeed(db utils.Executer, userID string) error {
+	query, args, err := squirrel.Delete("calendar_feeds").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query, args...)
+	return err
+}
@@ -0,0 +1,39 @@
+package calendar
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	group := router.Group("/api/v1/calendar")
+	{
+		group.GET("/feed",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.GetFeed)
+		group.PUT("/feed",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.UpdateFeed)
+		group.DELETE("/feed",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.DeleteFeed)
+		group.POST("/feed/token",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.RotateToken)
+		// calendar apps only have the url, the token in it is the authentication
+		group.GET("/feeds/:file", handler.ServeFeed)
+	}
+}
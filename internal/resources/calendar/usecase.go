@@ -0,0 +1,147 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type CalendarUseCase interface {
+	GetFeed(userID string) (Feed, error)
+	RotateToken(userID string) (Feed, string, error)
+	UpdateReminders(userID string, reminders []Reminder) (Feed, error)
+	DeleteFeed(userID string) error
+	Render(token string, now time.Time) ([]byte, error)
+}
+
+type CalendarUseCaseImpl struct {
+	repo                CalendarRepo
+	transactionsUseCase transactions.TransactionsUseCase
+	db                  *sql.DB
+}
+
+func NewCalendarUseCase(repo CalendarRepo, transactionsUseCase transactions.TransactionsUseCase, db *sql.DB) CalendarUseCase {
+	return &CalendarUseCaseImpl{
+		repo:                repo,
+		transactionsUseCase: transactionsUseCase,
+		db:                  db,
+	}
+}
+
+// TokenHash is what's stored of a feed token, a leaked database can't be used
+// to read anyone's feed
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newToken() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (uc *CalendarUseCaseImpl) GetFeed(userID string) (Feed, error) {
+	feed, err := uc.repo.GetFeed(uc.db, userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return Feed{}, ErrFeedNotFound
+	}
+
+	if err != nil {
+		return Feed{}, ErrFailedToGetFeed
+	}
+
+	return feed, nil
+}
+
+// RotateToken creates the feed of a user or gives it a new token, the url of
+// the previous one stops working right away
+func (uc *CalendarUseCaseImpl) RotateToken(userID string) (Feed, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return Feed{}, "", ErrFailedToCreateToken
+	}
+
+	feed, err := uc.repo.SaveToken(uc.db, userID, TokenHash(token))
+	if err != nil {
+		return Feed{}, "", ErrFailedToSaveFeed
+	}
+
+	return feed, token, nil
+}
+
+func (uc *CalendarUseCaseImpl) UpdateReminders(userID string, reminders []Reminder) (Feed, error) {
+	if err := ValidateReminders(reminders); err != nil {
+		return Feed{}, err
+	}
+
+	feed, err := uc.repo.UpdateReminders(uc.db, userID, reminders)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return Feed{}, ErrFeedNotFound
+	}
+
+	if err != nil {
+		return Feed{}, ErrFailedToSaveFeed
+	}
+
+	return feed, nil
+}
+
+func (uc *CalendarUseCaseImpl) DeleteFeed(userID string) error {
+	if _, err := uc.GetFeed(userID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteFeed(uc.db, userID); err != nil {
+		return ErrFailedToDeleteFeed
+	}
+
+	return nil
+}
+
+const (
+	// how far ahead the feed goes, long installments would fill it otherwise
+	feedHorizonMonths = 12
+	// most events a feed has, calendar apps poll it often
+	maxFeedEvents = 1000
+)
+
+// Render writes the feed of a token with the expenses from the day of now up
+// to feedHorizonMonths ahead, an unknown or rotated token is just a feed that
+// doesn't exist
+func (uc *CalendarUseCaseImpl) Render(token string, now time.Time) ([]byte, error) {
+	feed, err := uc.repo.GetFeedByTokenHash(uc.db, TokenHash(token))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFeedNotFound
+	}
+
+	if err != nil {
+		return nil, ErrFailedToGetFeed
+	}
+
+	entries, err := uc.transactionsUseCase.ListViewEntries(utils.QueryOpts().
+		And("user_id", "eq", feed.UserID).
+		And("amount", "lt", 0).
+		And("reference_date", "gte", now.Format("2006-01-02")).
+		And("reference_date", "lte", now.AddDate(0, feedHorizonMonths, 0).Format("2006-01-02")).
+		OrderBy("reference_date", "asc").
+		OrderBy("id", "asc").
+		Limit(maxFeedEvents))
+	if err != nil {
+		return nil, ErrFailedToListEntries
+	}
+
+	return WriteFeed(entries, feed.Reminders, now), nil
+}
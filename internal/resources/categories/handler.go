@@ -3,6 +3,7 @@ package categories
 import (
 	"database/sql"
 	"net/http"
+	"strconv"
 
 	"github.com/felipe1496/open-wallet/internal/utils"
 
@@ -70,6 +71,7 @@ func (api *API) Create(ctx *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
 // @Param order_by query string false "Sort field" example(name:asc,created_at:desc)
+// @Param cursor query string false "Keyset pagination cursor, send it empty to start paginating by cursor"
 // @Param filter query string false "Category filter"
 // @Param name query string false "A category name to filter by"
 // @Success 200 {object} ListCategoriesResponse "List of categories"
@@ -93,33 +95,20 @@ func (api *API) List(ctx *gin.Context) {
 		return
 	}
 
-	count, err := api.categoriesUseCase.Count(utils.ForCount(queryOpts))
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	categories, meta, err := utils.Paginate(categories, queryOpts, page, perPage, api.categoriesUseCase.Count)
 	if err != nil {
-		apiErr := utils.NewHTTPError(http.StatusInternalServerError, err.Error())
+		apiErr := utils.GetApiErr(err)
 		ctx.JSON(apiErr.StatusCode, apiErr)
 		return
 	}
 
-	page := ctx.GetInt("page")
-	perPage := ctx.GetInt("per_page")
-	nextPage := len(categories) > perPage
-	totalPages := (count + perPage - 1) / perPage
-
-	if nextPage {
-		categories = categories[:len(categories)-1]
-	}
-
 	ctx.JSON(http.StatusOK, ListCategoriesResponse{
 		Data: ListCategoriesResponseData{
 			Categories: categories,
 		},
-		Query: utils.QueryMeta{
-			NextPage:   nextPage,
-			Page:       page,
-			PerPage:    perPage,
-			TotalItems: count,
-			TotalPages: totalPages,
-		},
+		Query: meta,
 	})
 }
 
@@ -160,6 +149,7 @@ func (api *API) DeleteByID(ctx *gin.Context) {
 // @Param per_page query int false "Items per page" default(10)
 // @Param filter query string false "Category filter"
 // @Param order_by query string false "Sort field" example(name:asc,created_at:desc)
+// @Param cursor query string false "Keyset pagination cursor, send it empty to start paginating by cursor"
 // @Success 200 {object} ListCategoryAmountPerPeriodResponse "List of categories with amount per period"
 // @Failure 401 {object} utils.HTTPError "Unauthorized"
 // @Failure 500 {object} utils.HTTPError "Internal server error"
@@ -178,32 +168,99 @@ func (api *API) ListCategoryAmountPerPeriod(ctx *gin.Context) {
 		return
 	}
 
-	count, err := api.categoriesUseCase.CountCategoryAmountPerPeriod(utils.ForCount(queryOpts))
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	categories, meta, err := utils.Paginate(categories, queryOpts, page, perPage, api.categoriesUseCase.CountCategoryAmountPerPeriod)
 	if err != nil {
-		apiErr := err.(*utils.HTTPError)
+		apiErr := utils.GetApiErr(err)
 		ctx.JSON(apiErr.StatusCode, apiErr)
 		return
 	}
 
-	page := ctx.GetInt("page")
-	perPage := ctx.GetInt("per_page")
-	nextPage := len(categories) > perPage
-	totalPages := (count + perPage - 1) / perPage
-
-	if nextPage {
-		categories = categories[:len(categories)-1]
-	}
-
 	ctx.JSON(http.StatusOK, ListCategoryAmountPerPeriodResponse{
 		Data: ListCategoryAmountPerPeriodResponseData{
 			Categories: categories,
 		},
-		Query: utils.QueryMeta{
-			NextPage:   nextPage,
-			Page:       page,
-			PerPage:    perPage,
-			TotalItems: count,
-			TotalPages: totalPages,
+		Query: meta,
+	})
+}
+
+// @Summary List spending trends per category
+// @Description List the spending of each category in a range of periods, with month-over-month and year-over-year changes, rolling 3, 6 and 12 months averages and the deviation from the average of the chosen window
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "First period" example(202501)
+// @Param to query string true "Last period" example(202512)
+// @Param window query int false "Rolling average window used for the deviation, 3, 6 or 12" default(3)
+// @Success 200 {object} ListCategoryTrendsResponse "Trend of each category"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /categories/trends [get]
+func (api *API) ListCategoryTrends(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	// a window that isn't a number is left as zero for the use case to reject
+	window := 3
+	if value, ok := ctx.GetQuery("window"); ok {
+		window, _ = strconv.Atoi(value)
+	}
+
+	trends, err := api.categoriesUseCase.ListCategoryTrends(userID, ctx.Query("from"), ctx.Query("to"), window)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListCategoryTrendsResponse{
+		Data: ListCategoryTrendsResponseData{
+			Categories: trends,
+		},
+	})
+}
+
+// @Summary Suggest categories for a transaction name
+// @Description Rank the categories of the user for a transaction name, learned from the names of past categorized transactions
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name query string true "Transaction name" example(Drogaria São Paulo)
+// @Param limit query int false "Maximum number of suggestions" default(3)
+// @Success 200 {object} SuggestCategoriesResponse "Ranked suggestions"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /categories/suggest [get]
+func (api *API) Suggest(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	limit := 3
+	if value, ok := ctx.GetQuery("limit"); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			apiErr := utils.NewHTTPError(http.StatusBadRequest, "limit must be between 1 and 10")
+			ctx.JSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := api.categoriesUseCase.Suggest(userID, ctx.Query("name"), limit)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SuggestCategoriesResponse{
+		Data: SuggestCategoriesResponseData{
+			Suggestions: suggestions,
 		},
 	})
 }
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoriesRepo struct {
+	mock.Mock
+}
+
+func (m *MockCategoriesRepo) Create(db utils.Executer, payload categories.CreateCategoryDTO) (categories.Category, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(categories.Category), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]categories.Category, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]categories.Category), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) DeleteByID(db utils.Executer, id string) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoriesRepo) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) ListCategoryAmountPerPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) ([]categories.CategoryAmountPerPeriod, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]categories.CategoryAmountPerPeriod), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) CountCategoryAmountPerPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) FirstCategoryAmountPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (*string, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).(*string), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) Update(db utils.Executer, id string, payload categories.UpdateCategoryDTO) (categories.Category, error) {
+	args := m.Called(db, id, payload)
+	return args.Get(0).(categories.Category), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) ListCategorizedNames(db utils.Executer, userID string, limit int) ([]categories.CategorizedName, error) {
+	args := m.Called(db, userID, limit)
+	return args.Get(0).([]categories.CategorizedName), args.Error(1)
+}
+
+func (m *MockCategoriesRepo) GetCategorizedGeneration(db utils.Executer, userID string) (categories.CategorizedGeneration, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(categories.CategorizedGeneration), args.Error(1)
+}
@@ -42,6 +42,57 @@ type ListCategoryAmountPerPeriodResponseData struct {
 	Categories []CategoryAmountPerPeriod `json:"categories"`
 }
 
+type ListCategoryTrendsResponse struct {
+	Data ListCategoryTrendsResponseData `json:"data"`
+}
+
+type ListCategoryTrendsResponseData struct {
+	Categories []CategoryTrend `json:"categories"`
+}
+
+// Spending of a category in each period of a range, spending being the
+// opposite of the category total so expenses are positive
+type CategoryTrend struct {
+	ID      string                `json:"id"`
+	Name    string                `json:"name"`
+	Color   string                `json:"color"`
+	Periods []CategoryTrendPeriod `json:"periods"`
+}
+
+// Changes are nil when there is nothing to compare with. Rolling averages are
+// taken over the periods before this one, so deviation tells how far the
+// period is from the usual spending of the chosen window
+type CategoryTrendPeriod struct {
+	Period              string   `json:"period"`
+	Spent               float64  `json:"spent"`
+	MoMChange           *float64 `json:"mom_change"`
+	MoMPercentage       *float64 `json:"mom_percentage"`
+	YoYChange           *float64 `json:"yoy_change"`
+	YoYPercentage       *float64 `json:"yoy_percentage"`
+	RollingAverage3     float64  `json:"rolling_average_3"`
+	RollingAverage6     float64  `json:"rolling_average_6"`
+	RollingAverage12    float64  `json:"rolling_average_12"`
+	Deviation           float64  `json:"deviation"`
+	DeviationPercentage *float64 `json:"deviation_percentage"`
+}
+
+type SuggestCategoriesResponse struct {
+	Data SuggestCategoriesResponseData `json:"data"`
+}
+
+type SuggestCategoriesResponseData struct {
+	Suggestions []CategorySuggestion `json:"suggestions"`
+}
+
+// A category likely to fit a transaction name, confidences of the
+// suggestions of a name sum up to at most 1
+type CategorySuggestion struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Color      string  `json:"color"`
+	Confidence float64 `json:"confidence"`
+}
+
 type UpdateCategoryRequest struct {
 	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
 	Color *string `json:"color" binding:"omitempty,hexcolor,len=7"`
@@ -92,3 +143,23 @@ type CategoryAmountPerPeriod struct {
 	Period      string  `json:"period"`
 	TotalAmount float64 `json:"total_amount"`
 }
+
+// Name of a categorized transaction, the history suggestions learn from
+type CategorizedName struct {
+	CategoryID string
+	Name       string
+}
+
+// Fingerprint of the categorized transactions of a user, creating, changing or
+// deleting any of them changes it
+type CategorizedGeneration struct {
+	Count       int
+	LastUpdated *time.Time
+}
+
+func (g CategorizedGeneration) Equal(other CategorizedGeneration) bool {
+	if g.Count != other.Count || (g.LastUpdated == nil) != (other.LastUpdated == nil) {
+		return false
+	}
+	return g.LastUpdated == nil || g.LastUpdated.Equal(*other.LastUpdated)
+}
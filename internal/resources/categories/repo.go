@@ -14,7 +14,10 @@ type CategoriesRepo interface {
 	Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
 	ListCategoryAmountPerPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) ([]CategoryAmountPerPeriod, error)
 	CountCategoryAmountPerPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	FirstCategoryAmountPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (*string, error)
 	Update(db utils.Executer, id string, payload UpdateCategoryDTO) (Category, error)
+	ListCategorizedNames(db utils.Executer, userID string, limit int) ([]CategorizedName, error)
+	GetCategorizedGeneration(db utils.Executer, userID string) (CategorizedGeneration, error)
 }
 
 type CategoriesRepoImpl struct {
@@ -162,6 +165,30 @@ func (r *CategoriesRepoImpl) ListCategoryAmountPerPeriod(db utils.Executer, filt
 	return result, nil
 }
 
+// FirstCategoryAmountPeriod is the earliest period with amounts, nil when there are none
+func (r *CategoriesRepoImpl) FirstCategoryAmountPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (*string, error) {
+	query := squirrel.
+		Select("MIN(period)").
+		From("v_category_amount_per_period").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var period *string
+	err = db.QueryRow(sql, args...).Scan(&period)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
 func (r *CategoriesRepoImpl) CountCategoryAmountPerPeriod(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
 	countQuery := squirrel.
 		Select("COUNT(*)").
@@ -215,3 +242,58 @@ func (r *CategoriesRepoImpl) Update(db utils.Executer, id string, payload Update
 
 	return category, err
 }
+
+// ListCategorizedNames lists the names of the latest categorized transactions
+// of a user, only categories of the user itself are considered
+func (r *CategoriesRepoImpl) ListCategorizedNames(db utils.Executer, userID string, limit int) ([]CategorizedName, error) {
+	sql, args, err := squirrel.Select("t.category_id", "t.name").
+		From("transactions t").
+		Join("categories c ON c.id = t.category_id AND c.user_id = t.user_id").
+		Where(squirrel.Eq{"t.user_id": userID}).
+		OrderBy("t.created_at DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]CategorizedName, 0)
+	for rows.Next() {
+		var name CategorizedName
+		if err := rows.Scan(&name.CategoryID, &name.Name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// GetCategorizedGeneration reads the fingerprint of the transactions
+// ListCategorizedNames learns from, a deleted one lowers the count and a
+// created or changed one moves the last update
+func (r *CategoriesRepoImpl) GetCategorizedGeneration(db utils.Executer, userID string) (CategorizedGeneration, error) {
+	sql, args, err := squirrel.Select("COUNT(*)", "MAX(t.updated_at)").
+		From("transactions t").
+		Join("categories c ON c.id = t.category_id AND c.user_id = t.user_id").
+		Where(squirrel.Eq{"t.user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return CategorizedGeneration{}, err
+	}
+
+	var generation CategorizedGeneration
+	err = db.QueryRow(sql, args...).Scan(&generation.Count, &generation.LastUpdated)
+
+	return generation, err
+}
@@ -30,6 +30,12 @@ func Router(router *gin.Engine) {
 		group.DELETE("/:category_id",
 			middlewares.RequireAuthMiddleware(jwtService),
 			handler.DeleteByID)
+		group.GET("/suggest",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.Suggest)
+		group.GET("/trends",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ListCategoryTrends)
 		group.GET("/:period",
 			middlewares.RequireAuthMiddleware(jwtService),
 			middlewares.QueryOptsMiddleware(),
@@ -2,8 +2,16 @@ package categories
 
 import (
 	"database/sql"
+	"math"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
 	"github.com/felipe1496/open-wallet/internal/utils"
 )
 
@@ -15,6 +23,8 @@ type CategoriesUseCase interface {
 	ListCategoryAmountPerPeriod(filter *utils.QueryOptsBuilder) ([]CategoryAmountPerPeriod, error)
 	CountCategoryAmountPerPeriod(filter *utils.QueryOptsBuilder) (int, error)
 	Update(id string, payload UpdateCategoryDTO) (Category, error)
+	ListCategoryTrends(userID string, from string, to string, window int) ([]CategoryTrend, error)
+	Suggest(userID string, name string, limit int) ([]CategorySuggestion, error)
 }
 
 type CategoriesUseCaseImpl struct {
@@ -48,13 +58,13 @@ func (uc *CategoriesUseCaseImpl) List(filter *utils.QueryOptsBuilder) ([]Categor
 }
 
 func (uc *CategoriesUseCaseImpl) DeleteByID(id string) error {
-	exists, err := uc.repo.Count(uc.db, utils.QueryOpts().And("id", "eq", id))
+	exists, err := uc.repo.List(uc.db, utils.QueryOpts().And("id", "eq", id))
 
 	if err != nil {
 		return utils.NewHTTPError(http.StatusInternalServerError, "failed to delete category")
 	}
 
-	if exists == 0 {
+	if len(exists) == 0 {
 		return utils.NewHTTPError(http.StatusNotFound, "category not found")
 	}
 
@@ -114,3 +124,382 @@ func (uc *CategoriesUseCaseImpl) Update(id string, payload UpdateCategoryDTO) (C
 
 	return category, nil
 }
+
+// rolling average windows, in months
+var trendWindows = []int{3, 6, 12}
+
+func shiftPeriod(period string, months int) string {
+	date, _ := time.Parse("200601", period)
+	return date.AddDate(0, months, 0).Format("200601")
+}
+
+func roundPercentage(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// change returns the difference from a previous value and its percentage,
+// the percentage is nil when the previous value is zero
+func change(current float64, previous float64) (*float64, *float64) {
+	diff := current - previous
+	if previous == 0 {
+		return &diff, nil
+	}
+	percentage := roundPercentage(diff / math.Abs(previous) * 100)
+	return &diff, &percentage
+}
+
+// BuildCategoryTrends computes the trend of each category from its amounts
+// per period, which must include the year before from for the yearly change
+// and rolling averages. Periods before firstPeriod, the first one with
+// entries, are not compared with nor averaged, while periods without amounts
+// count as zero
+func BuildCategoryTrends(amounts []CategoryAmountPerPeriod, firstPeriod string, from string, to string, window int) []CategoryTrend {
+	trends := make([]CategoryTrend, 0)
+	spent := make(map[string]map[string]float64)
+	index := make(map[string]int)
+	for _, amount := range amounts {
+		if _, ok := index[amount.ID]; !ok {
+			index[amount.ID] = len(trends)
+			spent[amount.ID] = make(map[string]float64)
+			trends = append(trends, CategoryTrend{
+				ID:      amount.ID,
+				Name:    amount.Name,
+				Color:   amount.Color,
+				Periods: make([]CategoryTrendPeriod, 0),
+			})
+		}
+		spent[amount.ID][amount.Period] = -amount.TotalAmount
+	}
+
+	for i := range trends {
+		categorySpent := spent[trends[i].ID]
+
+		for period := from; period <= to; period = shiftPeriod(period, 1) {
+			trendPeriod := CategoryTrendPeriod{
+				Period: period,
+				Spent:  categorySpent[period],
+			}
+
+			if previous := shiftPeriod(period, -1); previous >= firstPeriod {
+				trendPeriod.MoMChange, trendPeriod.MoMPercentage = change(trendPeriod.Spent, categorySpent[previous])
+			}
+
+			if lastYear := shiftPeriod(period, -12); lastYear >= firstPeriod {
+				trendPeriod.YoYChange, trendPeriod.YoYPercentage = change(trendPeriod.Spent, categorySpent[lastYear])
+			}
+
+			averages := make(map[int]float64)
+			for _, months := range trendWindows {
+				total, count := 0.0, 0
+				for back := 1; back <= months; back++ {
+					previous := shiftPeriod(period, -back)
+					if previous < firstPeriod {
+						break
+					}
+					total += categorySpent[previous]
+					count++
+				}
+				if count > 0 {
+					averages[months] = total / float64(count)
+				}
+			}
+
+			trendPeriod.RollingAverage3 = averages[3]
+			trendPeriod.RollingAverage6 = averages[6]
+			trendPeriod.RollingAverage12 = averages[12]
+
+			if average, ok := averages[window]; ok {
+				_, trendPeriod.DeviationPercentage = change(trendPeriod.Spent, average)
+				trendPeriod.Deviation = trendPeriod.Spent - average
+			}
+
+			trends[i].Periods = append(trends[i].Periods, trendPeriod)
+		}
+	}
+
+	return trends
+}
+
+func (uc *CategoriesUseCaseImpl) ListCategoryTrends(userID string, from string, to string, window int) ([]CategoryTrend, error) {
+	if !slices.Contains(trendWindows, window) {
+		return nil, utils.NewHTTPError(http.StatusBadRequest, "window must be 3, 6 or 12")
+	}
+
+	if err := summary.ValidatePeriodRange(from, to); err != nil {
+		return nil, err
+	}
+
+	// the first period with entries is where comparisons start, so they don't
+	// start from the gap before the user had any
+	firstPeriod, err := uc.repo.FirstCategoryAmountPeriod(uc.db, utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("period", "lte", to))
+	if err != nil {
+		return nil, utils.NewHTTPError(http.StatusInternalServerError, "failed to list category amounts per period")
+	}
+
+	if firstPeriod == nil {
+		return make([]CategoryTrend, 0), nil
+	}
+
+	// a year before the range covers the yearly change and the longest average
+	amounts, err := uc.repo.ListCategoryAmountPerPeriod(uc.db, utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("period", "gte", shiftPeriod(from, -12)).
+		And("period", "lte", to).
+		OrderBy("name", "asc").
+		OrderBy("id", "asc").
+		OrderBy("period", "asc"))
+	if err != nil {
+		return nil, utils.NewHTTPError(http.StatusInternalServerError, "failed to list category amounts per period")
+	}
+
+	return BuildCategoryTrends(amounts, *firstPeriod, from, to, window), nil
+}
+
+// how many of the latest categorized transactions suggestions learn from
+const suggestionHistory = 5000
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// tokenize splits a transaction name in lowercase words without accents,
+// numbers and single letters are dropped since they are usually dates,
+// installments or card digits that say nothing about the category
+func tokenize(name string) []string {
+	words := strings.FieldsFunc(accentReplacer.Replace(strings.ToLower(name)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || strings.IndexFunc(word, unicode.IsLetter) == -1 {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// NormalizeName lowercases a name and drops accents, punctuation and the
+// words tokenize ignores, so names written differently can be compared
+func NormalizeName(name string) string {
+	return strings.Join(tokenize(name), " ")
+}
+
+// suggestionModel holds the counts of a multinomial naive Bayes over the
+// tokens of the names already categorized
+type suggestionModel struct {
+	names       int
+	docs        map[string]int
+	tokenCounts map[string]map[string]int
+	totalTokens map[string]int
+	vocabulary  map[string]bool
+}
+
+func trainSuggestionModel(history []CategorizedName) suggestionModel {
+	model := suggestionModel{
+		names:       len(history),
+		docs:        make(map[string]int),
+		tokenCounts: make(map[string]map[string]int),
+		totalTokens: make(map[string]int),
+		vocabulary:  make(map[string]bool),
+	}
+
+	for _, categorized := range history {
+		model.docs[categorized.CategoryID]++
+		if model.tokenCounts[categorized.CategoryID] == nil {
+			model.tokenCounts[categorized.CategoryID] = make(map[string]int)
+		}
+		for _, token := range tokenize(categorized.Name) {
+			model.tokenCounts[categorized.CategoryID][token]++
+			model.totalTokens[categorized.CategoryID]++
+			model.vocabulary[token] = true
+		}
+	}
+
+	return model
+}
+
+func (m suggestionModel) suggest(name string) []CategorySuggestion {
+	suggestions := make([]CategorySuggestion, 0)
+
+	tokens := make([]string, 0)
+	for _, token := range tokenize(name) {
+		if m.vocabulary[token] {
+			tokens = append(tokens, token)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return suggestions
+	}
+
+	logPosteriors := make(map[string]float64)
+	maxLog := math.Inf(-1)
+	for categoryID, count := range m.docs {
+		logPosterior := math.Log(float64(count) / float64(m.names))
+		for _, token := range tokens {
+			likelihood := float64(m.tokenCounts[categoryID][token]+1) / float64(m.totalTokens[categoryID]+len(m.vocabulary))
+			logPosterior += math.Log(likelihood)
+		}
+		logPosteriors[categoryID] = logPosterior
+		maxLog = math.Max(maxLog, logPosterior)
+	}
+
+	// softmax shifted by the highest posterior so exponentials don't underflow
+	sum := 0.0
+	for _, logPosterior := range logPosteriors {
+		sum += math.Exp(logPosterior - maxLog)
+	}
+
+	for categoryID, logPosterior := range logPosteriors {
+		suggestions = append(suggestions, CategorySuggestion{
+			ID:         categoryID,
+			Confidence: math.Round(math.Exp(logPosterior-maxLog)/sum*10000) / 10000,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+
+	return suggestions
+}
+
+// SuggestFromHistory ranks categories for a name with a multinomial naive
+// Bayes over the tokens of the names already categorized, with Laplace
+// smoothing. Confidences are the normalized posteriors, and nothing is
+// suggested when no token of the name was ever seen
+func SuggestFromHistory(history []CategorizedName, name string) []CategorySuggestion {
+	return trainSuggestionModel(history).suggest(name)
+}
+
+// how many models are kept in memory, the least recently used is dropped
+// to make room for a new one
+const maxSuggestionModels = 1000
+
+// cachedSuggestionModel is a model with the generation of the history it was
+// trained from, lastUsed orders the models for eviction
+type cachedSuggestionModel struct {
+	model      suggestionModel
+	generation CategorizedGeneration
+	lastUsed   uint64
+}
+
+// models are shared by every use case instance, each use ticks the clock
+var suggestionModels = struct {
+	sync.Mutex
+	byUser map[string]*cachedSuggestionModel
+	clock  uint64
+}{
+	byUser: make(map[string]*cachedSuggestionModel),
+}
+
+// userSuggestionModel returns the cached model of a user while the generation
+// in the database is the one it was trained from, so writes made by any
+// instance retrain it. The generation is read before the history, so a model
+// is never newer than the generation it's cached with
+func (uc *CategoriesUseCaseImpl) userSuggestionModel(userID string) (suggestionModel, error) {
+	generation, err := uc.repo.GetCategorizedGeneration(uc.db, userID)
+	if err != nil {
+		return suggestionModel{}, err
+	}
+
+	suggestionModels.Lock()
+	cached, ok := suggestionModels.byUser[userID]
+	if ok && cached.generation.Equal(generation) {
+		suggestionModels.clock++
+		cached.lastUsed = suggestionModels.clock
+		suggestionModels.Unlock()
+		return cached.model, nil
+	}
+	suggestionModels.Unlock()
+
+	history, err := uc.repo.ListCategorizedNames(uc.db, userID, suggestionHistory)
+	if err != nil {
+		return suggestionModel{}, err
+	}
+
+	model := trainSuggestionModel(history)
+
+	suggestionModels.Lock()
+	defer suggestionModels.Unlock()
+
+	if _, ok := suggestionModels.byUser[userID]; !ok && len(suggestionModels.byUser) >= maxSuggestionModels {
+		evicted := ""
+		for cachedUserID, cached := range suggestionModels.byUser {
+			if evicted == "" || cached.lastUsed < suggestionModels.byUser[evicted].lastUsed {
+				evicted = cachedUserID
+			}
+		}
+		delete(suggestionModels.byUser, evicted)
+	}
+
+	suggestionModels.clock++
+	suggestionModels.byUser[userID] = &cachedSuggestionModel{
+		model:      model,
+		generation: generation,
+		lastUsed:   suggestionModels.clock,
+	}
+
+	return model, nil
+}
+
+// Suggest ranks categories with the model of the user, trained once from
+// the history and kept while the history doesn't change
+func (uc *CategoriesUseCaseImpl) Suggest(userID string, name string, limit int) ([]CategorySuggestion, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, utils.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+
+	if limit < 1 || limit > 10 {
+		return nil, utils.NewHTTPError(http.StatusBadRequest, "limit must be between 1 and 10")
+	}
+
+	model, err := uc.userSuggestionModel(userID)
+	if err != nil {
+		return nil, utils.NewHTTPError(http.StatusInternalServerError, "failed to list categorized transactions")
+	}
+
+	suggestions := model.suggest(name)
+	if len(suggestions) == 0 {
+		return suggestions, nil
+	}
+
+	userCategories, err := uc.repo.List(uc.db, utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return nil, utils.NewHTTPError(http.StatusInternalServerError, "failed to list categories")
+	}
+
+	byID := make(map[string]Category)
+	for _, category := range userCategories {
+		byID[category.ID] = category
+	}
+
+	// the model may still know categories deleted after it was trained
+	ranked := make([]CategorySuggestion, 0, limit)
+	for _, suggestion := range suggestions {
+		category, ok := byID[suggestion.ID]
+		if !ok {
+			continue
+		}
+		suggestion.Name = category.Name
+		suggestion.Color = category.Color
+		ranked = append(ranked, suggestion)
+		if len(ranked) == limit {
+			break
+		}
+	}
+
+	return ranked, nil
+}
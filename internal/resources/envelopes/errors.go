@@ -0,0 +1,23 @@
+package envelopes
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrEnvelopeModeDisabled     = utils.NewHTTPError(http.StatusConflict, "envelope budgeting is not enabled")
+	ErrPeriodBeforeEnvelopeMode = utils.NewHTTPError(http.StatusBadRequest, "period is before envelope budgeting was enabled")
+	ErrInvalidPeriod            = utils.NewHTTPError(http.StatusBadRequest, "period must be in the YYYYMM format")
+	ErrSameEnvelope             = utils.NewHTTPError(http.StatusBadRequest, "from_category_id and to_category_id must be different envelopes")
+	ErrNotEnoughToAssign        = utils.NewHTTPError(http.StatusBadRequest, "amount is more than the available to assign of the period or a later one")
+	ErrNotEnoughInEnvelope      = utils.NewHTTPError(http.StatusBadRequest, "amount is more than the balance of the source envelope in the period or a later one")
+	ErrCategoryNotFound         = utils.NewHTTPError(http.StatusNotFound, "category not found")
+	ErrFailedToFetchUser        = utils.NewHTTPError(http.StatusInternalServerError, "failed to fetch user")
+	ErrFailedToSetMode          = utils.NewHTTPError(http.StatusInternalServerError, "failed to set envelope budgeting mode")
+	ErrFailedToCheckCategory    = utils.NewHTTPError(http.StatusInternalServerError, "failed to check if category exists")
+	ErrFailedToCreateMovement   = utils.NewHTTPError(http.StatusInternalServerError, "failed to create envelope movement")
+	ErrFailedToCheckBalance     = utils.NewHTTPError(http.StatusInternalServerError, "failed to check envelope balance")
+	ErrFailedToListEnvelopes    = utils.NewHTTPError(http.StatusInternalServerError, "failed to list envelopes")
+)
@@ -0,0 +1,219 @@
+package envelopes
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/users"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	envelopesUseCase EnvelopesUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		envelopesUseCase: NewEnvelopesUseCase(NewEnvelopesRepo(db),
+			categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+			users.NewUsersUseCase(users.NewUsersRepo(db)),
+			db),
+	}
+}
+
+// @Summary Set envelope budgeting mode
+// @Description Enable envelope budgeting from a period (the current one by default) or disable it
+// @Tags envelopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body SetModeRequest true "Mode payload"
+// @Success 200 {object} SetModeResponse "Mode updated"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /envelopes/mode [put]
+func (api *API) SetMode(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body SetModeRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	mode, err := api.envelopesUseCase.SetMode(userID, *body.Enabled, body.Since)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SetModeResponse{
+		Data: SetModeResponseData{
+			Mode: mode,
+		},
+	})
+}
+
+// @Summary Assign money to an envelope
+// @Description Move money from the available to assign pool into a category envelope, up to what is available at the end of the period and of every later one
+// @Tags envelopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body AssignRequest true "Assignment payload"
+// @Success 201 {object} CreateMovementResponse "Money assigned"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Category not found"
+// @Failure 409 {object} utils.HTTPError "Envelope budgeting disabled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /envelopes/assign [post]
+func (api *API) Assign(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body AssignRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	movement, err := api.envelopesUseCase.Assign(CreateMovementDTO{
+		UserID:       userID,
+		Period:       body.Period,
+		ToCategoryID: &body.CategoryID,
+		Amount:       body.Amount,
+		Note:         body.Note,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateMovementResponse{
+		Data: CreateMovementResponseData{
+			Movement: movement,
+		},
+	})
+}
+
+// @Summary Move money between envelopes
+// @Description Move money from one category envelope to another, up to the balance of the source envelope at the end of the period and of every later one
+// @Tags envelopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body MoveRequest true "Movement payload"
+// @Success 201 {object} CreateMovementResponse "Money moved"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Category not found"
+// @Failure 409 {object} utils.HTTPError "Envelope budgeting disabled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /envelopes/move [post]
+func (api *API) Move(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body MoveRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	movement, err := api.envelopesUseCase.Move(CreateMovementDTO{
+		UserID:         userID,
+		Period:         body.Period,
+		FromCategoryID: &body.FromCategoryID,
+		ToCategoryID:   &body.ToCategoryID,
+		Amount:         body.Amount,
+		Note:           body.Note,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateMovementResponse{
+		Data: CreateMovementResponseData{
+			Movement: movement,
+		},
+	})
+}
+
+// @Summary List envelopes of a period
+// @Description List the available to assign pool and the assigned, activity and running balance of each category envelope
+// @Tags envelopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param period path string true "period" example(202501)
+// @Success 200 {object} ListEnvelopesResponse "Envelopes of the period"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 409 {object} utils.HTTPError "Envelope budgeting disabled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /envelopes/{period} [get]
+func (api *API) ListEnvelopes(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	period := ctx.Param("period")
+
+	overview, err := api.envelopesUseCase.ListEnvelopes(userID, period)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListEnvelopesResponse{
+		Data: overview,
+	})
+}
+
+// @Summary List overspent envelopes of a period
+// @Description List the category envelopes whose running balance is negative at the end of a period
+// @Tags envelopes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param period path string true "period" example(202501)
+// @Success 200 {object} ListOverspendingResponse "Overspent envelopes"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 409 {object} utils.HTTPError "Envelope budgeting disabled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /envelopes/{period}/overspending [get]
+func (api *API) ListOverspending(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	period := ctx.Param("period")
+
+	report, err := api.envelopesUseCase.ListOverspending(userID, period)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListOverspendingResponse{
+		Data: report,
+	})
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/envelopes"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEnvelopesRepo struct {
+	mock.Mock
+}
+
+func (m *MockEnvelopesRepo) LockUser(db utils.Executer, userID string) error {
+	args := m.Called(db, userID)
+	return args.Error(0)
+}
+
+func (m *MockEnvelopesRepo) CreateMovement(db utils.Executer, payload envelopes.CreateMovementDTO) (envelopes.EnvelopeMovement, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(envelopes.EnvelopeMovement), args.Error(1)
+}
+
+func (m *MockEnvelopesRepo) SumMovements(db utils.Executer, userID string, from string, to string) ([]envelopes.EnvelopeAmount, error) {
+	args := m.Called(db, userID, from, to)
+	return args.Get(0).([]envelopes.EnvelopeAmount), args.Error(1)
+}
+
+func (m *MockEnvelopesRepo) SumActivity(db utils.Executer, userID string, from string, to string) ([]envelopes.EnvelopeAmount, error) {
+	args := m.Called(db, userID, from, to)
+	return args.Get(0).([]envelopes.EnvelopeAmount), args.Error(1)
+}
@@ -0,0 +1,121 @@
+package envelopes
+
+import "time"
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type SetModeRequest struct {
+	Enabled *bool   `json:"enabled" binding:"required"`
+	Since   *string `json:"since" binding:"omitempty,len=6,numeric"`
+}
+
+type SetModeResponse struct {
+	Data SetModeResponseData `json:"data"`
+}
+
+type SetModeResponseData struct {
+	Mode EnvelopeMode `json:"mode"`
+}
+
+type AssignRequest struct {
+	CategoryID string  `json:"category_id" binding:"required"`
+	Period     string  `json:"period" binding:"required,len=6,numeric"`
+	Amount     float64 `json:"amount" binding:"required,gt=0,lte=999999"`
+	Note       *string `json:"note" binding:"omitempty,max=400"`
+}
+
+type MoveRequest struct {
+	FromCategoryID string  `json:"from_category_id" binding:"required"`
+	ToCategoryID   string  `json:"to_category_id" binding:"required,nefield=FromCategoryID"`
+	Period         string  `json:"period" binding:"required,len=6,numeric"`
+	Amount         float64 `json:"amount" binding:"required,gt=0,lte=999999"`
+	Note           *string `json:"note" binding:"omitempty,max=400"`
+}
+
+type CreateMovementResponse struct {
+	Data CreateMovementResponseData `json:"data"`
+}
+
+type CreateMovementResponseData struct {
+	Movement EnvelopeMovement `json:"movement"`
+}
+
+type ListEnvelopesResponse struct {
+	Data EnvelopesOverview `json:"data"`
+}
+
+type ListOverspendingResponse struct {
+	Data OverspendingReport `json:"data"`
+}
+
+type EnvelopeMode struct {
+	Enabled bool    `json:"enabled"`
+	Since   *string `json:"since"`
+}
+
+// Money of a category envelope, balance runs across periods while assigned
+// and activity are only the ones of the requested period
+type Envelope struct {
+	CategoryID    string  `json:"category_id"`
+	CategoryName  string  `json:"category_name"`
+	CategoryColor string  `json:"category_color"`
+	Assigned      float64 `json:"assigned"`
+	Activity      float64 `json:"activity"`
+	Balance       float64 `json:"balance"`
+	Overspent     bool    `json:"overspent"`
+}
+
+type EnvelopesOverview struct {
+	Period            string     `json:"period"`
+	AvailableToAssign float64    `json:"available_to_assign"`
+	Envelopes         []Envelope `json:"envelopes"`
+}
+
+type OverspendingReport struct {
+	Period         string     `json:"period"`
+	TotalOverspent float64    `json:"total_overspent"`
+	Envelopes      []Envelope `json:"envelopes"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// A nil category is the available to assign pool
+type CreateMovementDTO struct {
+	UserID         string
+	Period         string
+	FromCategoryID *string
+	ToCategoryID   *string
+	Amount         float64
+	Note           *string
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Envelope movements table record
+type EnvelopeMovement struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Period         string    `json:"period"`
+	FromCategoryID *string   `json:"from_category_id"`
+	ToCategoryID   *string   `json:"to_category_id"`
+	Amount         float64   `json:"amount"`
+	Note           *string   `json:"note"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Amount that entered (or left, when negative) an envelope in a period, a nil
+// category is the available to assign pool
+type EnvelopeAmount struct {
+	CategoryID *string
+	Period     string
+	Amount     float64
+}
@@ -0,0 +1,120 @@
+package envelopes
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/oklog/ulid/v2"
+)
+
+// Every movement is both an inflow into its destination and an outflow of its source
+const sumMovementsQuery = `
+SELECT category_id, period, SUM(amount)
+FROM (
+    SELECT to_category_id AS category_id, period, amount
+    FROM envelope_movements
+    WHERE user_id = $1 AND period BETWEEN $2 AND $3
+    UNION ALL
+    SELECT from_category_id AS category_id, period, -amount
+    FROM envelope_movements
+    WHERE user_id = $1 AND period BETWEEN $2 AND $3
+) m
+GROUP BY category_id, period`
+
+// Income and uncategorized expenses go straight to the pool, categorized
+// expenses are taken from the envelope of their category
+const sumActivityQuery = `
+SELECT
+    CASE WHEN category = 'income' THEN NULL ELSE category_id END AS envelope_id,
+    period,
+    SUM(amount)
+FROM v_entries
+WHERE user_id = $1 AND period BETWEEN $2 AND $3
+GROUP BY envelope_id, period`
+
+type EnvelopesRepo interface {
+	LockUser(db utils.Executer, userID string) error
+	CreateMovement(db utils.Executer, payload CreateMovementDTO) (EnvelopeMovement, error)
+	SumMovements(db utils.Executer, userID string, from string, to string) ([]EnvelopeAmount, error)
+	SumActivity(db utils.Executer, userID string, from string, to string) ([]EnvelopeAmount, error)
+}
+
+type EnvelopesRepoImpl struct {
+}
+
+func NewEnvelopesRepo(db utils.Executer) EnvelopesRepo {
+	return &EnvelopesRepoImpl{}
+}
+
+// LockUser holds the row of a user until the transaction ends
+func (r *EnvelopesRepoImpl) LockUser(db utils.Executer, userID string) error {
+	query, args, err := squirrel.Select("id").
+		From("users").
+		Where(squirrel.Eq{"id": userID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var id string
+	return db.QueryRow(query, args...).Scan(&id)
+}
+
+func (r *EnvelopesRepoImpl) CreateMovement(db utils.Executer, payload CreateMovementDTO) (EnvelopeMovement, error) {
+	query, args, err := squirrel.Insert("envelope_movements").
+		Columns("id", "user_id", "period", "from_category_id", "to_category_id", "amount", "note").
+		Values(ulid.Make().String(), payload.UserID, payload.Period, payload.FromCategoryID, payload.ToCategoryID, payload.Amount, payload.Note).
+		Suffix("RETURNING id, user_id, period, from_category_id, to_category_id, amount, note, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	var movement EnvelopeMovement
+	err = db.QueryRow(query, args...).Scan(
+		&movement.ID,
+		&movement.UserID,
+		&movement.Period,
+		&movement.FromCategoryID,
+		&movement.ToCategoryID,
+		&movement.Amount,
+		&movement.Note,
+		&movement.CreatedAt,
+	)
+	return movement, err
+}
+
+func (r *EnvelopesRepoImpl) SumMovements(db utils.Executer, userID string, from string, to string) ([]EnvelopeAmount, error) {
+	return listEnvelopeAmounts(db, sumMovementsQuery, userID, from, to)
+}
+
+func (r *EnvelopesRepoImpl) SumActivity(db utils.Executer, userID string, from string, to string) ([]EnvelopeAmount, error) {
+	return listEnvelopeAmounts(db, sumActivityQuery, userID, from, to)
+}
+
+func listEnvelopeAmounts(db utils.Executer, query string, args ...any) ([]EnvelopeAmount, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	amounts := make([]EnvelopeAmount, 0)
+	for rows.Next() {
+		var amount EnvelopeAmount
+		if err := rows.Scan(
+			&amount.CategoryID,
+			&amount.Period,
+			&amount.Amount,
+		); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount)
+	}
+
+	return amounts, rows.Err()
+}
@@ -0,0 +1,40 @@
+package envelopes
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	group := router.Group("/api/v1/envelopes")
+	{
+		group.PUT("/mode",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.SetMode)
+		group.POST("/assign",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.Assign)
+		group.POST("/move",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.Move)
+		group.GET("/:period",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ListEnvelopes)
+		group.GET("/:period/overspending",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ListOverspending)
+	}
+}
@@ -0,0 +1,307 @@
+package envelopes
+
+import (
+	"database/sql"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/users"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type EnvelopesUseCase interface {
+	SetMode(userID string, enabled bool, since *string) (EnvelopeMode, error)
+	Assign(payload CreateMovementDTO) (EnvelopeMovement, error)
+	Move(payload CreateMovementDTO) (EnvelopeMovement, error)
+	ListEnvelopes(userID string, period string) (EnvelopesOverview, error)
+	ListOverspending(userID string, period string) (OverspendingReport, error)
+}
+
+type EnvelopesUseCaseImpl struct {
+	repo              EnvelopesRepo
+	categoriesUseCase categories.CategoriesUseCase
+	usersUseCase      users.UsersUseCase
+	db                *sql.DB
+}
+
+func NewEnvelopesUseCase(repo EnvelopesRepo, categoriesUseCase categories.CategoriesUseCase, usersUseCase users.UsersUseCase, db *sql.DB) EnvelopesUseCase {
+	return &EnvelopesUseCaseImpl{
+		repo:              repo,
+		categoriesUseCase: categoriesUseCase,
+		usersUseCase:      usersUseCase,
+		db:                db,
+	}
+}
+
+func (uc *EnvelopesUseCaseImpl) SetMode(userID string, enabled bool, since *string) (EnvelopeMode, error) {
+	if !enabled {
+		since = nil
+	} else if since == nil {
+		current := time.Now().Format("200601")
+		since = &current
+	} else if _, err := time.Parse("200601", *since); err != nil {
+		return EnvelopeMode{}, ErrInvalidPeriod
+	}
+
+	user, err := uc.usersUseCase.Update(userID, users.UpdateUserInput{
+		Update:                 []string{"envelope_budgeting_since"},
+		EnvelopeBudgetingSince: since,
+	})
+	if err != nil {
+		return EnvelopeMode{}, ErrFailedToSetMode
+	}
+
+	return EnvelopeMode{
+		Enabled: user.EnvelopeBudgetingSince != nil,
+		Since:   user.EnvelopeBudgetingSince,
+	}, nil
+}
+
+// since returns the first period counted by envelope budgeting, checking that
+// the mode is enabled and that period isn't before it
+func (uc *EnvelopesUseCaseImpl) since(userID string, period string) (string, error) {
+	if _, err := time.Parse("200601", period); err != nil {
+		return "", ErrInvalidPeriod
+	}
+
+	found, err := uc.usersUseCase.List(users.UserFilter{ID: userID})
+	if err != nil || len(found) == 0 {
+		return "", ErrFailedToFetchUser
+	}
+
+	since := found[0].EnvelopeBudgetingSince
+	if since == nil {
+		return "", ErrEnvelopeModeDisabled
+	}
+
+	if period < *since {
+		return "", ErrPeriodBeforeEnvelopeMode
+	}
+
+	return *since, nil
+}
+
+func (uc *EnvelopesUseCaseImpl) checkCategory(userID string, categoryID string) error {
+	count, err := uc.categoriesUseCase.Count(utils.QueryOpts().
+		And("id", "eq", categoryID).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCheckCategory
+	}
+
+	if count == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// lastPeriod bounds the sums read to check a movement, so every period after
+// the one of the movement is counted too
+const lastPeriod = "999912"
+
+// lowestBalance is the smallest money of an envelope, or of the available to
+// assign pool when categoryID is nil, at the end of period or of any later
+// period with movements or activity, as taking money in a period also takes it
+// from every one after it
+func (uc *EnvelopesUseCaseImpl) lowestBalance(db utils.Executer, userID string, since string, period string, categoryID *string) (float64, error) {
+	movements, err := uc.repo.SumMovements(db, userID, since, lastPeriod)
+	if err != nil {
+		return 0, ErrFailedToCheckBalance
+	}
+
+	activity, err := uc.repo.SumActivity(db, userID, since, lastPeriod)
+	if err != nil {
+		return 0, ErrFailedToCheckBalance
+	}
+
+	balance := 0.0
+	later := make(map[string]float64)
+	for _, amount := range append(movements, activity...) {
+		if (amount.CategoryID == nil) != (categoryID == nil) ||
+			(amount.CategoryID != nil && *amount.CategoryID != *categoryID) {
+			continue
+		}
+		if amount.Period <= period {
+			balance += amount.Amount
+		} else {
+			later[amount.Period] += amount.Amount
+		}
+	}
+
+	lowest := balance
+	for _, laterPeriod := range slices.Sorted(maps.Keys(later)) {
+		balance += later[laterPeriod]
+		lowest = min(lowest, balance)
+	}
+	return lowest, nil
+}
+
+// covers compares in cents, so float sums like 0.1 + 0.2 can be moved whole
+func covers(balance float64, amount float64) bool {
+	return math.Round(balance*100) >= math.Round(amount*100)
+}
+
+// createMovement checks that the source of the movement, the available to
+// assign pool when it has none, covers the amount and creates it. The user row
+// is held from the check to the insert so two movements at once can't both
+// take the same money
+func (uc *EnvelopesUseCaseImpl) createMovement(payload CreateMovementDTO, since string, errNotEnough error) (EnvelopeMovement, error) {
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return EnvelopeMovement{}, ErrFailedToCreateMovement
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.LockUser(tx, payload.UserID); err != nil {
+		return EnvelopeMovement{}, ErrFailedToCreateMovement
+	}
+
+	source, err := uc.lowestBalance(tx, payload.UserID, since, payload.Period, payload.FromCategoryID)
+	if err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	if !covers(source, payload.Amount) {
+		return EnvelopeMovement{}, errNotEnough
+	}
+
+	movement, err := uc.repo.CreateMovement(tx, payload)
+	if err != nil {
+		return EnvelopeMovement{}, ErrFailedToCreateMovement
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EnvelopeMovement{}, ErrFailedToCreateMovement
+	}
+
+	return movement, nil
+}
+
+func (uc *EnvelopesUseCaseImpl) Assign(payload CreateMovementDTO) (EnvelopeMovement, error) {
+	since, err := uc.since(payload.UserID, payload.Period)
+	if err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	if err := uc.checkCategory(payload.UserID, *payload.ToCategoryID); err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	payload.FromCategoryID = nil
+
+	return uc.createMovement(payload, since, ErrNotEnoughToAssign)
+}
+
+func (uc *EnvelopesUseCaseImpl) Move(payload CreateMovementDTO) (EnvelopeMovement, error) {
+	if *payload.FromCategoryID == *payload.ToCategoryID {
+		return EnvelopeMovement{}, ErrSameEnvelope
+	}
+
+	since, err := uc.since(payload.UserID, payload.Period)
+	if err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	if err := uc.checkCategory(payload.UserID, *payload.FromCategoryID); err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	if err := uc.checkCategory(payload.UserID, *payload.ToCategoryID); err != nil {
+		return EnvelopeMovement{}, err
+	}
+
+	// an overspent envelope is covered by moving money into it, never out of it
+	return uc.createMovement(payload, since, ErrNotEnoughInEnvelope)
+}
+
+func (uc *EnvelopesUseCaseImpl) ListEnvelopes(userID string, period string) (EnvelopesOverview, error) {
+	since, err := uc.since(userID, period)
+	if err != nil {
+		return EnvelopesOverview{}, err
+	}
+
+	movements, err := uc.repo.SumMovements(uc.db, userID, since, period)
+	if err != nil {
+		return EnvelopesOverview{}, ErrFailedToListEnvelopes
+	}
+
+	activity, err := uc.repo.SumActivity(uc.db, userID, since, period)
+	if err != nil {
+		return EnvelopesOverview{}, ErrFailedToListEnvelopes
+	}
+
+	userCategories, err := uc.categoriesUseCase.List(utils.QueryOpts().
+		And("user_id", "eq", userID).
+		OrderBy("name", "asc"))
+	if err != nil {
+		return EnvelopesOverview{}, ErrFailedToCheckCategory
+	}
+
+	overview := EnvelopesOverview{
+		Period:    period,
+		Envelopes: make([]Envelope, len(userCategories)),
+	}
+
+	byCategory := make(map[string]*Envelope)
+	for i, category := range userCategories {
+		overview.Envelopes[i] = Envelope{
+			CategoryID:    category.ID,
+			CategoryName:  category.Name,
+			CategoryColor: category.Color,
+		}
+		byCategory[category.ID] = &overview.Envelopes[i]
+	}
+
+	apply := func(amounts []EnvelopeAmount, current func(*Envelope) *float64) {
+		for _, amount := range amounts {
+			if amount.CategoryID == nil {
+				overview.AvailableToAssign += amount.Amount
+				continue
+			}
+
+			envelope, ok := byCategory[*amount.CategoryID]
+			if !ok {
+				continue
+			}
+
+			envelope.Balance += amount.Amount
+			if amount.Period == period {
+				*current(envelope) += amount.Amount
+			}
+		}
+	}
+
+	apply(movements, func(e *Envelope) *float64 { return &e.Assigned })
+	apply(activity, func(e *Envelope) *float64 { return &e.Activity })
+
+	for i := range overview.Envelopes {
+		overview.Envelopes[i].Overspent = overview.Envelopes[i].Balance < 0
+	}
+
+	return overview, nil
+}
+
+func (uc *EnvelopesUseCaseImpl) ListOverspending(userID string, period string) (OverspendingReport, error) {
+	overview, err := uc.ListEnvelopes(userID, period)
+	if err != nil {
+		return OverspendingReport{}, err
+	}
+
+	report := OverspendingReport{
+		Period:    period,
+		Envelopes: make([]Envelope, 0),
+	}
+
+	for _, envelope := range overview.Envelopes {
+		if envelope.Overspent {
+			report.TotalOverspent += -envelope.Balance
+			report.Envelopes = append(report.Envelopes, envelope)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,14 @@
+package forecast
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrInvalidMonths                 = utils.NewHTTPError(http.StatusBadRequest, "months must be a number between 1 and 60")
+	ErrFailedToAverageSpending       = utils.NewHTTPError(http.StatusInternalServerError, "failed to compute average spending")
+	ErrFailedToListRecurringForecast = utils.NewHTTPError(http.StatusInternalServerError, "failed to list recurring transactions")
+	ErrFailedToListScheduled         = utils.NewHTTPError(http.StatusInternalServerError, "failed to list scheduled entries")
+)
@@ -0,0 +1,70 @@
+package forecast
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	forecastUseCase ForecastUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		forecastUseCase: NewForecastUseCase(NewForecastRepo(db),
+			summary.NewSummaryUseCase(summary.NewSummaryRepo(db), db),
+			recurring.NewRecurringUseCase(recurring.NewRecurringRepo(db),
+				categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+				db),
+			db),
+	}
+}
+
+// @Summary Cash-flow forecast
+// @Description Project the balance of the next months from future entries, recurring transactions in the periods where their category has no entries yet and the average variable spending of the last months
+// @Tags forecast
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param months query int false "Months to project" default(6)
+// @Success 200 {object} ForecastResponse "Projected periods"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /forecast [get]
+func (api *API) Forecast(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	months := 6
+	if value, ok := ctx.GetQuery("months"); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			apiErr := ErrInvalidMonths
+			ctx.JSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		months = parsed
+	}
+
+	forecast, err := api.forecastUseCase.Forecast(userID, months)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ForecastResponse{
+		Data: ForecastResponseData{
+			Forecast: forecast,
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForecastRepo struct {
+	mock.Mock
+}
+
+func (m *MockForecastRepo) AverageVariableSpending(db utils.Executer, userID string, from string, to string, months int) ([]forecast.CategoryAverage, error) {
+	args := m.Called(db, userID, from, to, months)
+	return args.Get(0).([]forecast.CategoryAverage), args.Error(1)
+}
+
+func (m *MockForecastRepo) ListScheduledCategories(db utils.Executer, userID string, from string, to string) ([]forecast.ScheduledCategory, error) {
+	args := m.Called(db, userID, from, to)
+	return args.Get(0).([]forecast.ScheduledCategory), args.Error(1)
+}
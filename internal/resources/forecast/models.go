@@ -0,0 +1,63 @@
+package forecast
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type ForecastResponse struct {
+	Data ForecastResponseData `json:"data"`
+}
+
+type ForecastResponseData struct {
+	Forecast Forecast `json:"forecast"`
+}
+
+// Projection of a future period, net splits into what is already registered
+// as entries (scheduled), recurring templates and the average variable spending
+type ForecastPeriod struct {
+	Period    string  `json:"period"`
+	Income    float64 `json:"income"`
+	Expenses  float64 `json:"expenses"`
+	Scheduled float64 `json:"scheduled"`
+	Recurring float64 `json:"recurring"`
+	Variable  float64 `json:"variable"`
+	Net       float64 `json:"net"`
+	Balance   float64 `json:"balance"`
+	Negative  bool    `json:"negative"`
+}
+
+type Forecast struct {
+	OpeningBalance  float64          `json:"opening_balance"`
+	Periods         []ForecastPeriod `json:"periods"`
+	NegativePeriods []string         `json:"negative_periods"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// Entry that is not persisted, added on top of a forecast
+type ProjectedEntry struct {
+	Period string
+	Amount float64
+	Income bool
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Average monthly spending of a category, negative like the entries it comes from
+type CategoryAverage struct {
+	CategoryID *string
+	Average    float64
+}
+
+// Category with entries already registered in a future period
+type ScheduledCategory struct {
+	Period     string
+	CategoryID string
+}
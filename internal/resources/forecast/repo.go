@@ -0,0 +1,76 @@
+package forecast
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// Only simple expenses are variable spending, installments are already
+// registered as future entries
+const averageVariableSpendingQuery = `
+SELECT category_id, SUM(amount) / $4
+FROM v_entries
+WHERE user_id = $1 AND category = 'simple_expense' AND period BETWEEN $2 AND $3
+GROUP BY category_id`
+
+// Categories that already have entries in each period, such as the
+// installments of a purchase
+const listScheduledCategoriesQuery = `
+SELECT DISTINCT period, category_id
+FROM v_entries
+WHERE user_id = $1 AND category_id IS NOT NULL AND period BETWEEN $2 AND $3`
+
+type ForecastRepo interface {
+	AverageVariableSpending(db utils.Executer, userID string, from string, to string, months int) ([]CategoryAverage, error)
+	ListScheduledCategories(db utils.Executer, userID string, from string, to string) ([]ScheduledCategory, error)
+}
+
+type ForecastRepoImpl struct {
+}
+
+func NewForecastRepo(db utils.Executer) ForecastRepo {
+	return &ForecastRepoImpl{}
+}
+
+func (r *ForecastRepoImpl) AverageVariableSpending(db utils.Executer, userID string, from string, to string, months int) ([]CategoryAverage, error) {
+	rows, err := db.Query(averageVariableSpendingQuery, userID, from, to, months)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	averages := make([]CategoryAverage, 0)
+	for rows.Next() {
+		var average CategoryAverage
+		if err := rows.Scan(
+			&average.CategoryID,
+			&average.Average,
+		); err != nil {
+			return nil, err
+		}
+		averages = append(averages, average)
+	}
+
+	return averages, rows.Err()
+}
+
+func (r *ForecastRepoImpl) ListScheduledCategories(db utils.Executer, userID string, from string, to string) ([]ScheduledCategory, error) {
+	rows, err := db.Query(listScheduledCategoriesQuery, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scheduled := make([]ScheduledCategory, 0)
+	for rows.Next() {
+		var category ScheduledCategory
+		if err := rows.Scan(
+			&category.Period,
+			&category.CategoryID,
+		); err != nil {
+			return nil, err
+		}
+		scheduled = append(scheduled, category)
+	}
+
+	return scheduled, rows.Err()
+}
@@ -0,0 +1,208 @@
+package forecast
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+const (
+	MaxMonths = 60
+	// complete periods before the current one used to average variable spending
+	historyMonths = 3
+)
+
+type ForecastUseCase interface {
+	Forecast(userID string, months int) (Forecast, error)
+}
+
+type ForecastUseCaseImpl struct {
+	repo             ForecastRepo
+	summaryUseCase   summary.SummaryUseCase
+	recurringUseCase recurring.RecurringUseCase
+	db               *sql.DB
+}
+
+func NewForecastUseCase(repo ForecastRepo, summaryUseCase summary.SummaryUseCase, recurringUseCase recurring.RecurringUseCase, db *sql.DB) ForecastUseCase {
+	return &ForecastUseCaseImpl{
+		repo:             repo,
+		summaryUseCase:   summaryUseCase,
+		recurringUseCase: recurringUseCase,
+		db:               db,
+	}
+}
+
+// Forecast projects the periods after the current one, starting from the
+// balance of every entry up to the end of the current period
+func (uc *ForecastUseCaseImpl) Forecast(userID string, months int) (Forecast, error) {
+	if months < 1 || months > MaxMonths {
+		return Forecast{}, ErrInvalidMonths
+	}
+
+	// first day of the month, so adding months never skips one
+	now := time.Now()
+	now = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	first := now.AddDate(0, 1, 0).Format("200601")
+	last := now.AddDate(0, months, 0).Format("200601")
+
+	summaries, err := uc.summaryUseCase.ListPeriodSummaries(userID, first, last)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	templates, err := uc.recurringUseCase.List(utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return Forecast{}, ErrFailedToListRecurringForecast
+	}
+
+	averages, err := uc.repo.AverageVariableSpending(uc.db, userID,
+		now.AddDate(0, -historyMonths, 0).Format("200601"),
+		now.AddDate(0, -1, 0).Format("200601"),
+		historyMonths)
+	if err != nil {
+		return Forecast{}, ErrFailedToAverageSpending
+	}
+
+	scheduledCategories, err := uc.repo.ListScheduledCategories(uc.db, userID, first, last)
+	if err != nil {
+		return Forecast{}, ErrFailedToListScheduled
+	}
+
+	scheduled := make(map[string]map[string]bool)
+	for _, category := range scheduledCategories {
+		if scheduled[category.Period] == nil {
+			scheduled[category.Period] = make(map[string]bool)
+		}
+		scheduled[category.Period][category.CategoryID] = true
+	}
+
+	return project(summaries, templates, scheduled, variableSpending(averages, templates)), nil
+}
+
+// variableSpending sums the average spending of the categories that have no
+// recurring expense, those are considered fixed and already projected by the template
+func variableSpending(averages []CategoryAverage, templates []recurring.RecurringTransaction) float64 {
+	fixed := make(map[string]bool)
+	for _, template := range templates {
+		if template.Type == constants.SimpleExpense && template.CategoryID != nil {
+			fixed[*template.CategoryID] = true
+		}
+	}
+
+	variable := 0.0
+	for _, average := range averages {
+		if average.CategoryID != nil && fixed[*average.CategoryID] {
+			continue
+		}
+		variable += average.Average
+	}
+	return variable
+}
+
+// project adds the templates to the entries already scheduled in each period,
+// except in a period where the category of a template already has entries as
+// those are taken for the ones it repeats, so it isn't counted twice
+func project(summaries []summary.PeriodSummary, templates []recurring.RecurringTransaction, scheduled map[string]map[string]bool, variable float64) Forecast {
+	forecast := Forecast{
+		Periods:         make([]ForecastPeriod, 0, len(summaries)),
+		NegativePeriods: make([]string, 0),
+	}
+
+	if len(summaries) == 0 {
+		return forecast
+	}
+
+	forecast.OpeningBalance = summaries[0].Balance - summaries[0].Net
+	balance := forecast.OpeningBalance
+
+	for _, periodSummary := range summaries {
+		period := ForecastPeriod{
+			Period:    periodSummary.Period,
+			Income:    periodSummary.Income,
+			Expenses:  periodSummary.Expenses + variable,
+			Scheduled: periodSummary.Net,
+			Variable:  variable,
+		}
+
+		for _, template := range templates {
+			if !template.ActiveIn(period.Period) {
+				continue
+			}
+			if template.CategoryID != nil && scheduled[period.Period][*template.CategoryID] {
+				continue
+			}
+			period.Recurring += template.Amount
+			if template.Type == constants.Income {
+				period.Income += template.Amount
+			} else {
+				period.Expenses += template.Amount
+			}
+		}
+
+		period.Net = period.Scheduled + period.Recurring + period.Variable
+		balance += period.Net
+		period.Balance = balance
+		period.Negative = balance < 0
+
+		if period.Negative {
+			forecast.NegativePeriods = append(forecast.NegativePeriods, period.Period)
+		}
+
+		forecast.Periods = append(forecast.Periods, period)
+	}
+
+	return forecast
+}
+
+// WithEntries returns a copy of the forecast as if the entries were already
+// scheduled, entries before the first period change the opening balance
+func (f Forecast) WithEntries(entries []ProjectedEntry) Forecast {
+	result := Forecast{
+		OpeningBalance:  f.OpeningBalance,
+		Periods:         make([]ForecastPeriod, len(f.Periods)),
+		NegativePeriods: make([]string, 0),
+	}
+	copy(result.Periods, f.Periods)
+
+	indexes := make(map[string]int)
+	for i, period := range result.Periods {
+		indexes[period.Period] = i
+	}
+
+	for _, entry := range entries {
+		if len(result.Periods) > 0 && entry.Period < result.Periods[0].Period {
+			result.OpeningBalance += entry.Amount
+			continue
+		}
+
+		i, ok := indexes[entry.Period]
+		if !ok {
+			continue
+		}
+
+		period := &result.Periods[i]
+		period.Scheduled += entry.Amount
+		period.Net += entry.Amount
+		if entry.Income {
+			period.Income += entry.Amount
+		} else {
+			period.Expenses += entry.Amount
+		}
+	}
+
+	balance := result.OpeningBalance
+	for i := range result.Periods {
+		balance += result.Periods[i].Net
+		result.Periods[i].Balance = balance
+		result.Periods[i].Negative = balance < 0
+		if result.Periods[i].Negative {
+			result.NegativePeriods = append(result.NegativePeriods, result.Periods[i].Period)
+		}
+	}
+
+	return result
+}
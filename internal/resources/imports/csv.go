@@ -0,0 +1,479 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// Date formats accepted in a mapping and their layouts, single digit days
+// and months are accepted too
+var dateFormats = map[string]string{
+	"DD/MM/YYYY": "2/1/2006",
+	"DD/MM/YY":   "2/1/06",
+	"MM/DD/YYYY": "1/2/2006",
+	"YYYY-MM-DD": "2006-1-2",
+	"DD-MM-YYYY": "2-1-2006",
+	"DD.MM.YYYY": "2.1.2006",
+	"YYYY/MM/DD": "2006/1/2",
+}
+
+// Order formats are tried when detecting, day first comes before month first
+// so an ambiguous 03/04 is read as April 3rd
+var dateFormatOrder = []string{"DD/MM/YYYY", "YYYY-MM-DD", "DD-MM-YYYY", "DD.MM.YYYY", "YYYY/MM/DD", "MM/DD/YYYY", "DD/MM/YY"}
+
+// Delimiters tried when detecting, semicolon first since exports that use
+// decimal comma separate fields with it
+var delimiters = []string{";", ",", "\t", "|"}
+
+// how many rows are read to detect the mapping
+const detectionSample = 50
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func detectEncoding(data []byte) string {
+	if utf8.Valid(data) {
+		return "utf-8"
+	}
+	return "latin-1"
+}
+
+// decode converts the file to UTF-8, every Latin-1 byte is the code point of
+// the same value
+func decode(data []byte, encoding string) string {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if encoding != "latin-1" {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+func readRecords(text string, delimiter string) ([][]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma, _ = utf8.DecodeRuneInString(delimiter)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records := make([][]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isBlank(record) {
+			records = append(records, record)
+		}
+	}
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// detectDelimiter picks the delimiter that splits most of the first lines in
+// the same number of fields, with more than one field
+func detectDelimiter(text string) string {
+	lines := strings.SplitN(text, "\n", detectionSample+1)
+	sample := strings.Join(lines[:min(len(lines), detectionSample)], "\n")
+
+	best, bestScore := delimiters[0], 0
+	for _, delimiter := range delimiters {
+		records, err := readRecords(sample, delimiter)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+
+		counts := make(map[int]int)
+		for _, record := range records {
+			counts[len(record)]++
+		}
+
+		mode, modeCount := 0, 0
+		for fields, count := range counts {
+			if count > modeCount || (count == modeCount && fields > mode) {
+				mode, modeCount = fields, count
+			}
+		}
+
+		if mode < 2 {
+			continue
+		}
+
+		score := modeCount * 100 / len(records)
+		if score > bestScore {
+			best, bestScore = delimiter, score
+		}
+	}
+	return best
+}
+
+// cleanAmount removes currency symbols and spaces and tells if the value is
+// negative by a leading or trailing minus or by parentheses
+func cleanAmount(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	value = strings.ReplaceAll(value, "R$", "")
+	value = strings.ReplaceAll(value, " ", "")
+	value = strings.ReplaceAll(value, "\u00a0", "")
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = strings.Trim(value, "()")
+	}
+	if strings.HasSuffix(value, "-") {
+		negative = true
+		value = strings.TrimSuffix(value, "-")
+	}
+	if strings.HasPrefix(value, "-") {
+		negative = !negative
+		value = strings.TrimPrefix(value, "-")
+	}
+	value = strings.TrimPrefix(value, "+")
+
+	return value, negative
+}
+
+func parseAmount(value string, decimalSeparator string) (float64, error) {
+	cleaned, negative := cleanAmount(value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+
+	if decimalSeparator == "," {
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	}
+
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+
+	if negative {
+		amount = -amount
+	}
+	return math.Round(amount*100) / 100, nil
+}
+
+// detectDecimalSeparator votes on the last separator of each value followed
+// by one or two digits, like 1.234,56 or 12.30
+func detectDecimalSeparator(values []string) string {
+	comma, dot := 0, 0
+	for _, value := range values {
+		cleaned, _ := cleanAmount(value)
+		i := strings.LastIndexAny(cleaned, ".,")
+		if i == -1 {
+			continue
+		}
+		decimals := len(cleaned) - i - 1
+		if decimals < 1 || decimals > 2 {
+			continue
+		}
+		if cleaned[i] == ',' {
+			comma++
+		} else {
+			dot++
+		}
+	}
+
+	if comma > dot {
+		return ","
+	}
+	return "."
+}
+
+func parseDate(value string, format string) (time.Time, error) {
+	// exports often add the time after the date
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return time.Time{}, fmt.Errorf("missing date")
+	}
+
+	date, err := time.Parse(dateFormats[format], fields[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s', expected %s", value, format)
+	}
+	return date, nil
+}
+
+func column(record []string, index *int) string {
+	if index == nil || *index < 0 || *index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[*index])
+}
+
+// columnMatches tells if every non empty value of a column passes a check,
+// requiring at least one value
+func columnMatches(records [][]string, index int, check func(string) bool) bool {
+	found := false
+	for _, record := range records {
+		value := column(record, &index)
+		if value == "" {
+			continue
+		}
+		if !check(value) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func isNumeric(value string) bool {
+	cleaned, _ := cleanAmount(value)
+	if cleaned == "" {
+		return false
+	}
+	for _, r := range cleaned {
+		if (r < '0' || r > '9') && r != '.' && r != ',' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDate(value string) bool {
+	for _, format := range dateFormatOrder {
+		if _, err := parseDate(value, format); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMapping(mapping CSVMapping) error {
+	if mapping.Delimiter != "" && utf8.RuneCountInString(mapping.Delimiter) != 1 {
+		return utils.NewHTTPError(http.StatusBadRequest, "delimiter must be a single character")
+	}
+
+	if mapping.Encoding != "" && mapping.Encoding != "utf-8" && mapping.Encoding != "latin-1" {
+		return utils.NewHTTPError(http.StatusBadRequest, "encoding must be utf-8 or latin-1")
+	}
+
+	if mapping.DecimalSeparator != "" && mapping.DecimalSeparator != "," && mapping.DecimalSeparator != "." {
+		return utils.NewHTTPError(http.StatusBadRequest, "decimal_separator must be ',' or '.'")
+	}
+
+	if _, ok := dateFormats[mapping.DateFormat]; mapping.DateFormat != "" && !ok {
+		return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("date_format must be one of %s", strings.Join(dateFormatOrder, ", ")))
+	}
+
+	for _, index := range []*int{mapping.DateColumn, mapping.NameColumn, mapping.AmountColumn, mapping.NoteColumn, mapping.CategoryColumn, mapping.PayeeColumn} {
+		if index != nil && *index < 0 {
+			return utils.NewHTTPError(http.StatusBadRequest, "columns must not be negative")
+		}
+	}
+
+	if mapping.Account != nil && (len(*mapping.Account) == 0 || utf8.RuneCountInString(*mapping.Account) > 100) {
+		return utils.NewHTTPError(http.StatusBadRequest, "account must have between 1 and 100 characters")
+	}
+
+	return nil
+}
+
+// ReadCSV decodes a file and fills every empty field of the mapping with what
+// is detected from it, returning the mapping, the header when there is one
+// and the data rows
+func ReadCSV(data []byte, mapping CSVMapping) (CSVMapping, []string, [][]string, error) {
+	if err := validateMapping(mapping); err != nil {
+		return CSVMapping{}, nil, nil, err
+	}
+
+	if mapping.Encoding == "" {
+		mapping.Encoding = detectEncoding(data)
+	}
+	text := decode(data, mapping.Encoding)
+
+	if mapping.Delimiter == "" {
+		mapping.Delimiter = detectDelimiter(text)
+	}
+
+	records, err := readRecords(text, mapping.Delimiter)
+	if err != nil {
+		return CSVMapping{}, nil, nil, ErrFailedToReadFile
+	}
+
+	if len(records) == 0 {
+		return CSVMapping{}, nil, nil, ErrEmptyFile
+	}
+
+	// a header has no dates nor numbers, unlike the rows after it
+	if mapping.HasHeader == nil {
+		hasHeader := len(records) > 1
+		for _, value := range records[0] {
+			if isDate(value) || isNumeric(value) {
+				hasHeader = false
+				break
+			}
+		}
+		mapping.HasHeader = &hasHeader
+	}
+
+	var header []string
+	rows := records
+	if *mapping.HasHeader {
+		header, rows = records[0], records[1:]
+	}
+
+	sample := rows[:min(len(rows), detectionSample)]
+	width := 0
+	for _, record := range sample {
+		width = max(width, len(record))
+	}
+
+	if mapping.DateColumn == nil {
+		for i := 0; i < width && mapping.DateColumn == nil; i++ {
+			if columnMatches(sample, i, isDate) {
+				index := i
+				mapping.DateColumn = &index
+			}
+		}
+	}
+
+	if mapping.DateColumn != nil && mapping.DateFormat == "" {
+		for _, format := range dateFormatOrder {
+			if columnMatches(sample, *mapping.DateColumn, func(value string) bool {
+				_, err := parseDate(value, format)
+				return err == nil
+			}) {
+				mapping.DateFormat = format
+				break
+			}
+		}
+	}
+
+	if mapping.AmountColumn == nil {
+		for i := 0; i < width && mapping.AmountColumn == nil; i++ {
+			if mapping.DateColumn != nil && i == *mapping.DateColumn {
+				continue
+			}
+			if columnMatches(sample, i, isNumeric) {
+				index := i
+				mapping.AmountColumn = &index
+			}
+		}
+	}
+
+	if mapping.AmountColumn != nil && mapping.DecimalSeparator == "" {
+		values := make([]string, 0, len(sample))
+		for _, record := range sample {
+			values = append(values, column(record, mapping.AmountColumn))
+		}
+		mapping.DecimalSeparator = detectDecimalSeparator(values)
+	}
+
+	// the description is usually the longest text of a row
+	if mapping.NameColumn == nil {
+		longest := 0
+		for i := 0; i < width; i++ {
+			if (mapping.DateColumn != nil && i == *mapping.DateColumn) || (mapping.AmountColumn != nil && i == *mapping.AmountColumn) {
+				continue
+			}
+			total := 0
+			for _, record := range sample {
+				total += utf8.RuneCountInString(column(record, &i))
+			}
+			if total > longest {
+				index := i
+				mapping.NameColumn, longest = &index, total
+			}
+		}
+	}
+
+	if mapping.DateColumn == nil || mapping.DateFormat == "" || mapping.AmountColumn == nil || mapping.NameColumn == nil {
+		return mapping, header, rows, ErrColumnsNotDetected
+	}
+
+	return mapping, header, rows, nil
+}
+
+func truncate(value string, size int) string {
+	runes := []rune(value)
+	if len(runes) > size {
+		return string(runes[:size])
+	}
+	return value
+}
+
+func optionalColumn(record []string, index *int, size int) *string {
+	value := column(record, index)
+	if value == "" {
+		return nil
+	}
+	value = truncate(value, size)
+	return &value
+}
+
+// ParseCSVRow reads a data row with a complete mapping, negative amounts are
+// expenses and positive ones incomes unless the mapping inverts them
+func ParseCSVRow(record []string, mapping CSVMapping, row int) ParsedRow {
+	parsed := ParsedRow{
+		Row:      row,
+		Name:     truncate(column(record, mapping.NameColumn), 100),
+		Note:     optionalColumn(record, mapping.NoteColumn, 400),
+		Category: optionalColumn(record, mapping.CategoryColumn, 50),
+		Payee:    optionalColumn(record, mapping.PayeeColumn, 100),
+	}
+
+	fail := func(message string) ParsedRow {
+		parsed.Error = &message
+		return parsed
+	}
+
+	date, err := parseDate(column(record, mapping.DateColumn), mapping.DateFormat)
+	if err != nil {
+		return fail(err.Error())
+	}
+	parsed.Date = date.Format("2006-01-02")
+
+	amount, err := parseAmount(column(record, mapping.AmountColumn), mapping.DecimalSeparator)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if mapping.InvertAmounts {
+		amount = -amount
+	}
+	parsed.Amount = amount
+
+	switch {
+	case amount < 0:
+		parsed.Type = constants.SimpleExpense
+	case amount > 0:
+		parsed.Type = constants.Income
+	default:
+		return fail("amount must not be zero")
+	}
+
+	if parsed.Name == "" {
+		return fail("missing name")
+	}
+
+	return parsed
+}
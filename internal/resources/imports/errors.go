@@ -0,0 +1,40 @@
+package imports
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrMissingFile            = utils.NewHTTPError(http.StatusBadRequest, "file is required")
+	ErrFileTooLarge           = utils.NewHTTPError(http.StatusRequestEntityTooLarge, "file must have at most 5MB")
+	ErrEmptyFile              = utils.NewHTTPError(http.StatusBadRequest, "file has no rows")
+	ErrTooManyRows            = utils.NewHTTPError(http.StatusBadRequest, "file must have at most 5000 rows")
+	ErrInvalidMapping         = utils.NewHTTPError(http.StatusBadRequest, "mapping must be a valid JSON object")
+	ErrColumnsNotDetected     = utils.NewHTTPError(http.StatusBadRequest, "could not detect the date, name and amount columns, send them in the mapping")
+	ErrProfileNotFound        = utils.NewHTTPError(http.StatusNotFound, "import profile not found")
+	ErrBatchNotFound          = utils.NewHTTPError(http.StatusNotFound, "import batch not found")
+	ErrFailedToReadFile       = utils.NewHTTPError(http.StatusBadRequest, "failed to read file")
+	ErrFailedToCreateProfile  = utils.NewHTTPError(http.StatusInternalServerError, "failed to create import profile")
+	ErrFailedToListProfiles   = utils.NewHTTPError(http.StatusInternalServerError, "failed to list import profiles")
+	ErrFailedToCountProfiles  = utils.NewHTTPError(http.StatusInternalServerError, "failed to count import profiles")
+	ErrFailedToDeleteProfile  = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete import profile")
+	ErrFailedToCreateBatch    = utils.NewHTTPError(http.StatusInternalServerError, "failed to create import batch")
+	ErrFailedToListBatches    = utils.NewHTTPError(http.StatusInternalServerError, "failed to list import batches")
+	ErrFailedToCountBatches   = utils.NewHTTPError(http.StatusInternalServerError, "failed to count import batches")
+	ErrFailedToUndoBatch      = utils.NewHTTPError(http.StatusInternalServerError, "failed to undo import batch")
+	ErrFailedToListCategories = utils.NewHTTPError(http.StatusInternalServerError, "failed to list categories")
+	ErrInvalidOFX             = utils.NewHTTPError(http.StatusBadRequest, "file is not an OFX statement")
+	ErrInvalidAccount         = utils.NewHTTPError(http.StatusBadRequest, "account must have at most 100 characters")
+	ErrFailedToCheckImported  = utils.NewHTTPError(http.StatusInternalServerError, "failed to check imported transactions")
+	ErrFailedToGetBalance     = utils.NewHTTPError(http.StatusInternalServerError, "failed to get account balance")
+	ErrInvalidQIF             = utils.NewHTTPError(http.StatusBadRequest, "file is not a QIF file")
+	ErrInvalidDateFormat      = utils.NewHTTPError(http.StatusBadRequest, "date_format must be MM/DD/YYYY or DD/MM/YYYY")
+	ErrInvalidDateRange       = utils.NewHTTPError(http.StatusBadRequest, "from and to must be dates as YYYY-MM-DD and from must not be after to")
+	ErrFailedToCreateCategory = utils.NewHTTPError(http.StatusInternalServerError, "failed to create category")
+	ErrFailedToListEntries    = utils.NewHTTPError(http.StatusInternalServerError, "failed to list entries")
+	ErrInvalidNFe             = utils.NewHTTPError(http.StatusBadRequest, "file is not an NF-e or NFC-e XML")
+	ErrInvalidAccessKey       = utils.NewHTTPError(http.StatusBadRequest, "invoice access key is invalid")
+	ErrInvoiceAlreadyImported = utils.NewHTTPError(http.StatusConflict, "invoice was already imported")
+)
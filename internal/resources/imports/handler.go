@@ -0,0 +1,547 @@
+package imports
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// largest file accepted by an import
+const maxFileSize = 5 << 20
+
+type API struct {
+	importsUseCase ImportsUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	categoriesUseCase := categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db)
+	return &API{
+		importsUseCase: NewImportsUseCase(NewImportsRepo(db),
+			transactions.NewTransactionsUseCase(transactions.NewTransactionsRepo(db),
+				categoriesUseCase,
+				rules.NewRulesUseCase(rules.NewRulesRepo(db), categoriesUseCase, db),
+				db),
+			categoriesUseCase,
+			db),
+	}
+}
+
+type uploadedFile struct {
+	Name *string
+	Data []byte
+}
+
+// readFile reads the multipart "file" field of an import request
+func readFile(ctx *gin.Context) (uploadedFile, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return uploadedFile{}, ErrMissingFile
+	}
+
+	if header.Size > maxFileSize {
+		return uploadedFile{}, ErrFileTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return uploadedFile{}, ErrFailedToReadFile
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if err != nil {
+		return uploadedFile{}, ErrFailedToReadFile
+	}
+
+	if len(data) > maxFileSize {
+		return uploadedFile{}, ErrFileTooLarge
+	}
+
+	var name *string
+	if header.Filename != "" {
+		fileName := truncate(header.Filename, 255)
+		name = &fileName
+	}
+
+	return uploadedFile{Name: name, Data: data}, nil
+}
+
+// readCSVForm reads the optional "mapping" and "profile_id" form fields sent
+// along with a CSV file
+func readCSVForm(ctx *gin.Context) (CSVMapping, *string, error) {
+	var mapping CSVMapping
+	if raw := ctx.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return CSVMapping{}, nil, ErrInvalidMapping
+		}
+	}
+
+	var profileID *string
+	if raw := ctx.PostForm("profile_id"); raw != "" {
+		profileID = &raw
+	}
+
+	return mapping, profileID, nil
+}
+
+// @Summary Preview a CSV import
+// @Description Detect the delimiter, encoding, header, decimal separator, date format and columns of a bank export and show how its first rows would be imported. Fields sent in the mapping, or saved in the profile, are used instead of detected ones
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file, at most 5MB"
+// @Param mapping formData string false "Column mapping as JSON" example({"date_column":0,"name_column":1,"amount_column":2})
+// @Param profile_id formData string false "Saved mapping profile ID"
+// @Success 200 {object} PreviewCSVResponse "Import preview"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Profile not found"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/csv/preview [post]
+func (api *API) PreviewCSV(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	file, err := readFile(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	mapping, profileID, err := readCSVForm(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	preview, err := api.importsUseCase.PreviewCSV(userID, file.Data, mapping, profileID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PreviewCSVResponse{
+		Data: PreviewCSVResponseData{
+			Preview: preview,
+		},
+	})
+}
+
+// @Summary Import a CSV
+// @Description Create a transaction for each row of a bank export, the same way the preview reads it. Rows that fail are reported and don't stop the others, and every transaction created can be undone by deleting the import batch
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file, at most 5MB and 5000 rows"
+// @Param mapping formData string false "Column mapping as JSON" example({"date_column":0,"name_column":1,"amount_column":2})
+// @Param profile_id formData string false "Saved mapping profile ID"
+// @Success 201 {object} ImportResponse "Import batch and row errors"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Profile not found"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/csv [post]
+func (api *API) ImportCSV(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	file, err := readFile(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	mapping, profileID, err := readCSVForm(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	batch, rowErrors, err := api.importsUseCase.ImportCSV(userID, file.Name, file.Data, mapping, profileID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ImportResponse{
+		Data: ImportResponseData{
+			Batch:  batch,
+			Errors: rowErrors,
+		},
+	})
+}
+
+// @Summary Import an OFX statement
+// @Description Create a transaction for each STMTTRN of an OFX 1.x (SGML) or 2.x (XML) bank or credit card statement, debits as expenses and credits as incomes. Transactions whose FITID was already imported are skipped, so the same file can be sent again. The ledger balance of each statement is reported, and compared with the balance of the account when one is given
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "OFX or QFX file, at most 5MB and 5000 transactions"
+// @Param account formData string false "Account the transactions belong to" example(nubank)
+// @Success 201 {object} ImportOFXResponse "Import batch, statements and row errors"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/ofx [post]
+func (api *API) ImportOFX(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	file, err := readFile(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	var account *string
+	if raw := ctx.PostForm("account"); raw != "" {
+		account = &raw
+	}
+
+	batch, statements, rowErrors, err := api.importsUseCase.ImportOFX(userID, file.Name, file.Data, account)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ImportOFXResponse{
+		Data: ImportOFXResponseData{
+			Batch:      batch,
+			Statements: statements,
+			Errors:     rowErrors,
+		},
+	})
+}
+
+// @Summary Import a QIF file
+// @Description Create a transaction for each record of the bank, credit card and cash sections of a QIF file, a record with splits becoming a transaction per split. Categories the user doesn't have are created with the batch and removed when it's undone, subcategories keep the Category:Subcategory name, and transfers between accounts are left without category. Records already imported are skipped, so the same file can be sent again, and a record whose splits don't add up to its total is a row error
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "QIF file, at most 5MB and 5000 transactions"
+// @Param date_format formData string false "Date format of the file, MM/DD/YYYY or DD/MM/YYYY" default(MM/DD/YYYY)
+// @Param account formData string false "Account of transactions outside an !Account block" example(nubank)
+// @Success 201 {object} ImportQIFResponse "Import batch, created categories and row errors"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/qif [post]
+func (api *API) ImportQIF(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	file, err := readFile(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	var account *string
+	if raw := ctx.PostForm("account"); raw != "" {
+		account = &raw
+	}
+
+	dateFormat := ctx.DefaultPostForm("date_format", "MM/DD/YYYY")
+
+	batch, createdCategories, rowErrors, err := api.importsUseCase.ImportQIF(userID, file.Name, file.Data, dateFormat, account)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ImportQIFResponse{
+		Data: ImportQIFResponseData{
+			Batch:             batch,
+			CreatedCategories: createdCategories,
+			Errors:            rowErrors,
+		},
+	})
+}
+
+// @Summary Export a QIF file
+// @Description Export the entries of a date range as a QIF file, with a bank section per account. Each installment is its own record
+// @Tags imports
+// @Security BearerAuth
+// @Produce application/qif
+// @Param from query string true "First date" example(2025-01-01)
+// @Param to query string true "Last date" example(2025-12-31)
+// @Param date_format query string false "Date format of the file, MM/DD/YYYY or DD/MM/YYYY" default(MM/DD/YYYY)
+// @Success 200 {file} file "QIF file"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /exports/qif [get]
+func (api *API) ExportQIF(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	from := ctx.Query("from")
+	to := ctx.Query("to")
+	dateFormat := ctx.DefaultQuery("date_format", "MM/DD/YYYY")
+
+	data, err := api.importsUseCase.ExportQIF(userID, from, to, dateFormat)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"transactions-%s-%s.qif\"", from, to))
+	ctx.Data(http.StatusOK, "application/qif", data)
+}
+
+// @Summary Import an NF-e or NFC-e
+// @Description Create a transaction from an electronic invoice XML: the payee is the issuer, the amount is the invoice total and each product becomes an item of the transaction. When the billing has two or more dup entries it's created as an installment following them. An invoice is only imported once by its access key
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Invoice XML, at most 5MB"
+// @Param account formData string false "Account the purchase was paid with" example(nubank)
+// @Success 201 {object} ImportNFeResponse "Import batch and invoice"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 409 {object} utils.HTTPError "Invoice already imported"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/nfe [post]
+func (api *API) ImportNFe(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	file, err := readFile(ctx)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	var account *string
+	if raw := ctx.PostForm("account"); raw != "" {
+		account = &raw
+	}
+
+	batch, invoice, rowErrors, err := api.importsUseCase.ImportNFe(userID, file.Name, file.Data, account)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ImportNFeResponse{
+		Data: ImportNFeResponseData{
+			Batch:   batch,
+			Invoice: invoice,
+			Errors:  rowErrors,
+		},
+	})
+}
+
+// @Summary List import batches
+// @Description List the imports made by the user with how many rows were created and failed
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(created_at:desc)
+// @Param filter query string false "Batch filter" example(source eq 'csv')
+// @Success 200 {object} ListBatchesResponse "List of import batches"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports [get]
+func (api *API) ListBatches(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	batches, err := api.importsUseCase.ListBatches(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	batches, meta, err := utils.Paginate(batches, queryOpts, page, perPage, api.importsUseCase.CountBatches)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListBatchesResponse{
+		Data: ListBatchesResponseData{
+			Batches: batches,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary Undo an import
+// @Description Delete every transaction created by an import batch, the categories it created that no other transaction uses, and the batch itself
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param batch_id path string true "import batch ID"
+// @Success 204 "Import undone"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/{batch_id} [delete]
+func (api *API) UndoBatch(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("batch_id")
+
+	err := api.importsUseCase.UndoBatch(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Create an import profile
+// @Description Save a column mapping to reuse when importing files of the same bank
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateProfileRequest true "Profile payload"
+// @Success 201 {object} CreateProfileResponse "Profile created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/profiles [post]
+func (api *API) CreateProfile(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateProfileRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	profile, err := api.importsUseCase.CreateProfile(CreateProfileDTO{
+		UserID:  userID,
+		Name:    body.Name,
+		Mapping: body.Mapping,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateProfileResponse{
+		Data: CreateProfileResponseData{
+			Profile: profile,
+		},
+	})
+}
+
+// @Summary List import profiles
+// @Description List the saved column mappings
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(name:asc)
+// @Param filter query string false "Profile filter"
+// @Success 200 {object} ListProfilesResponse "List of import profiles"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/profiles [get]
+func (api *API) ListProfiles(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	profiles, err := api.importsUseCase.ListProfiles(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	profiles, meta, err := utils.Paginate(profiles, queryOpts, page, perPage, api.importsUseCase.CountProfiles)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListProfilesResponse{
+		Data: ListProfilesResponseData{
+			Profiles: profiles,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary Delete an import profile
+// @Description Delete a saved column mapping
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param profile_id path string true "import profile ID"
+// @Success 204 "Profile deleted"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /imports/profiles/{profile_id} [delete]
+func (api *API) DeleteProfileByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("profile_id")
+
+	err := api.importsUseCase.DeleteProfileByID(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
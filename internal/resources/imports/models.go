@@ -0,0 +1,286 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type PreviewCSVResponse struct {
+	Data PreviewCSVResponseData `json:"data"`
+}
+
+type PreviewCSVResponseData struct {
+	Preview CSVPreview `json:"preview"`
+}
+
+type ImportResponse struct {
+	Data ImportResponseData `json:"data"`
+}
+
+type ImportResponseData struct {
+	Batch  ImportBatch `json:"batch"`
+	Errors []RowError  `json:"errors"`
+}
+
+type ImportOFXResponse struct {
+	Data ImportOFXResponseData `json:"data"`
+}
+
+type ImportOFXResponseData struct {
+	Batch      ImportBatch    `json:"batch"`
+	Statements []OFXStatement `json:"statements"`
+	Errors     []RowError     `json:"errors"`
+}
+
+type ImportQIFResponse struct {
+	Data ImportQIFResponseData `json:"data"`
+}
+
+type ImportQIFResponseData struct {
+	Batch             ImportBatch           `json:"batch"`
+	CreatedCategories []categories.Category `json:"created_categories"`
+	Errors            []RowError            `json:"errors"`
+}
+
+type ImportNFeResponse struct {
+	Data ImportNFeResponseData `json:"data"`
+}
+
+type ImportNFeResponseData struct {
+	Batch   ImportBatch `json:"batch"`
+	Invoice NFeInvoice  `json:"invoice"`
+	Errors  []RowError  `json:"errors"`
+}
+
+type ListBatchesResponse struct {
+	Data  ListBatchesResponseData `json:"data"`
+	Query utils.QueryMeta         `json:"query"`
+}
+
+type ListBatchesResponseData struct {
+	Batches []ImportBatch `json:"batches"`
+}
+
+type CreateProfileRequest struct {
+	Name    string     `json:"name" binding:"required,min=1,max=100"`
+	Mapping CSVMapping `json:"mapping"`
+}
+
+type CreateProfileResponse struct {
+	Data CreateProfileResponseData `json:"data"`
+}
+
+type CreateProfileResponseData struct {
+	Profile ImportProfile `json:"profile"`
+}
+
+type ListProfilesResponse struct {
+	Data  ListProfilesResponseData `json:"data"`
+	Query utils.QueryMeta          `json:"query"`
+}
+
+type ListProfilesResponseData struct {
+	Profiles []ImportProfile `json:"profiles"`
+}
+
+// How the rows of a file would be imported, with the mapping completed by
+// what was detected so it can be reviewed, adjusted and saved as a profile
+type CSVPreview struct {
+	Mapping   CSVMapping  `json:"mapping"`
+	Header    []string    `json:"header"`
+	Rows      []ParsedRow `json:"rows"`
+	TotalRows int         `json:"total_rows"`
+}
+
+// A row of an imported file, error is set when it can't be imported
+type ParsedRow struct {
+	Row      int                       `json:"row"`
+	Date     string                    `json:"date"`
+	Name     string                    `json:"name"`
+	Amount   float64                   `json:"amount"`
+	Type     constants.TransactionType `json:"type"`
+	Note     *string                   `json:"note"`
+	Category *string                   `json:"category"`
+	Payee    *string                   `json:"payee"`
+	Error    *string                   `json:"error"`
+}
+
+// A bank or credit card statement of an OFX file. When an account is given
+// its balance on the ledger date is reported to be compared with the bank's
+type OFXStatement struct {
+	BankID            *string          `json:"bank_id"`
+	AccountID         *string          `json:"account_id"`
+	AccountType       string           `json:"account_type"`
+	Currency          *string          `json:"currency"`
+	StartDate         *string          `json:"start_date"`
+	EndDate           *string          `json:"end_date"`
+	LedgerBalance     *float64         `json:"ledger_balance"`
+	LedgerBalanceDate *string          `json:"ledger_balance_date"`
+	AccountBalance    *float64         `json:"account_balance"`
+	BalanceDifference *float64         `json:"balance_difference"`
+	TransactionCount  int              `json:"transaction_count"`
+	Transactions      []OFXTransaction `json:"-"`
+}
+
+// A STMTTRN record, row is its position in the file
+type OFXTransaction struct {
+	Row             int                       `json:"row"`
+	FITID           string                    `json:"fitid"`
+	TransactionType string                    `json:"transaction_type"`
+	Date            string                    `json:"date"`
+	Name            string                    `json:"name"`
+	Amount          float64                   `json:"amount"`
+	Type            constants.TransactionType `json:"type"`
+	Note            *string                   `json:"note"`
+	Error           *string                   `json:"error"`
+}
+
+// The transactions of the bank, credit card and cash sections of a QIF file
+// and the categories it lists
+type QIFFile struct {
+	Categories   []string
+	Transactions []QIFTransaction
+}
+
+// A QIF record, row is its position among the transactions of the file. A
+// record with splits is imported as a transaction per split
+type QIFTransaction struct {
+	Row        int
+	ExternalID string
+	Date       string
+	Amount     float64
+	Payee      *string
+	Memo       *string
+	Category   *string
+	Account    *string
+	Splits     []QIFSplit
+	Error      *string
+}
+
+type QIFSplit struct {
+	Category *string
+	Memo     *string
+	Amount   float64
+}
+
+// An NF-e or NFC-e, the issuer document is the CNPJ or, rarely, the CPF
+type NFeInvoice struct {
+	AccessKey      string           `json:"access_key"`
+	Model          string           `json:"model"`
+	Number         string           `json:"number"`
+	Series         string           `json:"series"`
+	IssueDate      string           `json:"issue_date"`
+	IssuerName     string           `json:"issuer_name"`
+	IssuerDocument string           `json:"issuer_document"`
+	Total          float64          `json:"total"`
+	Items          []NFeItem        `json:"items"`
+	Installments   []NFeInstallment `json:"installments"`
+	Payments       []NFePayment     `json:"payments"`
+}
+
+// A product of an invoice, amount is net of discount
+type NFeItem struct {
+	Code        *string `json:"code"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	Unit        *string `json:"unit"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+// A dup entry of the invoice billing
+type NFeInstallment struct {
+	Number  string  `json:"number"`
+	DueDate string  `json:"due_date"`
+	Amount  float64 `json:"amount"`
+}
+
+type NFePayment struct {
+	Method      string  `json:"method"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+type RowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// CSVMapping tells how to read a CSV export. Columns are zero based indexes
+// and every field left empty is detected from the file
+type CSVMapping struct {
+	Delimiter        string  `json:"delimiter"`
+	Encoding         string  `json:"encoding"`
+	HasHeader        *bool   `json:"has_header"`
+	DecimalSeparator string  `json:"decimal_separator"`
+	DateFormat       string  `json:"date_format"`
+	DateColumn       *int    `json:"date_column"`
+	NameColumn       *int    `json:"name_column"`
+	AmountColumn     *int    `json:"amount_column"`
+	NoteColumn       *int    `json:"note_column"`
+	CategoryColumn   *int    `json:"category_column"`
+	PayeeColumn      *int    `json:"payee_column"`
+	InvertAmounts    bool    `json:"invert_amounts"`
+	Account          *string `json:"account"`
+}
+
+type CreateProfileDTO struct {
+	UserID  string
+	Name    string
+	Mapping CSVMapping
+}
+
+type CreateBatchDTO struct {
+	UserID   string
+	Source   string
+	FileName *string
+}
+
+// A row ready to be created, the reason it can't be, or whether it was
+// already imported before
+type importRow struct {
+	Row         int
+	Transaction transactions.CreateTransactionDTO
+	Err         error
+	Duplicate   bool
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Import profiles table record, a mapping saved to import files of a bank
+type ImportProfile struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Mapping   CSVMapping `json:"mapping"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Import batches table record, the transactions created by one import
+type ImportBatch struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	Source       string    `json:"source"`
+	FileName     *string   `json:"file_name"`
+	CreatedCount int       `json:"created_count"`
+	FailedCount  int       `json:"failed_count"`
+	SkippedCount int       `json:"skipped_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
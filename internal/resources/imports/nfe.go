@@ -0,0 +1,341 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// Descriptions of the payment methods (tPag) of an invoice
+var nfePaymentMethods = map[string]string{
+	"01": "Dinheiro",
+	"02": "Cheque",
+	"03": "Cartão de Crédito",
+	"04": "Cartão de Débito",
+	"05": "Crédito Loja",
+	"10": "Vale Alimentação",
+	"11": "Vale Refeição",
+	"12": "Vale Presente",
+	"13": "Vale Combustível",
+	"15": "Boleto Bancário",
+	"16": "Depósito Bancário",
+	"17": "PIX",
+	"18": "Transferência bancária",
+	"19": "Programa de fidelidade",
+	"90": "Sem pagamento",
+	"99": "Outros",
+}
+
+type nfeInfo struct {
+	ID  string `xml:"Id,attr"`
+	Ide struct {
+		Mod   string `xml:"mod"`
+		Serie string `xml:"serie"`
+		NNF   string `xml:"nNF"`
+		DhEmi string `xml:"dhEmi"`
+		DEmi  string `xml:"dEmi"`
+	} `xml:"ide"`
+	Emit struct {
+		CNPJ  string `xml:"CNPJ"`
+		CPF   string `xml:"CPF"`
+		XNome string `xml:"xNome"`
+		XFant string `xml:"xFant"`
+	} `xml:"emit"`
+	Det []struct {
+		Prod struct {
+			CProd  string `xml:"cProd"`
+			XProd  string `xml:"xProd"`
+			QCom   string `xml:"qCom"`
+			UCom   string `xml:"uCom"`
+			VUnCom string `xml:"vUnCom"`
+			VProd  string `xml:"vProd"`
+			VDesc  string `xml:"vDesc"`
+		} `xml:"prod"`
+	} `xml:"det"`
+	Total struct {
+		ICMSTot struct {
+			VNF string `xml:"vNF"`
+		} `xml:"ICMSTot"`
+	} `xml:"total"`
+	Cobr struct {
+		Dup []struct {
+			NDup  string `xml:"nDup"`
+			DVenc string `xml:"dVenc"`
+			VDup  string `xml:"vDup"`
+		} `xml:"dup"`
+	} `xml:"cobr"`
+	Pag struct {
+		DetPag []nfePayment `xml:"detPag"`
+		// layouts before 4.0 have a single payment without detPag
+		nfePayment
+	} `xml:"pag"`
+}
+
+type nfePayment struct {
+	TPag string `xml:"tPag"`
+	VPag string `xml:"vPag"`
+}
+
+// The root is nfeProc when the authorization protocol is attached, or NFe
+type nfeDocument struct {
+	XMLName xml.Name
+	NFe     struct {
+		InfNFe nfeInfo `xml:"infNFe"`
+	} `xml:"NFe"`
+	InfNFe  nfeInfo `xml:"infNFe"`
+	ProtNFe struct {
+		InfProt struct {
+			ChNFe string `xml:"chNFe"`
+		} `xml:"infProt"`
+	} `xml:"protNFe"`
+}
+
+// ValidAccessKey checks the 44 digits of an access key and its mod 11
+// check digit
+func ValidAccessKey(key string) bool {
+	if len(key) != 44 {
+		return false
+	}
+
+	sum, weight := 0, 2
+	for i := 42; i >= 0; i-- {
+		digit := key[i] - '0'
+		if digit > 9 {
+			return false
+		}
+		sum += int(digit) * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	checkDigit := 11 - sum%11
+	if checkDigit >= 10 {
+		checkDigit = 0
+	}
+
+	return key[43] == byte('0'+checkDigit)
+}
+
+func parseNFeDecimal(value string, field string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s '%s'", field, value))
+	}
+	return number, nil
+}
+
+// formatCNPJ formats the 14 digits of a CNPJ, a CPF is kept as is
+func formatCNPJ(cnpj string) string {
+	if len(cnpj) != 14 {
+		return cnpj
+	}
+	return cnpj[:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:]
+}
+
+// ParseNFe reads an NF-e (model 55) or NFC-e (model 65) XML, with or without
+// the authorization protocol
+func ParseNFe(data []byte) (NFeInvoice, error) {
+	var document nfeDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// invoices are UTF-8, some declare it in a way encoding/xml doesn't know
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&document); err != nil {
+		return NFeInvoice{}, ErrInvalidNFe
+	}
+
+	info := document.InfNFe
+	if document.XMLName.Local == "nfeProc" {
+		info = document.NFe.InfNFe
+	}
+
+	key := strings.TrimPrefix(info.ID, "NFe")
+	if key == "" {
+		key = document.ProtNFe.InfProt.ChNFe
+	}
+	if key == "" || info.Ide.NNF == "" {
+		return NFeInvoice{}, ErrInvalidNFe
+	}
+	if !ValidAccessKey(key) {
+		return NFeInvoice{}, ErrInvalidAccessKey
+	}
+
+	issued := info.Ide.DhEmi
+	if issued == "" {
+		issued = info.Ide.DEmi
+	}
+	if len(issued) < 10 {
+		return NFeInvoice{}, utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue date '%s'", issued))
+	}
+	issueDate, err := time.Parse("2006-01-02", issued[:10])
+	if err != nil {
+		return NFeInvoice{}, utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue date '%s'", issued))
+	}
+
+	total, err := parseNFeDecimal(info.Total.ICMSTot.VNF, "vNF")
+	if err != nil {
+		return NFeInvoice{}, err
+	}
+	if total <= 0 {
+		return NFeInvoice{}, utils.NewHTTPError(http.StatusBadRequest, "invoice total must be greater than zero")
+	}
+
+	issuerName := strings.TrimSpace(info.Emit.XFant)
+	if issuerName == "" {
+		issuerName = strings.TrimSpace(info.Emit.XNome)
+	}
+	if issuerName == "" {
+		return NFeInvoice{}, ErrInvalidNFe
+	}
+
+	issuerDocument := info.Emit.CNPJ
+	if issuerDocument == "" {
+		issuerDocument = info.Emit.CPF
+	}
+
+	invoice := NFeInvoice{
+		AccessKey:      key,
+		Model:          info.Ide.Mod,
+		Number:         info.Ide.NNF,
+		Series:         info.Ide.Serie,
+		IssueDate:      issueDate.Format("2006-01-02"),
+		IssuerName:     truncate(issuerName, 100),
+		IssuerDocument: formatCNPJ(issuerDocument),
+		Total:          total,
+		Items:          make([]NFeItem, 0, len(info.Det)),
+		Installments:   make([]NFeInstallment, 0, len(info.Cobr.Dup)),
+		Payments:       make([]NFePayment, 0),
+	}
+
+	for i, det := range info.Det {
+		prod := det.Prod
+		quantity, err := parseNFeDecimal(prod.QCom, fmt.Sprintf("item %d quantity", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+		unitPrice, err := parseNFeDecimal(prod.VUnCom, fmt.Sprintf("item %d unit price", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+		amount, err := parseNFeDecimal(prod.VProd, fmt.Sprintf("item %d amount", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+		discount, err := parseNFeDecimal(prod.VDesc, fmt.Sprintf("item %d discount", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+
+		invoice.Items = append(invoice.Items, NFeItem{
+			Code:        optionalValue(prod.CProd, 60),
+			Description: truncate(strings.TrimSpace(prod.XProd), 120),
+			Quantity:    quantity,
+			Unit:        optionalValue(prod.UCom, 6),
+			UnitPrice:   unitPrice,
+			Amount:      math.Round((amount-discount)*100) / 100,
+		})
+	}
+
+	for i, dup := range info.Cobr.Dup {
+		dueDate, err := time.Parse("2006-01-02", strings.TrimSpace(dup.DVenc))
+		if err != nil {
+			return NFeInvoice{}, utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid due date '%s' of installment %d", dup.DVenc, i+1))
+		}
+		amount, err := parseNFeDecimal(dup.VDup, fmt.Sprintf("installment %d amount", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+		invoice.Installments = append(invoice.Installments, NFeInstallment{
+			Number:  dup.NDup,
+			DueDate: dueDate.Format("2006-01-02"),
+			Amount:  amount,
+		})
+	}
+
+	payments := info.Pag.DetPag
+	if len(payments) == 0 && info.Pag.TPag != "" {
+		payments = append(payments, info.Pag.nfePayment)
+	}
+	for i, payment := range payments {
+		amount, err := parseNFeDecimal(payment.VPag, fmt.Sprintf("payment %d amount", i+1))
+		if err != nil {
+			return NFeInvoice{}, err
+		}
+		description, ok := nfePaymentMethods[payment.TPag]
+		if !ok {
+			description = nfePaymentMethods["99"]
+		}
+		invoice.Payments = append(invoice.Payments, NFePayment{
+			Method:      payment.TPag,
+			Description: description,
+			Amount:      amount,
+		})
+	}
+
+	return invoice, nil
+}
+
+// NFeTransaction builds the transaction of an invoice, split in the
+// installments of its dup entries when there are two or more
+func NFeTransaction(invoice NFeInvoice) transactions.CreateTransactionDTO {
+	kind, documentKind := "NF-e", "CNPJ"
+	if invoice.Model == "65" {
+		kind = "NFC-e"
+	}
+	if len(invoice.IssuerDocument) < 18 {
+		documentKind = "CPF"
+	}
+
+	note := fmt.Sprintf("%s %s · %s %s", kind, invoice.Number, documentKind, invoice.IssuerDocument)
+	for _, payment := range invoice.Payments {
+		note += " · " + payment.Description
+	}
+
+	items := make([]transactions.CreateItemDTO, 0, len(invoice.Items))
+	for _, item := range invoice.Items {
+		items = append(items, transactions.CreateItemDTO(item))
+	}
+
+	payee := invoice.IssuerName
+	externalID := "nfe:" + invoice.AccessKey
+	transaction := transactions.CreateTransactionDTO{
+		Name:       invoice.IssuerName,
+		Note:       &note,
+		Type:       constants.SimpleExpense,
+		Payee:      &payee,
+		ExternalID: &externalID,
+		Items:      items,
+		Entries: []transactions.CreateEntryDTO{
+			{Amount: -invoice.Total, ReferenceDate: invoice.IssueDate},
+		},
+	}
+
+	if len(invoice.Installments) >= 2 {
+		transaction.Type = constants.Installment
+		transaction.Entries = make([]transactions.CreateEntryDTO, 0, len(invoice.Installments))
+		for _, installment := range invoice.Installments {
+			transaction.Entries = append(transaction.Entries, transactions.CreateEntryDTO{
+				Amount:        -installment.Amount,
+				ReferenceDate: installment.DueDate,
+			})
+		}
+	}
+
+	return transaction
+}
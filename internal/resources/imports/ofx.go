@@ -0,0 +1,269 @@
+package imports
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+)
+
+// ofxLeaves are the elements read from a statement, known to be leaves so an
+// empty one left unclosed in SGML isn't taken for an aggregate
+var ofxLeaves = map[string]bool{
+	"TRNTYPE": true, "DTPOSTED": true, "DTUSER": true, "DTAVAIL": true, "TRNAMT": true,
+	"FITID": true, "CORRECTFITID": true, "CORRECTACTION": true, "SRVRTID": true,
+	"CHECKNUM": true, "REFNUM": true, "SIC": true, "PAYEEID": true, "NAME": true,
+	"EXTDNAME": true, "MEMO": true, "BANKID": true, "BRANCHID": true, "ACCTID": true,
+	"ACCTTYPE": true, "ACCTKEY": true, "CURDEF": true, "DTSTART": true, "DTEND": true,
+	"BALAMT": true, "DTASOF": true,
+}
+
+// ofxParser walks the tags of an OFX file. Version 1.x is SGML where leaf
+// elements have no closing tag and 2.x is XML where they do, so a tag is a
+// leaf when text follows it or it's one of ofxLeaves and an aggregate
+// otherwise, and closing tags of leaves are ignored
+type ofxParser struct {
+	stack       []string
+	statements  []OFXStatement
+	statement   *OFXStatement
+	transaction map[string]string
+	rows        int
+	occurrences map[string]int
+}
+
+func (p *ofxParser) open(tag string) {
+	p.stack = append(p.stack, tag)
+
+	switch tag {
+	case "STMTRS", "CCSTMTRS":
+		accountType := "bank"
+		if tag == "CCSTMTRS" {
+			accountType = "credit_card"
+		}
+		p.statement = &OFXStatement{AccountType: accountType, Transactions: make([]OFXTransaction, 0)}
+		p.occurrences = make(map[string]int)
+	case "STMTTRN":
+		p.transaction = make(map[string]string)
+	}
+}
+
+func (p *ofxParser) leaf(tag string, value string) {
+	parent := ""
+	if len(p.stack) > 0 {
+		parent = p.stack[len(p.stack)-1]
+	}
+
+	if parent == "STMTTRN" && p.transaction != nil {
+		p.transaction[tag] = value
+		return
+	}
+
+	if p.statement == nil {
+		return
+	}
+
+	switch parent + "." + tag {
+	case "BANKACCTFROM.BANKID":
+		p.statement.BankID = &value
+	case "BANKACCTFROM.ACCTID", "CCACCTFROM.ACCTID":
+		p.statement.AccountID = &value
+	case "STMTRS.CURDEF", "CCSTMTRS.CURDEF":
+		p.statement.Currency = &value
+	case "BANKTRANLIST.DTSTART":
+		p.statement.StartDate = formatOFXDate(value)
+	case "BANKTRANLIST.DTEND":
+		p.statement.EndDate = formatOFXDate(value)
+	case "LEDGERBAL.BALAMT":
+		if amount, err := parseDetectedAmount(value); err == nil {
+			p.statement.LedgerBalance = &amount
+		}
+	case "LEDGERBAL.DTASOF":
+		p.statement.LedgerBalanceDate = formatOFXDate(value)
+	}
+}
+
+// close pops the stack up to the tag, closing aggregates left open
+func (p *ofxParser) close(tag string) {
+	index := -1
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i] == tag {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return
+	}
+
+	for len(p.stack) > index {
+		closed := p.stack[len(p.stack)-1]
+		p.stack = p.stack[:len(p.stack)-1]
+
+		switch closed {
+		case "STMTTRN":
+			if p.transaction != nil && p.statement != nil {
+				p.rows++
+				p.statement.Transactions = append(p.statement.Transactions, p.buildTransaction(p.transaction))
+			}
+			p.transaction = nil
+		case "STMTRS", "CCSTMTRS":
+			if p.statement != nil {
+				p.statement.TransactionCount = len(p.statement.Transactions)
+				p.statements = append(p.statements, *p.statement)
+			}
+			p.statement = nil
+		}
+	}
+}
+
+func (p *ofxParser) buildTransaction(fields map[string]string) OFXTransaction {
+	transaction := OFXTransaction{
+		Row:             p.rows,
+		FITID:           fields["FITID"],
+		TransactionType: fields["TRNTYPE"],
+		Name:            truncate(fields["NAME"], 100),
+	}
+
+	memo := fields["MEMO"]
+	if transaction.Name == "" {
+		transaction.Name = truncate(memo, 100)
+	} else if memo != "" && !strings.EqualFold(memo, fields["NAME"]) {
+		note := truncate(memo, 400)
+		transaction.Note = &note
+	}
+
+	// a FITID is required by the spec but some banks leave it empty, so one
+	// is derived from the transaction, numbered to keep same day repeats apart
+	if transaction.FITID == "" {
+		key := fields["DTPOSTED"] + "|" + fields["TRNAMT"] + "|" + fields["NAME"] + "|" + memo
+		p.occurrences[key]++
+		sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, p.occurrences[key])))
+		transaction.FITID = "sha1:" + hex.EncodeToString(sum[:])
+	}
+
+	fail := func(message string) OFXTransaction {
+		transaction.Error = &message
+		return transaction
+	}
+
+	posted := fields["DTPOSTED"]
+	if posted == "" {
+		posted = fields["DTUSER"]
+	}
+	date := formatOFXDate(posted)
+	if date == nil {
+		return fail(fmt.Sprintf("invalid date '%s'", posted))
+	}
+	transaction.Date = *date
+
+	amount, err := parseDetectedAmount(fields["TRNAMT"])
+	if err != nil {
+		return fail(err.Error())
+	}
+	transaction.Amount = amount
+
+	switch {
+	case amount < 0:
+		transaction.Type = constants.SimpleExpense
+	case amount > 0:
+		transaction.Type = constants.Income
+	default:
+		return fail("amount must not be zero")
+	}
+
+	if transaction.Name == "" {
+		return fail("missing name")
+	}
+
+	return transaction
+}
+
+// formatOFXDate reads the date part of YYYYMMDDHHMMSS.XXX[-3:BRT]
+func formatOFXDate(value string) *string {
+	if len(value) < 8 {
+		return nil
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return nil
+	}
+
+	formatted := date.Format("2006-01-02")
+	return &formatted
+}
+
+// parseDetectedAmount reads amounts with decimal point, or decimal comma as
+// some brazilian banks send them
+func parseDetectedAmount(value string) (float64, error) {
+	return parseAmount(value, detectDecimalSeparator([]string{value}))
+}
+
+// ParseOFX reads the bank and credit card statements of an OFX file, either
+// version 1.x (SGML) or 2.x (XML)
+func ParseOFX(data []byte) ([]OFXStatement, error) {
+	text := decode(data, detectEncoding(data))
+
+	start := strings.Index(strings.ToUpper(text), "<OFX>")
+	if start == -1 {
+		return nil, ErrInvalidOFX
+	}
+	text = text[start:]
+
+	parser := &ofxParser{statements: make([]OFXStatement, 0)}
+	pending, lastLeaf := "", ""
+	for len(text) > 0 {
+		open := strings.IndexByte(text, '<')
+		if open == -1 {
+			break
+		}
+		value := strings.TrimSpace(html.UnescapeString(text[:open]))
+
+		end := strings.IndexByte(text[open:], '>')
+		if end == -1 {
+			break
+		}
+		token := strings.TrimSpace(text[open+1 : open+end])
+		text = text[open+end+1:]
+
+		// the previous tag is now known to be a leaf or an aggregate
+		if pending != "" {
+			if value != "" || ofxLeaves[pending] {
+				parser.leaf(pending, value)
+				lastLeaf = pending
+			} else {
+				parser.open(pending)
+				lastLeaf = ""
+			}
+			pending = ""
+		}
+
+		switch {
+		case token == "" || strings.HasPrefix(token, "?") || strings.HasPrefix(token, "!") || strings.HasSuffix(token, "/"):
+			continue
+		case strings.HasPrefix(token, "/"):
+			tag := strings.ToUpper(strings.TrimSpace(token[1:]))
+			if tag != lastLeaf {
+				parser.close(tag)
+			}
+			lastLeaf = ""
+		default:
+			pending = strings.ToUpper(strings.Fields(token)[0])
+		}
+	}
+
+	// files cut short still have their open statements read
+	if len(parser.stack) > 0 {
+		parser.close(parser.stack[0])
+	}
+
+	if len(parser.statements) == 0 {
+		return nil, ErrInvalidOFX
+	}
+
+	return parser.statements, nil
+}
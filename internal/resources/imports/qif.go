@@ -0,0 +1,355 @@
+package imports
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+// Date formats a QIF file may use, the american one is the default
+var qifDateFormats = []string{"MM/DD/YYYY", "DD/MM/YYYY"}
+
+// Sections holding transactions, investment and other sections are skipped
+var qifTransactionSections = map[string]bool{
+	"bank":  true,
+	"ccard": true,
+	"cash":  true,
+}
+
+// qifParser reads a QIF file line by line, nextAccount being the account of
+// the last !Account block that the next section takes
+type qifParser struct {
+	dateFormat  string
+	file        QIFFile
+	section     string
+	account     *string
+	nextAccount *string
+	fields      [][2]string
+	records     int
+	occurrences map[string]int
+}
+
+// parseQIFDate reads dates like 04/03/2025, 4/3'25 or 4/ 3/25, an
+// apostrophe before the year meaning the 2000s
+func parseQIFDate(value string, dateFormat string) (string, error) {
+	normalized := strings.NewReplacer("'", "/", "-", "/", ".", "/", " ", "").Replace(value)
+	parts := strings.Split(normalized, "/")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid date '%s'", value)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return "", fmt.Errorf("invalid date '%s'", value)
+		}
+		numbers[i] = number
+	}
+
+	var day, month, year int
+	switch {
+	case len(parts[0]) == 4:
+		year, month, day = numbers[0], numbers[1], numbers[2]
+	case dateFormat == "DD/MM/YYYY":
+		day, month, year = numbers[0], numbers[1], numbers[2]
+	default:
+		month, day, year = numbers[0], numbers[1], numbers[2]
+	}
+
+	// two digit years are in the 2000s unless that is in the future
+	if year < 100 {
+		year += 2000
+		if !strings.Contains(value, "'") && year > time.Now().Year() {
+			year -= 100
+		}
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day || int(date.Month()) != month {
+		return "", fmt.Errorf("invalid date '%s'", value)
+	}
+
+	return date.Format("2006-01-02"), nil
+}
+
+// qifCategory reads the L and S fields, transfers to other accounts are
+// written between brackets and have no category
+func qifCategory(value string) *string {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, "/"); i != -1 {
+		// what follows the slash is a class
+		value = strings.TrimSpace(value[:i])
+	}
+	if value == "" || strings.HasPrefix(value, "[") {
+		return nil
+	}
+	value = truncate(value, 50)
+	return &value
+}
+
+func optionalValue(value string, size int) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	value = truncate(value, size)
+	return &value
+}
+
+func (p *qifParser) header(line string) {
+	fields := strings.SplitN(strings.TrimPrefix(line, "!"), ":", 2)
+	switch strings.ToLower(strings.TrimSpace(fields[0])) {
+	case "type":
+		p.section = ""
+		if len(fields) == 2 {
+			p.section = strings.ToLower(strings.TrimSpace(fields[1]))
+		}
+		// a section without an !Account block right before it has no account
+		p.account, p.nextAccount = p.nextAccount, nil
+	case "account":
+		p.section = "account"
+	}
+}
+
+// end handles the fields read up to a ^ line
+func (p *qifParser) end() {
+	fields := p.fields
+	p.fields = nil
+	if len(fields) == 0 {
+		return
+	}
+
+	switch {
+	case p.section == "account":
+		for _, field := range fields {
+			if field[0] == "N" {
+				p.nextAccount = optionalValue(field[1], 100)
+			}
+		}
+	case p.section == "cat":
+		for _, field := range fields {
+			if field[0] == "N" {
+				if category := qifCategory(field[1]); category != nil {
+					p.file.Categories = append(p.file.Categories, *category)
+				}
+			}
+		}
+	case qifTransactionSections[p.section]:
+		p.records++
+		p.file.Transactions = append(p.file.Transactions, p.buildTransaction(fields))
+	}
+}
+
+func (p *qifParser) buildTransaction(fields [][2]string) QIFTransaction {
+	transaction := QIFTransaction{
+		Row:     p.records,
+		Account: p.account,
+		Splits:  make([]QIFSplit, 0),
+	}
+
+	var date, amount, payee, memo string
+	for _, field := range fields {
+		value := field[1]
+		switch field[0] {
+		case "D":
+			date = value
+		case "T", "U":
+			if amount == "" {
+				amount = value
+			}
+		case "P":
+			payee = value
+			transaction.Payee = optionalValue(value, 100)
+		case "M":
+			memo = value
+			transaction.Memo = optionalValue(value, 400)
+		case "L":
+			transaction.Category = qifCategory(value)
+		case "S":
+			transaction.Splits = append(transaction.Splits, QIFSplit{Category: qifCategory(value)})
+		case "E":
+			if len(transaction.Splits) > 0 {
+				transaction.Splits[len(transaction.Splits)-1].Memo = optionalValue(value, 400)
+			}
+		case "$":
+			if len(transaction.Splits) > 0 {
+				split := &transaction.Splits[len(transaction.Splits)-1]
+				splitAmount, err := parseDetectedAmount(value)
+				if err != nil {
+					message := fmt.Sprintf("split %d: %s", len(transaction.Splits), err.Error())
+					transaction.Error = &message
+				}
+				split.Amount = splitAmount
+			}
+		}
+	}
+
+	// QIF has no identifier, so one is derived from the record, numbered to
+	// keep same day repeats apart, and a file imported again is skipped
+	account := ""
+	if p.account != nil {
+		account = *p.account
+	}
+	key := account + "|" + date + "|" + amount + "|" + payee + "|" + memo
+	p.occurrences[key]++
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, p.occurrences[key])))
+	transaction.ExternalID = "qif:sha1:" + hex.EncodeToString(sum[:])
+
+	fail := func(message string) QIFTransaction {
+		transaction.Error = &message
+		return transaction
+	}
+
+	if transaction.Error != nil {
+		return transaction
+	}
+
+	parsedDate, err := parseQIFDate(date, p.dateFormat)
+	if err != nil {
+		return fail(err.Error())
+	}
+	transaction.Date = parsedDate
+
+	parsedAmount, err := parseDetectedAmount(amount)
+	if err != nil {
+		return fail(err.Error())
+	}
+	transaction.Amount = parsedAmount
+
+	if transaction.Payee == nil && transaction.Memo == nil {
+		return fail("missing payee and memo")
+	}
+
+	// splits are imported in place of the total, so they must add up to it
+	if len(transaction.Splits) > 0 {
+		splitsTotal := 0.0
+		for _, split := range transaction.Splits {
+			splitsTotal += split.Amount
+		}
+		if math.Round(splitsTotal*100) != math.Round(transaction.Amount*100) {
+			return fail(fmt.Sprintf("splits add up to %.2f instead of the total %.2f", splitsTotal, transaction.Amount))
+		}
+	}
+
+	return transaction
+}
+
+// ParseQIF reads the bank, credit card and cash sections of a QIF file and
+// the categories it lists. Transactions take the account of the !Account
+// block right before their section
+func ParseQIF(data []byte, dateFormat string) (QIFFile, error) {
+	text := decode(data, detectEncoding(data))
+
+	parser := &qifParser{
+		dateFormat: dateFormat,
+		file: QIFFile{
+			Categories:   make([]string, 0),
+			Transactions: make([]QIFTransaction, 0),
+		},
+		occurrences: make(map[string]int),
+	}
+
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			parser.end()
+			parser.header(line)
+			found = true
+		case strings.HasPrefix(line, "^"):
+			parser.end()
+		default:
+			parser.fields = append(parser.fields, [2]string{line[:1], strings.TrimSpace(line[1:])})
+		}
+	}
+	parser.end()
+
+	if err := scanner.Err(); err != nil || !found {
+		return QIFFile{}, ErrInvalidQIF
+	}
+
+	return parser.file, nil
+}
+
+func writeQIFField(builder *strings.Builder, code string, value string) {
+	// a field takes a single line
+	value = strings.Join(strings.Fields(value), " ")
+	builder.WriteString(code + value + "\n")
+}
+
+// WriteQIF writes entries as a QIF bank section per account, each entry of
+// an installment being its own record. Entries without account go first in
+// their own section, the others must be ordered by account
+func WriteQIF(entries []transactions.ViewEntry, dateFormat string) []byte {
+	var builder strings.Builder
+
+	ordered := make([]transactions.ViewEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Account == nil {
+			ordered = append(ordered, entry)
+		}
+	}
+	for _, entry := range entries {
+		if entry.Account != nil {
+			ordered = append(ordered, entry)
+		}
+	}
+
+	first := true
+	var account *string
+	for _, entry := range ordered {
+		if first || !samePointer(account, entry.Account) {
+			if entry.Account != nil {
+				builder.WriteString("!Account\n")
+				writeQIFField(&builder, "N", *entry.Account)
+				builder.WriteString("TBank\n^\n")
+			}
+			builder.WriteString("!Type:Bank\n")
+			first, account = false, entry.Account
+		}
+
+		date, _ := time.Parse("2006-01-02", entry.ReferenceDate)
+		layout := "01/02/2006"
+		if dateFormat == "DD/MM/YYYY" {
+			layout = "02/01/2006"
+		}
+
+		name := entry.Name
+		if entry.Type == constants.Installment {
+			name = fmt.Sprintf("%s (%d/%d)", name, entry.Installment, entry.TotalInstallments)
+		}
+
+		writeQIFField(&builder, "D", date.Format(layout))
+		writeQIFField(&builder, "T", strconv.FormatFloat(entry.Amount, 'f', 2, 64))
+		writeQIFField(&builder, "P", name)
+		if entry.Description != nil {
+			writeQIFField(&builder, "M", *entry.Description)
+		}
+		if entry.CategoryName != nil {
+			writeQIFField(&builder, "L", *entry.CategoryName)
+		}
+		builder.WriteString("^\n")
+	}
+
+	return []byte(builder.String())
+}
+
+func samePointer(a *string, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
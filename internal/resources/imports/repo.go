@@ -0,0 +1,345 @@
+package imports
+
+import (
+	"encoding/json"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+)
+
+type ImportsRepo interface {
+	CreateProfile(db utils.Executer, payload CreateProfileDTO) (ImportProfile, error)
+	ListProfiles(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ImportProfile, error)
+	CountProfiles(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	DeleteProfileByID(db utils.Executer, id string) error
+	CreateBatch(db utils.Executer, payload CreateBatchDTO) (ImportBatch, error)
+	UpdateBatchCounts(db utils.Executer, id string, created int, failed int, skipped int) (ImportBatch, error)
+	ListBatches(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ImportBatch, error)
+	CountBatches(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	CreateBatchCategory(db utils.Executer, batchID string, payload categories.CreateCategoryDTO) (categories.Category, error)
+	DeleteBatchTransactions(db utils.Executer, batchID string) error
+	DeleteBatchCategories(db utils.Executer, batchID string) error
+	DeleteBatchByID(db utils.Executer, id string) error
+	ListExternalIDs(db utils.Executer, userID string, externalIDs []string) ([]string, error)
+}
+
+type ImportsRepoImpl struct {
+}
+
+func NewImportsRepo(db utils.Executer) ImportsRepo {
+	return &ImportsRepoImpl{}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProfile(row rowScanner) (ImportProfile, error) {
+	var profile ImportProfile
+	var mapping []byte
+	if err := row.Scan(
+		&profile.ID,
+		&profile.UserID,
+		&profile.Name,
+		&mapping,
+		&profile.CreatedAt,
+	); err != nil {
+		return ImportProfile{}, err
+	}
+
+	if err := json.Unmarshal(mapping, &profile.Mapping); err != nil {
+		return ImportProfile{}, err
+	}
+
+	return profile, nil
+}
+
+func scanBatch(row rowScanner) (ImportBatch, error) {
+	var batch ImportBatch
+	err := row.Scan(
+		&batch.ID,
+		&batch.UserID,
+		&batch.Source,
+		&batch.FileName,
+		&batch.CreatedCount,
+		&batch.FailedCount,
+		&batch.SkippedCount,
+		&batch.CreatedAt,
+	)
+	return batch, err
+}
+
+func (r *ImportsRepoImpl) CreateProfile(db utils.Executer, payload CreateProfileDTO) (ImportProfile, error) {
+	mapping, err := json.Marshal(payload.Mapping)
+	if err != nil {
+		return ImportProfile{}, err
+	}
+
+	query, args, err := squirrel.Insert("import_profiles").
+		Columns("id", "user_id", "name", "mapping").
+		Values(ulid.Make().String(), payload.UserID, payload.Name, mapping).
+		Suffix("RETURNING id, user_id, name, mapping, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return ImportProfile{}, err
+	}
+
+	return scanProfile(db.QueryRow(query, args...))
+}
+
+func (r *ImportsRepoImpl) ListProfiles(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ImportProfile, error) {
+	query := squirrel.Select("id", "user_id", "name", "mapping", "created_at").
+		From("import_profiles").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := make([]ImportProfile, 0)
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (r *ImportsRepoImpl) CountProfiles(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("import_profiles").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *ImportsRepoImpl) DeleteProfileByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("import_profiles").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+func (r *ImportsRepoImpl) CreateBatch(db utils.Executer, payload CreateBatchDTO) (ImportBatch, error) {
+	query, args, err := squirrel.Insert("import_batches").
+		Columns("id", "user_id", "source", "file_name").
+		Values(ulid.Make().String(), payload.UserID, payload.Source, payload.FileName).
+		Suffix("RETURNING id, user_id, source, file_name, created_count, failed_count, skipped_count, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return ImportBatch{}, err
+	}
+
+	return scanBatch(db.QueryRow(query, args...))
+}
+
+func (r *ImportsRepoImpl) UpdateBatchCounts(db utils.Executer, id string, created int, failed int, skipped int) (ImportBatch, error) {
+	query, args, err := squirrel.Update("import_batches").
+		Set("created_count", created).
+		Set("failed_count", failed).
+		Set("skipped_count", skipped).
+		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING id, user_id, source, file_name, created_count, failed_count, skipped_count, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return ImportBatch{}, err
+	}
+
+	return scanBatch(db.QueryRow(query, args...))
+}
+
+func (r *ImportsRepoImpl) ListBatches(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ImportBatch, error) {
+	query := squirrel.Select("id", "user_id", "source", "file_name", "created_count", "failed_count", "skipped_count", "created_at").
+		From("import_batches").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := make([]ImportBatch, 0)
+	for rows.Next() {
+		batch, err := scanBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+func (r *ImportsRepoImpl) CountBatches(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("import_batches").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CreateBatchCategory creates a category tagged with the import that needed it
+func (r *ImportsRepoImpl) CreateBatchCategory(db utils.Executer, batchID string, payload categories.CreateCategoryDTO) (categories.Category, error) {
+	query, args, err := squirrel.Insert("categories").
+		Columns("id", "user_id", "name", "color", "import_batch_id").
+		Values(ulid.Make().String(), payload.UserID, payload.Name, payload.Color, batchID).
+		Suffix("RETURNING id, user_id, name, color, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return categories.Category{}, err
+	}
+
+	var category categories.Category
+	err = db.QueryRow(query, args...).Scan(&category.ID, &category.UserID, &category.Name, &category.Color, &category.CreatedAt)
+
+	return category, err
+}
+
+// DeleteBatchCategories deletes the categories created by an import that no
+// transaction uses, those the user kept using stay
+func (r *ImportsRepoImpl) DeleteBatchCategories(db utils.Executer, batchID string) error {
+	sql, args, err := squirrel.Delete("categories").
+		Where(squirrel.Eq{"import_batch_id": batchID}).
+		Where("not exists (select 1 from transactions t where t.category_id = categories.id)").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+// DeleteBatchTransactions deletes the transactions created by an import,
+// their entries are deleted in cascade
+func (r *ImportsRepoImpl) DeleteBatchTransactions(db utils.Executer, batchID string) error {
+	sql, args, err := squirrel.Delete("transactions").
+		Where(squirrel.Eq{"import_batch_id": batchID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+func (r *ImportsRepoImpl) DeleteBatchByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("import_batches").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+// ListExternalIDs returns which of the external IDs the user already imported
+func (r *ImportsRepoImpl) ListExternalIDs(db utils.Executer, userID string, externalIDs []string) ([]string, error) {
+	sql, args, err := squirrel.Select("external_id").
+		From("transactions").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where("external_id = ANY(?)", pq.Array(externalIDs)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make([]string, 0)
+	for rows.Next() {
+		var externalID string
+		if err := rows.Scan(&externalID); err != nil {
+			return nil, err
+		}
+		imported = append(imported, externalID)
+	}
+
+	return imported, nil
+}
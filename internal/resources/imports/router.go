@@ -0,0 +1,63 @@
+package imports
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	group := router.Group("/api/v1/imports")
+	{
+		group.GET("",
+			middlewares.RequireAuthMiddleware(jwtService),
+			middlewares.QueryOptsMiddleware(),
+			handler.ListBatches)
+		group.POST("/csv/preview",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.PreviewCSV)
+		group.POST("/csv",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ImportCSV)
+		group.POST("/ofx",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ImportOFX)
+		group.POST("/qif",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ImportQIF)
+		group.POST("/nfe",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ImportNFe)
+		group.POST("/profiles",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.CreateProfile)
+		group.GET("/profiles",
+			middlewares.RequireAuthMiddleware(jwtService),
+			middlewares.QueryOptsMiddleware(),
+			handler.ListProfiles)
+		group.DELETE("/profiles/:profile_id",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.DeleteProfileByID)
+		group.DELETE("/:batch_id",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.UndoBatch)
+	}
+	exports := router.Group("/api/v1/exports")
+	{
+		exports.GET("/qif",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ExportQIF)
+	}
+}
@@ -0,0 +1,769 @@
+package imports
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// how many parsed rows a preview returns
+const previewRows = 20
+
+// most rows a single import creates
+const maxImportRows = 5000
+
+type ImportsUseCase interface {
+	PreviewCSV(userID string, data []byte, mapping CSVMapping, profileID *string) (CSVPreview, error)
+	ImportCSV(userID string, fileName *string, data []byte, mapping CSVMapping, profileID *string) (ImportBatch, []RowError, error)
+	ImportOFX(userID string, fileName *string, data []byte, account *string) (ImportBatch, []OFXStatement, []RowError, error)
+	ImportQIF(userID string, fileName *string, data []byte, dateFormat string, account *string) (ImportBatch, []categories.Category, []RowError, error)
+	ExportQIF(userID string, from string, to string, dateFormat string) ([]byte, error)
+	ImportNFe(userID string, fileName *string, data []byte, account *string) (ImportBatch, NFeInvoice, []RowError, error)
+	CreateProfile(payload CreateProfileDTO) (ImportProfile, error)
+	ListProfiles(filter *utils.QueryOptsBuilder) ([]ImportProfile, error)
+	CountProfiles(filter *utils.QueryOptsBuilder) (int, error)
+	DeleteProfileByID(id string, userID string) error
+	ListBatches(filter *utils.QueryOptsBuilder) ([]ImportBatch, error)
+	CountBatches(filter *utils.QueryOptsBuilder) (int, error)
+	UndoBatch(id string, userID string) error
+}
+
+type ImportsUseCaseImpl struct {
+	repo                ImportsRepo
+	transactionsUseCase transactions.TransactionsUseCase
+	categoriesUseCase   categories.CategoriesUseCase
+	db                  *sql.DB
+}
+
+func NewImportsUseCase(repo ImportsRepo, transactionsUseCase transactions.TransactionsUseCase, categoriesUseCase categories.CategoriesUseCase, db *sql.DB) ImportsUseCase {
+	return &ImportsUseCaseImpl{
+		repo:                repo,
+		transactionsUseCase: transactionsUseCase,
+		categoriesUseCase:   categoriesUseCase,
+		db:                  db,
+	}
+}
+
+// MergeMapping fills the fields left empty in mapping with the ones of base
+func MergeMapping(base CSVMapping, mapping CSVMapping) CSVMapping {
+	merged := base
+	if mapping.Delimiter != "" {
+		merged.Delimiter = mapping.Delimiter
+	}
+	if mapping.Encoding != "" {
+		merged.Encoding = mapping.Encoding
+	}
+	if mapping.HasHeader != nil {
+		merged.HasHeader = mapping.HasHeader
+	}
+	if mapping.DecimalSeparator != "" {
+		merged.DecimalSeparator = mapping.DecimalSeparator
+	}
+	if mapping.DateFormat != "" {
+		merged.DateFormat = mapping.DateFormat
+	}
+	if mapping.DateColumn != nil {
+		merged.DateColumn = mapping.DateColumn
+	}
+	if mapping.NameColumn != nil {
+		merged.NameColumn = mapping.NameColumn
+	}
+	if mapping.AmountColumn != nil {
+		merged.AmountColumn = mapping.AmountColumn
+	}
+	if mapping.NoteColumn != nil {
+		merged.NoteColumn = mapping.NoteColumn
+	}
+	if mapping.CategoryColumn != nil {
+		merged.CategoryColumn = mapping.CategoryColumn
+	}
+	if mapping.PayeeColumn != nil {
+		merged.PayeeColumn = mapping.PayeeColumn
+	}
+	if mapping.InvertAmounts {
+		merged.InvertAmounts = true
+	}
+	if mapping.Account != nil {
+		merged.Account = mapping.Account
+	}
+	return merged
+}
+
+// resolveMapping uses the mapping of a saved profile as the base of the one
+// sent with the file
+func (uc *ImportsUseCaseImpl) resolveMapping(userID string, mapping CSVMapping, profileID *string) (CSVMapping, error) {
+	if profileID == nil {
+		return mapping, nil
+	}
+
+	profiles, err := uc.repo.ListProfiles(uc.db, utils.QueryOpts().
+		And("id", "eq", *profileID).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return CSVMapping{}, ErrFailedToListProfiles
+	}
+
+	if len(profiles) == 0 {
+		return CSVMapping{}, ErrProfileNotFound
+	}
+
+	return MergeMapping(profiles[0].Mapping, mapping), nil
+}
+
+// readCSVRows reads and parses every data row, numbering them by their line
+// in the file
+func (uc *ImportsUseCaseImpl) readCSVRows(userID string, data []byte, mapping CSVMapping, profileID *string) (CSVPreview, []ParsedRow, error) {
+	mapping, err := uc.resolveMapping(userID, mapping, profileID)
+	if err != nil {
+		return CSVPreview{}, nil, err
+	}
+
+	mapping, header, records, err := ReadCSV(data, mapping)
+	if err != nil {
+		return CSVPreview{}, nil, err
+	}
+
+	offset := 1
+	if len(header) > 0 {
+		offset = 2
+	}
+
+	rows := make([]ParsedRow, 0, len(records))
+	for i, record := range records {
+		rows = append(rows, ParseCSVRow(record, mapping, i+offset))
+	}
+
+	return CSVPreview{
+		Mapping:   mapping,
+		Header:    header,
+		TotalRows: len(rows),
+	}, rows, nil
+}
+
+func (uc *ImportsUseCaseImpl) PreviewCSV(userID string, data []byte, mapping CSVMapping, profileID *string) (CSVPreview, error) {
+	preview, rows, err := uc.readCSVRows(userID, data, mapping, profileID)
+	if err != nil {
+		return CSVPreview{}, err
+	}
+
+	preview.Rows = rows[:min(len(rows), previewRows)]
+
+	return preview, nil
+}
+
+func (uc *ImportsUseCaseImpl) ImportCSV(userID string, fileName *string, data []byte, mapping CSVMapping, profileID *string) (ImportBatch, []RowError, error) {
+	preview, rows, err := uc.readCSVRows(userID, data, mapping, profileID)
+	if err != nil {
+		return ImportBatch{}, nil, err
+	}
+
+	if len(rows) > maxImportRows {
+		return ImportBatch{}, nil, ErrTooManyRows
+	}
+
+	userCategories, err := uc.categoriesUseCase.List(utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return ImportBatch{}, nil, ErrFailedToListCategories
+	}
+
+	// categories are matched by name, ignoring case
+	categoryIDs := make(map[string]string)
+	for _, category := range userCategories {
+		categoryIDs[strings.ToLower(category.Name)] = category.ID
+	}
+
+	toImport := make([]importRow, 0, len(rows))
+	for _, row := range rows {
+		if row.Error != nil {
+			toImport = append(toImport, importRow{Row: row.Row, Err: utils.NewHTTPError(http.StatusBadRequest, *row.Error)})
+			continue
+		}
+
+		var categoryID *string
+		if row.Category != nil {
+			if id, ok := categoryIDs[strings.ToLower(*row.Category)]; ok {
+				categoryID = &id
+			}
+		}
+
+		toImport = append(toImport, importRow{
+			Row: row.Row,
+			Transaction: transactions.CreateTransactionDTO{
+				UserID:     userID,
+				Name:       row.Name,
+				Note:       row.Note,
+				Type:       row.Type,
+				CategoryID: categoryID,
+				Payee:      row.Payee,
+				Account:    preview.Mapping.Account,
+				Entries: []transactions.CreateEntryDTO{
+					{Amount: row.Amount, ReferenceDate: row.Date},
+				},
+			},
+		})
+	}
+
+	return uc.importTransactions(userID, "csv", fileName, toImport)
+}
+
+// ofxExternalID identifies a transaction by its FITID, which is only unique
+// within the account of the statement
+func ofxExternalID(statement OFXStatement, fitid string) string {
+	bankID, accountID := "", ""
+	if statement.BankID != nil {
+		bankID = *statement.BankID
+	}
+	if statement.AccountID != nil {
+		accountID = *statement.AccountID
+	}
+	return truncate("ofx:"+bankID+":"+accountID+":"+fitid, 255)
+}
+
+// ImportOFX creates the transactions of every statement of the file, skipping
+// the ones whose FITID was already imported
+func (uc *ImportsUseCaseImpl) ImportOFX(userID string, fileName *string, data []byte, account *string) (ImportBatch, []OFXStatement, []RowError, error) {
+	if account != nil && utf8.RuneCountInString(*account) > 100 {
+		return ImportBatch{}, nil, nil, ErrInvalidAccount
+	}
+
+	statements, err := ParseOFX(data)
+	if err != nil {
+		return ImportBatch{}, nil, nil, err
+	}
+
+	externalIDs := make([]string, 0)
+	for _, statement := range statements {
+		for _, transaction := range statement.Transactions {
+			externalIDs = append(externalIDs, ofxExternalID(statement, transaction.FITID))
+		}
+	}
+
+	if len(externalIDs) == 0 {
+		return ImportBatch{}, nil, nil, ErrEmptyFile
+	}
+
+	if len(externalIDs) > maxImportRows {
+		return ImportBatch{}, nil, nil, ErrTooManyRows
+	}
+
+	imported, err := uc.repo.ListExternalIDs(uc.db, userID, externalIDs)
+	if err != nil {
+		return ImportBatch{}, nil, nil, ErrFailedToCheckImported
+	}
+
+	seen := make(map[string]bool)
+	for _, externalID := range imported {
+		seen[externalID] = true
+	}
+
+	rows := make([]importRow, 0, len(externalIDs))
+	for _, statement := range statements {
+		for _, transaction := range statement.Transactions {
+			externalID := ofxExternalID(statement, transaction.FITID)
+			if seen[externalID] {
+				rows = append(rows, importRow{Row: transaction.Row, Duplicate: true})
+				continue
+			}
+			seen[externalID] = true
+
+			if transaction.Error != nil {
+				rows = append(rows, importRow{Row: transaction.Row, Err: utils.NewHTTPError(http.StatusBadRequest, *transaction.Error)})
+				continue
+			}
+
+			rows = append(rows, importRow{
+				Row: transaction.Row,
+				Transaction: transactions.CreateTransactionDTO{
+					UserID:     userID,
+					Name:       transaction.Name,
+					Note:       transaction.Note,
+					Type:       transaction.Type,
+					Account:    account,
+					ExternalID: &externalID,
+					Entries: []transactions.CreateEntryDTO{
+						{Amount: transaction.Amount, ReferenceDate: transaction.Date},
+					},
+				},
+			})
+		}
+	}
+
+	batch, rowErrors, err := uc.importTransactions(userID, "ofx", fileName, rows)
+	if err != nil {
+		return ImportBatch{}, nil, nil, err
+	}
+
+	// the balance of the account is only comparable after the import
+	if account != nil {
+		for i, statement := range statements {
+			if statement.LedgerBalance == nil || statement.LedgerBalanceDate == nil {
+				continue
+			}
+
+			aggregate, err := uc.transactionsUseCase.AggregateViewEntries(utils.QueryOpts().
+				And("user_id", "eq", userID).
+				And("account", "eq", *account).
+				And("reference_date", "lte", *statement.LedgerBalanceDate))
+			if err != nil {
+				return ImportBatch{}, nil, nil, ErrFailedToGetBalance
+			}
+
+			balance := aggregate.Net
+			difference := math.Round((*statement.LedgerBalance-balance)*100) / 100
+			statements[i].AccountBalance = &balance
+			statements[i].BalanceDifference = &difference
+		}
+	}
+
+	return batch, statements, rowErrors, nil
+}
+
+// Colors given to categories created by an import
+var categoryColors = []string{"#EF4444", "#F97316", "#EAB308", "#22C55E", "#14B8A6", "#3B82F6", "#8B5CF6", "#EC4899"}
+
+func validDateFormat(dateFormat string) bool {
+	for _, format := range qifDateFormats {
+		if format == dateFormat {
+			return true
+		}
+	}
+	return false
+}
+
+// qifExternalIDs are the external IDs of the transactions a QIF record is
+// imported as, one per split
+func qifExternalIDs(transaction QIFTransaction) []string {
+	if len(transaction.Splits) == 0 {
+		return []string{transaction.ExternalID}
+	}
+	externalIDs := make([]string, len(transaction.Splits))
+	for i := range transaction.Splits {
+		externalIDs[i] = fmt.Sprintf("%s:%d", transaction.ExternalID, i+1)
+	}
+	return externalIDs
+}
+
+// ImportQIF creates the transactions of a QIF file, creating the categories
+// it uses that the user doesn't have yet, subcategories keep the
+// Category:Subcategory name. Records already imported are skipped
+func (uc *ImportsUseCaseImpl) ImportQIF(userID string, fileName *string, data []byte, dateFormat string, account *string) (ImportBatch, []categories.Category, []RowError, error) {
+	if !validDateFormat(dateFormat) {
+		return ImportBatch{}, nil, nil, ErrInvalidDateFormat
+	}
+
+	if account != nil && utf8.RuneCountInString(*account) > 100 {
+		return ImportBatch{}, nil, nil, ErrInvalidAccount
+	}
+
+	file, err := ParseQIF(data, dateFormat)
+	if err != nil {
+		return ImportBatch{}, nil, nil, err
+	}
+
+	if len(file.Transactions) == 0 {
+		return ImportBatch{}, nil, nil, ErrEmptyFile
+	}
+
+	externalIDs := make([]string, 0)
+	for _, transaction := range file.Transactions {
+		externalIDs = append(externalIDs, qifExternalIDs(transaction)...)
+	}
+	total := len(externalIDs)
+	if total > maxImportRows {
+		return ImportBatch{}, nil, nil, ErrTooManyRows
+	}
+
+	imported, err := uc.repo.ListExternalIDs(uc.db, userID, externalIDs)
+	if err != nil {
+		return ImportBatch{}, nil, nil, ErrFailedToCheckImported
+	}
+
+	seen := make(map[string]bool)
+	for _, externalID := range imported {
+		seen[externalID] = true
+	}
+
+	duplicate := make([]bool, len(file.Transactions))
+	for i, transaction := range file.Transactions {
+		for _, externalID := range qifExternalIDs(transaction) {
+			duplicate[i] = duplicate[i] || seen[externalID]
+			seen[externalID] = true
+		}
+	}
+
+	userCategories, err := uc.categoriesUseCase.List(utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return ImportBatch{}, nil, nil, ErrFailedToListCategories
+	}
+
+	categoryIDs := make(map[string]string)
+	for _, category := range userCategories {
+		categoryIDs[strings.ToLower(category.Name)] = category.ID
+	}
+
+	used := append([]string{}, file.Categories...)
+	for i, transaction := range file.Transactions {
+		if duplicate[i] {
+			continue
+		}
+		if transaction.Category != nil {
+			used = append(used, *transaction.Category)
+		}
+		for _, split := range transaction.Splits {
+			if split.Category != nil {
+				used = append(used, *split.Category)
+			}
+		}
+	}
+
+	// the batch and its categories are created together so undoing the batch
+	// also removes them
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return ImportBatch{}, nil, nil, ErrFailedToCreateBatch
+	}
+
+	batch, err := uc.repo.CreateBatch(tx, CreateBatchDTO{
+		UserID:   userID,
+		Source:   "qif",
+		FileName: fileName,
+	})
+	if err != nil {
+		tx.Rollback()
+		return ImportBatch{}, nil, nil, ErrFailedToCreateBatch
+	}
+
+	created := make([]categories.Category, 0)
+	for _, name := range used {
+		if _, ok := categoryIDs[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		category, err := uc.repo.CreateBatchCategory(tx, batch.ID, categories.CreateCategoryDTO{
+			UserID: userID,
+			Name:   name,
+			Color:  categoryColors[len(created)%len(categoryColors)],
+		})
+		if err != nil {
+			tx.Rollback()
+			return ImportBatch{}, nil, nil, ErrFailedToCreateCategory
+		}
+
+		categoryIDs[strings.ToLower(name)] = category.ID
+		created = append(created, category)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportBatch{}, nil, nil, ErrFailedToCreateCategory
+	}
+
+	categoryID := func(name *string) *string {
+		if name == nil {
+			return nil
+		}
+		id := categoryIDs[strings.ToLower(*name)]
+		return &id
+	}
+
+	rows := make([]importRow, 0, total)
+	for i, transaction := range file.Transactions {
+		if duplicate[i] {
+			rows = append(rows, importRow{Row: transaction.Row, Duplicate: true})
+			continue
+		}
+
+		if transaction.Error != nil {
+			rows = append(rows, importRow{Row: transaction.Row, Err: utils.NewHTTPError(http.StatusBadRequest, *transaction.Error)})
+			continue
+		}
+
+		name, note := transaction.Memo, (*string)(nil)
+		if transaction.Payee != nil {
+			name, note = transaction.Payee, transaction.Memo
+		}
+
+		transactionAccount := transaction.Account
+		if transactionAccount == nil {
+			transactionAccount = account
+		}
+
+		splits := transaction.Splits
+		if len(splits) == 0 {
+			splits = []QIFSplit{{Category: transaction.Category, Memo: note, Amount: transaction.Amount}}
+		}
+
+		splitExternalIDs := qifExternalIDs(transaction)
+		for j, split := range splits {
+			splitNote := split.Memo
+			if splitNote == nil {
+				splitNote = note
+			}
+
+			var transactionType constants.TransactionType
+			switch {
+			case split.Amount < 0:
+				transactionType = constants.SimpleExpense
+			case split.Amount > 0:
+				transactionType = constants.Income
+			default:
+				rows = append(rows, importRow{Row: transaction.Row, Err: utils.NewHTTPError(http.StatusBadRequest, "amount must not be zero")})
+				continue
+			}
+
+			rows = append(rows, importRow{
+				Row: transaction.Row,
+				Transaction: transactions.CreateTransactionDTO{
+					UserID:     userID,
+					Name:       truncate(*name, 100),
+					Note:       splitNote,
+					Type:       transactionType,
+					CategoryID: categoryID(split.Category),
+					Payee:      transaction.Payee,
+					Account:    transactionAccount,
+					ExternalID: &splitExternalIDs[j],
+					Entries: []transactions.CreateEntryDTO{
+						{Amount: split.Amount, ReferenceDate: transaction.Date},
+					},
+				},
+			})
+		}
+	}
+
+	batch, rowErrors, err := uc.importBatchTransactions(batch, rows)
+	if err != nil {
+		return ImportBatch{}, nil, nil, err
+	}
+
+	return batch, created, rowErrors, nil
+}
+
+// ExportQIF writes the entries of a date range as QIF, the way ImportQIF
+// reads them back
+func (uc *ImportsUseCaseImpl) ExportQIF(userID string, from string, to string, dateFormat string) ([]byte, error) {
+	if !validDateFormat(dateFormat) {
+		return nil, ErrInvalidDateFormat
+	}
+
+	fromDate, fromErr := time.Parse("2006-01-02", from)
+	toDate, toErr := time.Parse("2006-01-02", to)
+	if fromErr != nil || toErr != nil || fromDate.After(toDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	entries, err := uc.transactionsUseCase.ListViewEntries(utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("reference_date", "gte", from).
+		And("reference_date", "lte", to).
+		OrderBy("account", "asc").
+		OrderBy("reference_date", "asc").
+		OrderBy("id", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListEntries
+	}
+
+	return WriteQIF(entries, dateFormat), nil
+}
+
+// ImportNFe creates the transaction of an invoice with its items, an invoice
+// is only imported once by its access key
+func (uc *ImportsUseCaseImpl) ImportNFe(userID string, fileName *string, data []byte, account *string) (ImportBatch, NFeInvoice, []RowError, error) {
+	if account != nil && utf8.RuneCountInString(*account) > 100 {
+		return ImportBatch{}, NFeInvoice{}, nil, ErrInvalidAccount
+	}
+
+	invoice, err := ParseNFe(data)
+	if err != nil {
+		return ImportBatch{}, NFeInvoice{}, nil, err
+	}
+
+	transaction := NFeTransaction(invoice)
+	transaction.UserID = userID
+	transaction.Account = account
+
+	imported, err := uc.repo.ListExternalIDs(uc.db, userID, []string{*transaction.ExternalID})
+	if err != nil {
+		return ImportBatch{}, NFeInvoice{}, nil, ErrFailedToCheckImported
+	}
+
+	if len(imported) > 0 {
+		return ImportBatch{}, NFeInvoice{}, nil, ErrInvoiceAlreadyImported
+	}
+
+	// an invoice is a single transaction, so it fails as a whole
+	if err := transactions.ValidateCreateTransaction(transaction); err != nil {
+		return ImportBatch{}, NFeInvoice{}, nil, err
+	}
+
+	batch, rowErrors, err := uc.importTransactions(userID, "nfe", fileName, []importRow{
+		{Row: 1, Transaction: transaction},
+	})
+	if err != nil {
+		return ImportBatch{}, NFeInvoice{}, nil, err
+	}
+
+	return batch, invoice, rowErrors, nil
+}
+
+// importTransactions creates each row through CreateTransaction so rules and
+// validations apply like on any other transaction, tagging them with a batch
+// that can be undone. A row that fails doesn't stop the others and duplicated
+// rows are only counted
+func (uc *ImportsUseCaseImpl) importTransactions(userID string, source string, fileName *string, rows []importRow) (ImportBatch, []RowError, error) {
+	batch, err := uc.repo.CreateBatch(uc.db, CreateBatchDTO{
+		UserID:   userID,
+		Source:   source,
+		FileName: fileName,
+	})
+	if err != nil {
+		return ImportBatch{}, nil, ErrFailedToCreateBatch
+	}
+
+	return uc.importBatchTransactions(batch, rows)
+}
+
+// importBatchTransactions creates the rows of a batch already created
+func (uc *ImportsUseCaseImpl) importBatchTransactions(batch ImportBatch, rows []importRow) (ImportBatch, []RowError, error) {
+	rowErrors := make([]RowError, 0)
+	created, skipped := 0, 0
+	for _, row := range rows {
+		if row.Duplicate {
+			skipped++
+			continue
+		}
+
+		if row.Err == nil {
+			row.Transaction.ImportBatchID = &batch.ID
+			_, row.Err = uc.transactionsUseCase.CreateTransaction(row.Transaction)
+		}
+
+		if row.Err != nil {
+			rowErrors = append(rowErrors, RowError{
+				Row:     row.Row,
+				Message: utils.GetApiErr(row.Err).ErrorData.Message,
+			})
+			continue
+		}
+		created++
+	}
+
+	batch, err := uc.repo.UpdateBatchCounts(uc.db, batch.ID, created, len(rowErrors), skipped)
+	if err != nil {
+		return ImportBatch{}, nil, ErrFailedToCreateBatch
+	}
+
+	return batch, rowErrors, nil
+}
+
+func (uc *ImportsUseCaseImpl) CreateProfile(payload CreateProfileDTO) (ImportProfile, error) {
+	if err := validateMapping(payload.Mapping); err != nil {
+		return ImportProfile{}, err
+	}
+
+	profile, err := uc.repo.CreateProfile(uc.db, payload)
+	if err != nil {
+		return ImportProfile{}, ErrFailedToCreateProfile
+	}
+
+	return profile, nil
+}
+
+func (uc *ImportsUseCaseImpl) ListProfiles(filter *utils.QueryOptsBuilder) ([]ImportProfile, error) {
+	profiles, err := uc.repo.ListProfiles(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListProfiles
+	}
+	return profiles, nil
+}
+
+func (uc *ImportsUseCaseImpl) CountProfiles(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.CountProfiles(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountProfiles
+	}
+	return count, nil
+}
+
+func (uc *ImportsUseCaseImpl) DeleteProfileByID(id string, userID string) error {
+	exists, err := uc.repo.CountProfiles(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountProfiles
+	}
+
+	if exists == 0 {
+		return ErrProfileNotFound
+	}
+
+	if err := uc.repo.DeleteProfileByID(uc.db, id); err != nil {
+		return ErrFailedToDeleteProfile
+	}
+
+	return nil
+}
+
+func (uc *ImportsUseCaseImpl) ListBatches(filter *utils.QueryOptsBuilder) ([]ImportBatch, error) {
+	batches, err := uc.repo.ListBatches(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListBatches
+	}
+	return batches, nil
+}
+
+func (uc *ImportsUseCaseImpl) CountBatches(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.CountBatches(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountBatches
+	}
+	return count, nil
+}
+
+// UndoBatch deletes every transaction created by an import, the categories it
+// created that are left unused and the batch itself
+func (uc *ImportsUseCaseImpl) UndoBatch(id string, userID string) error {
+	exists, err := uc.repo.CountBatches(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountBatches
+	}
+
+	if exists == 0 {
+		return ErrBatchNotFound
+	}
+
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return ErrFailedToUndoBatch
+	}
+
+	if err := uc.repo.DeleteBatchTransactions(tx, id); err != nil {
+		tx.Rollback()
+		return ErrFailedToUndoBatch
+	}
+
+	if err := uc.repo.DeleteBatchCategories(tx, id); err != nil {
+		tx.Rollback()
+		return ErrFailedToUndoBatch
+	}
+
+	if err := uc.repo.DeleteBatchByID(tx, id); err != nil {
+		tx.Rollback()
+		return ErrFailedToUndoBatch
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ErrFailedToUndoBatch
+	}
+
+	return nil
+}
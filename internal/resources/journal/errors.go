@@ -0,0 +1,19 @@
+package journal
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrInvalidFormat        = utils.NewHTTPError(http.StatusBadRequest, "format must be ledger, hledger or beancount")
+	ErrInvalidDateRange     = utils.NewHTTPError(http.StatusBadRequest, "from and to must be dates in the format YYYY-MM-DD, with from not after to")
+	ErrInvalidRoot          = utils.NewHTTPError(http.StatusBadRequest, "account roots must be a single distinct word starting with an uppercase letter")
+	ErrInvalidAccountName   = utils.NewHTTPError(http.StatusBadRequest, "default_account and uncategorized must have a letter or a digit")
+	ErrInvalidCommodity     = utils.NewHTTPError(http.StatusBadRequest, "commodity must be uppercase letters, digits or '._- starting with a letter, like BRL")
+	ErrInvalidMappedAccount = utils.NewHTTPError(http.StatusBadRequest, "mapped accounts must be under the assets or liabilities root, like Liabilities:Nubank")
+	ErrFailedToGetSettings  = utils.NewHTTPError(http.StatusInternalServerError, "failed to get journal settings")
+	ErrFailedToSaveSettings = utils.NewHTTPError(http.StatusInternalServerError, "failed to save journal settings")
+	ErrFailedToListEntries  = utils.NewHTTPError(http.StatusInternalServerError, "failed to list entries")
+)
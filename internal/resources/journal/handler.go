@@ -0,0 +1,134 @@
+package journal
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	journalUseCase JournalUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	categoriesUseCase := categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db)
+	return &API{
+		journalUseCase: NewJournalUseCase(NewJournalRepo(db),
+			transactions.NewTransactionsUseCase(transactions.NewTransactionsRepo(db),
+				categoriesUseCase,
+				rules.NewRulesUseCase(rules.NewRulesRepo(db), categoriesUseCase, db),
+				db),
+			db),
+	}
+}
+
+// file extension each tool looks for
+var extensions = map[Format]string{
+	Ledger:    "ledger",
+	HLedger:   "journal",
+	Beancount: "beancount",
+}
+
+// @Summary Export a plain-text accounting journal
+// @Description Export the entries of a date range as a ledger, hledger or beancount journal. Categories become accounts under the expenses or income root and each installment is its own transaction on its date, moving money from the account of the transaction. Account names follow the journal settings of the user
+// @Tags exports
+// @Security BearerAuth
+// @Produce plain
+// @Param format query string true "Journal syntax, ledger, hledger or beancount" example(hledger)
+// @Param from query string true "First date" example(2025-01-01)
+// @Param to query string true "Last date" example(2025-12-31)
+// @Success 200 {file} file "Journal file"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /exports/journal [get]
+func (api *API) ExportJournal(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	format := Format(ctx.Query("format"))
+	from := ctx.Query("from")
+	to := ctx.Query("to")
+
+	data, err := api.journalUseCase.Export(userID, format, from, to)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"transactions-%s-%s.%s\"", from, to, extensions[format]))
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// @Summary Get journal settings
+// @Description Get the account naming scheme of journal exports, the defaults while never saved
+// @Tags exports
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} SettingsResponse "Journal settings"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /exports/journal/settings [get]
+func (api *API) GetSettings(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	settings, err := api.journalUseCase.GetSettings(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SettingsResponse{
+		Data: SettingsResponseData{
+			Settings: settings,
+		},
+	})
+}
+
+// @Summary Update journal settings
+// @Description Replace the account naming scheme of journal exports, fields left out go back to their defaults. Accounts maps the account of a transaction to a full journal account under the assets or liabilities root
+// @Tags exports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body UpdateSettingsRequest true "Settings payload"
+// @Success 200 {object} SettingsResponse "Settings updated"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /exports/journal/settings [put]
+func (api *API) UpdateSettings(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body UpdateSettingsRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	settings, err := api.journalUseCase.UpdateSettings(userID, body)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SettingsResponse{
+		Data: SettingsResponseData{
+			Settings: settings,
+		},
+	})
+}
@@ -0,0 +1,337 @@
+package journal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+// DefaultSettings is the naming scheme of a user that never changed it, the
+// english roots are the ones every tool knows without configuration
+func DefaultSettings(userID string) Settings {
+	return Settings{
+		UserID:          userID,
+		AssetsRoot:      "Assets",
+		LiabilitiesRoot: "Liabilities",
+		IncomeRoot:      "Income",
+		ExpensesRoot:    "Expenses",
+		DefaultAccount:  "Checking",
+		Uncategorized:   "Uncategorized",
+		Commodity:       "BRL",
+		Accounts:        map[string]string{},
+	}
+}
+
+var rootPattern = regexp.MustCompile(`^\p{Lu}[\p{L}\p{N}]*$`)
+
+// same rule beancount has for currencies, which ledger and hledger also accept
+var commodityPattern = regexp.MustCompile(`^[A-Z]([A-Z0-9'._-]{0,22}[A-Z0-9])?$`)
+
+// ValidateSettings checks that every format can use the names as they are
+func ValidateSettings(settings Settings) error {
+	roots := []string{settings.AssetsRoot, settings.LiabilitiesRoot, settings.IncomeRoot, settings.ExpensesRoot}
+	for i, root := range roots {
+		if !rootPattern.MatchString(root) {
+			return ErrInvalidRoot
+		}
+		for _, other := range roots[:i] {
+			if root == other {
+				return ErrInvalidRoot
+			}
+		}
+	}
+
+	if len(accountWords(settings.DefaultAccount)) == 0 || len(accountWords(settings.Uncategorized)) == 0 {
+		return ErrInvalidAccountName
+	}
+
+	if !commodityPattern.MatchString(settings.Commodity) {
+		return ErrInvalidCommodity
+	}
+
+	for from, to := range settings.Accounts {
+		components := strings.Split(to, ":")
+		if strings.TrimSpace(from) == "" || len(components) < 2 ||
+			(components[0] != settings.AssetsRoot && components[0] != settings.LiabilitiesRoot) {
+			return ErrInvalidMappedAccount
+		}
+		for _, component := range components[1:] {
+			if len(accountWords(component)) == 0 {
+				return ErrInvalidMappedAccount
+			}
+		}
+	}
+
+	return nil
+}
+
+func accountWords(component string) []string {
+	return strings.FieldsFunc(component, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// accountComponent gives every name the same shape: words start uppercase and
+// are joined by a space, or by a dash in beancount which has no spaces in names
+func accountComponent(component string, format Format) string {
+	words := accountWords(component)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+
+	if format == Beancount {
+		return strings.Join(words, "-")
+	}
+	return strings.Join(words, " ")
+}
+
+// accountName joins a root with a path whose ":" split subaccounts, like the
+// "Alimentação:Mercado" of a category
+func accountName(root string, path string, format Format) string {
+	components := []string{root}
+	for _, component := range strings.Split(path, ":") {
+		if name := accountComponent(component, format); name != "" {
+			components = append(components, name)
+		}
+	}
+	return strings.Join(components, ":")
+}
+
+type namer struct {
+	settings Settings
+	format   Format
+	mapped   map[string]string
+}
+
+func newNamer(settings Settings, format Format) namer {
+	mapped := make(map[string]string, len(settings.Accounts))
+	for from, to := range settings.Accounts {
+		components := strings.SplitN(to, ":", 2)
+		mapped[strings.ToLower(strings.TrimSpace(from))] = accountName(components[0], components[1], format)
+	}
+	return namer{settings: settings, format: format, mapped: mapped}
+}
+
+// moneyAccount is where the money of an entry came from or went to
+func (n namer) moneyAccount(entry transactions.ViewEntry) string {
+	if entry.Account == nil || strings.TrimSpace(*entry.Account) == "" {
+		return accountName(n.settings.AssetsRoot, n.settings.DefaultAccount, n.format)
+	}
+	if mapped, ok := n.mapped[strings.ToLower(strings.TrimSpace(*entry.Account))]; ok {
+		return mapped
+	}
+	// the account of a transaction is a single name, never a path
+	return accountName(n.settings.AssetsRoot, strings.ReplaceAll(*entry.Account, ":", " "), n.format)
+}
+
+// categoryAccount is the expense or income account of an entry by its sign
+func (n namer) categoryAccount(entry transactions.ViewEntry) string {
+	root := n.settings.ExpensesRoot
+	if entry.Amount > 0 {
+		root = n.settings.IncomeRoot
+	}
+
+	category := n.settings.Uncategorized
+	if entry.CategoryName != nil && len(accountWords(*entry.CategoryName)) > 0 {
+		category = *entry.CategoryName
+	}
+
+	return accountName(root, category, n.format)
+}
+
+func formatAmount(amount float64) string {
+	cents := math.Round(amount * 100)
+	if cents == 0 {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", cents/100)
+}
+
+func entryTitle(entry transactions.ViewEntry) string {
+	if entry.TotalInstallments > 1 {
+		return fmt.Sprintf("%s (%d/%d)", entry.Name, entry.Installment, entry.TotalInstallments)
+	}
+	return entry.Name
+}
+
+// tagName keeps the characters every format accepts in a tag
+func tagName(tag string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '/') {
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(tag))
+	return strings.Trim(name, "-")
+}
+
+func entryTags(entry transactions.ViewEntry) []string {
+	tags := make([]string, 0, len(entry.Tags))
+	for _, tag := range entry.Tags {
+		if name := tagName(tag); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// comment keeps a free text on a single comment line
+func comment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func beancountString(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(comment(text)) + `"`
+}
+
+// WriteJournal writes each entry as a transaction on its date, an installment
+// purchase becomes one transaction per installment. Entries must be ordered
+// by date
+func WriteJournal(entries []transactions.ViewEntry, settings Settings, format Format) []byte {
+	var b strings.Builder
+	n := newNamer(settings, format)
+
+	if format == Beancount {
+		writeBeancountHeader(&b, entries, n)
+	}
+
+	for _, entry := range entries {
+		b.WriteString("\n")
+		switch format {
+		case Beancount:
+			writeBeancountTransaction(&b, entry, n)
+		default:
+			writeLedgerTransaction(&b, entry, n)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// ledger and hledger only differ on how the payee and tags are written
+func writeLedgerTransaction(b *strings.Builder, entry transactions.ViewEntry, n namer) {
+	if n.format == HLedger && entry.Payee != nil && comment(*entry.Payee) != "" {
+		// hledger takes the text before | as the payee
+		fmt.Fprintf(b, "%s %s | %s\n", entry.ReferenceDate, strings.ReplaceAll(comment(*entry.Payee), "|", "-"), comment(entryTitle(entry)))
+	} else {
+		fmt.Fprintf(b, "%s %s\n", entry.ReferenceDate, comment(entryTitle(entry)))
+	}
+
+	if entry.Description != nil && comment(*entry.Description) != "" {
+		fmt.Fprintf(b, "    ; %s\n", comment(*entry.Description))
+	}
+
+	tags := entryTags(entry)
+	switch n.format {
+	case HLedger:
+		if entry.TotalInstallments > 1 {
+			fmt.Fprintf(b, "    ; installment:%d/%d\n", entry.Installment, entry.TotalInstallments)
+		}
+		if len(tags) > 0 {
+			b.WriteString("    ; " + strings.Join(tags, ":, ") + ":\n")
+		}
+	default:
+		if entry.Payee != nil && comment(*entry.Payee) != "" {
+			fmt.Fprintf(b, "    ; payee: %s\n", comment(*entry.Payee))
+		}
+		if entry.TotalInstallments > 1 {
+			fmt.Fprintf(b, "    ; installment: %d/%d\n", entry.Installment, entry.TotalInstallments)
+		}
+		if len(tags) > 0 {
+			b.WriteString("    ; :" + strings.Join(tags, ":") + ":\n")
+		}
+	}
+
+	writePostings(b, entry, n, "    ")
+}
+
+func writeBeancountHeader(b *strings.Builder, entries []transactions.ViewEntry, n namer) {
+	fmt.Fprintf(b, "option \"operating_currency\" \"%s\"\n", n.settings.Commodity)
+
+	defaults := DefaultSettings("")
+	names := []struct{ option, value, standard string }{
+		{"name_assets", n.settings.AssetsRoot, defaults.AssetsRoot},
+		{"name_liabilities", n.settings.LiabilitiesRoot, defaults.LiabilitiesRoot},
+		{"name_income", n.settings.IncomeRoot, defaults.IncomeRoot},
+		{"name_expenses", n.settings.ExpensesRoot, defaults.ExpensesRoot},
+	}
+	for _, name := range names {
+		if name.value != name.standard {
+			fmt.Fprintf(b, "option \"%s\" \"%s\"\n", name.option, name.value)
+		}
+	}
+
+	// beancount refuses postings to accounts that were never opened
+	opened := map[string]string{}
+	for _, entry := range entries {
+		for _, account := range []string{n.categoryAccount(entry), n.moneyAccount(entry)} {
+			if date, ok := opened[account]; !ok || entry.ReferenceDate < date {
+				opened[account] = entry.ReferenceDate
+			}
+		}
+	}
+
+	accounts := make([]openAccount, 0, len(opened))
+	for name, date := range opened {
+		accounts = append(accounts, openAccount{Name: name, Date: date})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Date != accounts[j].Date {
+			return accounts[i].Date < accounts[j].Date
+		}
+		return accounts[i].Name < accounts[j].Name
+	})
+
+	if len(accounts) > 0 {
+		b.WriteString("\n")
+	}
+	for _, account := range accounts {
+		fmt.Fprintf(b, "%s open %s %s\n", account.Date, account.Name, n.settings.Commodity)
+	}
+}
+
+func writeBeancountTransaction(b *strings.Builder, entry transactions.ViewEntry, n namer) {
+	fmt.Fprintf(b, "%s *", entry.ReferenceDate)
+	if entry.Payee != nil && comment(*entry.Payee) != "" {
+		fmt.Fprintf(b, " %s", beancountString(*entry.Payee))
+	}
+	fmt.Fprintf(b, " %s", beancountString(entryTitle(entry)))
+	for _, tag := range entryTags(entry) {
+		fmt.Fprintf(b, " #%s", tag)
+	}
+	b.WriteString("\n")
+
+	if entry.TotalInstallments > 1 {
+		fmt.Fprintf(b, "  installment: \"%d/%d\"\n", entry.Installment, entry.TotalInstallments)
+	}
+	if entry.Description != nil && comment(*entry.Description) != "" {
+		fmt.Fprintf(b, "  note: %s\n", beancountString(*entry.Description))
+	}
+
+	writePostings(b, entry, n, "  ")
+}
+
+// writePostings moves the amount between the category account and the money
+// account, both amounts are explicit so the transaction reads the same in
+// every tool
+func writePostings(b *strings.Builder, entry transactions.ViewEntry, n namer, indent string) {
+	postings := []struct {
+		account string
+		amount  float64
+	}{
+		{n.categoryAccount(entry), -entry.Amount},
+		{n.moneyAccount(entry), entry.Amount},
+	}
+
+	for _, posting := range postings {
+		// two spaces at least end the account name in ledger and hledger
+		fmt.Fprintf(b, "%s%-50s  %12s %s\n", indent, posting.account, formatAmount(posting.amount), n.settings.Commodity)
+	}
+}
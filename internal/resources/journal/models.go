@@ -0,0 +1,71 @@
+package journal
+
+import (
+	"time"
+)
+
+type Format string
+
+const (
+	Ledger    Format = "ledger"
+	HLedger   Format = "hledger"
+	Beancount Format = "beancount"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+// Fields left out take their default value, the settings are always replaced
+// as a whole
+type UpdateSettingsRequest struct {
+	AssetsRoot      *string           `json:"assets_root" binding:"omitempty,min=1,max=50" example:"Ativos"`
+	LiabilitiesRoot *string           `json:"liabilities_root" binding:"omitempty,min=1,max=50" example:"Passivos"`
+	IncomeRoot      *string           `json:"income_root" binding:"omitempty,min=1,max=50" example:"Receitas"`
+	ExpensesRoot    *string           `json:"expenses_root" binding:"omitempty,min=1,max=50" example:"Despesas"`
+	DefaultAccount  *string           `json:"default_account" binding:"omitempty,min=1,max=100" example:"Conta Corrente"`
+	Uncategorized   *string           `json:"uncategorized" binding:"omitempty,min=1,max=100" example:"Outros"`
+	Commodity       *string           `json:"commodity" binding:"omitempty,min=1,max=24" example:"BRL"`
+	Accounts        map[string]string `json:"accounts" binding:"omitempty,max=100" example:"nubank:Passivos:Cartão Nubank"`
+}
+
+type SettingsResponse struct {
+	Data SettingsResponseData `json:"data"`
+}
+
+type SettingsResponseData struct {
+	Settings Settings `json:"settings"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// A journal account and the first date it's used, beancount needs it opened
+type openAccount struct {
+	Name string
+	Date string
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Journal settings table record. Accounts maps the account of a transaction
+// (like "nubank") to a full journal account (like "Liabilities:Nubank"),
+// accounts that aren't mapped go under the assets root
+type Settings struct {
+	UserID          string            `json:"user_id"`
+	AssetsRoot      string            `json:"assets_root"`
+	LiabilitiesRoot string            `json:"liabilities_root"`
+	IncomeRoot      string            `json:"income_root"`
+	ExpensesRoot    string            `json:"expenses_root"`
+	DefaultAccount  string            `json:"default_account"`
+	Uncategorized   string            `json:"uncategorized"`
+	Commodity       string            `json:"commodity"`
+	Accounts        map[string]string `json:"accounts"`
+	UpdatedAt       *time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,93 @@
+package journal
+
+import (
+	"encoding/json"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type JournalRepo interface {
+	GetSettings(db utils.Executer, userID string) (Settings, error)
+	SaveSettings(db utils.Executer, payload Settings) (Settings, error)
+}
+
+type JournalRepoImpl struct {
+}
+
+func NewJournalRepo(db utils.Executer) JournalRepo {
+	return &JournalRepoImpl{}
+}
+
+type settingsScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSettings(row settingsScanner) (Settings, error) {
+	var settings Settings
+	var accounts []byte
+	if err := row.Scan(
+		&settings.UserID,
+		&settings.AssetsRoot,
+		&settings.LiabilitiesRoot,
+		&settings.IncomeRoot,
+		&settings.ExpensesRoot,
+		&settings.DefaultAccount,
+		&settings.Uncategorized,
+		&settings.Commodity,
+		&accounts,
+		&settings.UpdatedAt,
+	); err != nil {
+		return Settings{}, err
+	}
+
+	if err := json.Unmarshal(accounts, &settings.Accounts); err != nil {
+		return Settings{}, err
+	}
+
+	return settings, nil
+}
+
+func (r *JournalRepoImpl) GetSettings(db utils.Executer, userID string) (Settings, error) {
+	query, args, err := squirrel.Select("user_id", "assets_root", "liabilities_root", "income_root", "expenses_root", "default_account", "uncategorized", "commodity", "accounts", "updated_at").
+		From("journal_settings").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return scanSettings(db.QueryRow(query, args...))
+}
+
+// SaveSettings creates the settings of a user or replaces them
+func (r *JournalRepoImpl) SaveSettings(db utils.Executer, payload Settings) (Settings, error) {
+	accounts, err := json.Marshal(payload.Accounts)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	query, args, err := squirrel.Insert("journal_settings").
+		Columns("user_id", "assets_root", "liabilities_root", "income_root", "expenses_root", "default_account", "uncategorized", "commodity", "accounts").
+		Values(payload.UserID, payload.AssetsRoot, payload.LiabilitiesRoot, payload.IncomeRoot, payload.ExpensesRoot, payload.DefaultAccount, payload.Uncategorized, payload.Commodity, accounts).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET
+			assets_root = excluded.assets_root,
+			liabilities_root = excluded.liabilities_root,
+			income_root = excluded.income_root,
+			expenses_root = excluded.expenses_root,
+			default_account = excluded.default_account,
+			uncategorized = excluded.uncategorized,
+			commodity = excluded.commodity,
+			accounts = excluded.accounts,
+			updated_at = now()
+		RETURNING user_id, assets_root, liabilities_root, income_root, expenses_root, default_account, uncategorized, commodity, accounts, updated_at`).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return scanSettings(db.QueryRow(query, args...))
+}
@@ -0,0 +1,34 @@
+package journal
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	group := router.Group("/api/v1/exports/journal")
+	{
+		group.GET("",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ExportJournal)
+		group.GET("/settings",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.GetSettings)
+		group.PUT("/settings",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.UpdateSettings)
+	}
+}
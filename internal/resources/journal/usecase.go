@@ -0,0 +1,114 @@
+package journal
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type JournalUseCase interface {
+	GetSettings(userID string) (Settings, error)
+	UpdateSettings(userID string, payload UpdateSettingsRequest) (Settings, error)
+	Export(userID string, format Format, from string, to string) ([]byte, error)
+}
+
+type JournalUseCaseImpl struct {
+	repo                JournalRepo
+	transactionsUseCase transactions.TransactionsUseCase
+	db                  *sql.DB
+}
+
+func NewJournalUseCase(repo JournalRepo, transactionsUseCase transactions.TransactionsUseCase, db *sql.DB) JournalUseCase {
+	return &JournalUseCaseImpl{
+		repo:                repo,
+		transactionsUseCase: transactionsUseCase,
+		db:                  db,
+	}
+}
+
+// GetSettings returns the defaults while the user never saved settings
+func (uc *JournalUseCaseImpl) GetSettings(userID string) (Settings, error) {
+	settings, err := uc.repo.GetSettings(uc.db, userID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultSettings(userID), nil
+	}
+
+	if err != nil {
+		return Settings{}, ErrFailedToGetSettings
+	}
+
+	return settings, nil
+}
+
+func (uc *JournalUseCaseImpl) UpdateSettings(userID string, payload UpdateSettingsRequest) (Settings, error) {
+	settings := DefaultSettings(userID)
+
+	if payload.AssetsRoot != nil {
+		settings.AssetsRoot = *payload.AssetsRoot
+	}
+	if payload.LiabilitiesRoot != nil {
+		settings.LiabilitiesRoot = *payload.LiabilitiesRoot
+	}
+	if payload.IncomeRoot != nil {
+		settings.IncomeRoot = *payload.IncomeRoot
+	}
+	if payload.ExpensesRoot != nil {
+		settings.ExpensesRoot = *payload.ExpensesRoot
+	}
+	if payload.DefaultAccount != nil {
+		settings.DefaultAccount = *payload.DefaultAccount
+	}
+	if payload.Uncategorized != nil {
+		settings.Uncategorized = *payload.Uncategorized
+	}
+	if payload.Commodity != nil {
+		settings.Commodity = *payload.Commodity
+	}
+	if payload.Accounts != nil {
+		settings.Accounts = payload.Accounts
+	}
+
+	if err := ValidateSettings(settings); err != nil {
+		return Settings{}, err
+	}
+
+	saved, err := uc.repo.SaveSettings(uc.db, settings)
+	if err != nil {
+		return Settings{}, ErrFailedToSaveSettings
+	}
+
+	return saved, nil
+}
+
+func (uc *JournalUseCaseImpl) Export(userID string, format Format, from string, to string) ([]byte, error) {
+	if format != Ledger && format != HLedger && format != Beancount {
+		return nil, ErrInvalidFormat
+	}
+
+	fromDate, fromErr := time.Parse("2006-01-02", from)
+	toDate, toErr := time.Parse("2006-01-02", to)
+	if fromErr != nil || toErr != nil || fromDate.After(toDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	settings, err := uc.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.transactionsUseCase.ListViewEntries(utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("reference_date", "gte", from).
+		And("reference_date", "lte", to).
+		OrderBy("reference_date", "asc").
+		OrderBy("id", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListEntries
+	}
+
+	return WriteJournal(entries, settings, format), nil
+}
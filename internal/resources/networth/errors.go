@@ -0,0 +1,18 @@
+package networth
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrItemNotFound            = utils.NewHTTPError(http.StatusNotFound, "net worth item not found")
+	ErrInvalidInterval         = utils.NewHTTPError(http.StatusBadRequest, "interval must be month, quarter or year")
+	ErrFailedToCreateItem      = utils.NewHTTPError(http.StatusInternalServerError, "failed to create net worth item")
+	ErrFailedToListItems       = utils.NewHTTPError(http.StatusInternalServerError, "failed to list net worth items")
+	ErrFailedToCountItems      = utils.NewHTTPError(http.StatusInternalServerError, "failed to count net worth items")
+	ErrFailedToDeleteItem      = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete net worth item")
+	ErrFailedToCreateValuation = utils.NewHTTPError(http.StatusInternalServerError, "failed to create valuation")
+	ErrFailedToListValuations  = utils.NewHTTPError(http.StatusInternalServerError, "failed to list valuations")
+)
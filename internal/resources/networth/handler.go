@@ -0,0 +1,244 @@
+package networth
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	netWorthUseCase NetWorthUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		netWorthUseCase: NewNetWorthUseCase(NewNetWorthRepo(db),
+			summary.NewSummaryUseCase(summary.NewSummaryRepo(db), db),
+			db),
+	}
+}
+
+// @Summary Net worth over time
+// @Description Net worth at the end of each calendar month, quarter or year in a range and at its last period, the accounts balance plus assets minus liabilities with the value of each item
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "First period" example(202501)
+// @Param to query string true "Last period" example(202512)
+// @Param interval query string false "month, quarter or year" default(month)
+// @Success 200 {object} NetWorthResponse "Net worth of each interval"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth [get]
+func (api *API) NetWorth(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	points, err := api.netWorthUseCase.NetWorth(userID, ctx.Query("from"), ctx.Query("to"), ctx.Query("interval"))
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NetWorthResponse{
+		Data: NetWorthResponseData{
+			Points: points,
+		},
+	})
+}
+
+// @Summary Create a net worth item
+// @Description Create an asset or a liability valued manually, like an investment or a loan
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateItemRequest true "Item payload"
+// @Success 201 {object} CreateItemResponse "Item created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth/items [post]
+func (api *API) CreateItem(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateItemRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	item, err := api.netWorthUseCase.CreateItem(CreateItemDTO{
+		UserID: userID,
+		Name:   body.Name,
+		Kind:   body.Kind,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateItemResponse{
+		Data: CreateItemResponseData{
+			Item: item,
+		},
+	})
+}
+
+// @Summary List net worth items
+// @Description List assets and liabilities
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(name:asc)
+// @Param filter query string false "Item filter"
+// @Success 200 {object} ListItemsResponse "List of items"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth/items [get]
+func (api *API) ListItems(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	items, err := api.netWorthUseCase.ListItems(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	items, meta, err := utils.Paginate(items, queryOpts, page, perPage, api.netWorthUseCase.CountItems)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListItemsResponse{
+		Data: ListItemsResponseData{
+			Items: items,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary Delete Net Worth Item By ID
+// @Description Delete a net worth item and its valuations
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param item_id path string true "item ID"
+// @Success 204 "Item deleted"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth/items/{item_id} [delete]
+func (api *API) DeleteItemByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("item_id")
+
+	err := api.netWorthUseCase.DeleteItemByID(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Create a valuation
+// @Description Record the value of a net worth item at a date
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param item_id path string true "item ID"
+// @Param body body CreateValuationRequest true "Valuation payload"
+// @Success 201 {object} CreateValuationResponse "Valuation created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth/items/{item_id}/valuations [post]
+func (api *API) CreateValuation(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateValuationRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	valuation, err := api.netWorthUseCase.CreateValuation(CreateValuationDTO{
+		UserID:   userID,
+		ItemID:   ctx.Param("item_id"),
+		Value:    body.Value,
+		ValuedAt: body.ValuedAt,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateValuationResponse{
+		Data: CreateValuationResponseData{
+			Valuation: valuation,
+		},
+	})
+}
+
+// @Summary List valuations
+// @Description List the valuations of a net worth item, latest first
+// @Tags net-worth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param item_id path string true "item ID"
+// @Success 200 {object} ListValuationsResponse "List of valuations"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /net-worth/items/{item_id}/valuations [get]
+func (api *API) ListValuations(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	valuations, err := api.netWorthUseCase.ListValuations(ctx.Param("item_id"), userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListValuationsResponse{
+		Data: ListValuationsResponseData{
+			Valuations: valuations,
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/networth"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNetWorthRepo struct {
+	mock.Mock
+}
+
+func (m *MockNetWorthRepo) CreateItem(db utils.Executer, payload networth.CreateItemDTO) (networth.Item, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(networth.Item), args.Error(1)
+}
+
+func (m *MockNetWorthRepo) ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]networth.Item, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]networth.Item), args.Error(1)
+}
+
+func (m *MockNetWorthRepo) CountItems(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNetWorthRepo) DeleteItemByID(db utils.Executer, id string) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *MockNetWorthRepo) CreateValuation(db utils.Executer, payload networth.CreateValuationDTO) (networth.Valuation, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(networth.Valuation), args.Error(1)
+}
+
+func (m *MockNetWorthRepo) ListValuations(db utils.Executer, filter *utils.QueryOptsBuilder) ([]networth.Valuation, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]networth.Valuation), args.Error(1)
+}
@@ -0,0 +1,131 @@
+package networth
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type ItemKind string
+
+const (
+	Asset     ItemKind = "asset"
+	Liability ItemKind = "liability"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type CreateItemRequest struct {
+	Name string   `json:"name" binding:"required,min=1,max=100"`
+	Kind ItemKind `json:"kind" binding:"required,oneof=asset liability"`
+}
+
+type CreateItemResponse struct {
+	Data CreateItemResponseData `json:"data"`
+}
+
+type CreateItemResponseData struct {
+	Item Item `json:"item"`
+}
+
+type ListItemsResponse struct {
+	Data  ListItemsResponseData `json:"data"`
+	Query utils.QueryMeta       `json:"query"`
+}
+
+type ListItemsResponseData struct {
+	Items []Item `json:"items"`
+}
+
+type CreateValuationRequest struct {
+	Value    float64 `json:"value" binding:"gte=0,lte=9999999999"`
+	ValuedAt string  `json:"valued_at" binding:"required,datetime=2006-01-02"`
+}
+
+type CreateValuationResponse struct {
+	Data CreateValuationResponseData `json:"data"`
+}
+
+type CreateValuationResponseData struct {
+	Valuation Valuation `json:"valuation"`
+}
+
+type ListValuationsResponse struct {
+	Data ListValuationsResponseData `json:"data"`
+}
+
+type ListValuationsResponseData struct {
+	Valuations []Valuation `json:"valuations"`
+}
+
+type NetWorthResponse struct {
+	Data NetWorthResponseData `json:"data"`
+}
+
+type NetWorthResponseData struct {
+	Points []NetWorthPoint `json:"points"`
+}
+
+// Net worth at the end of a period, accounts is the balance of every entry
+// up to it and items are valued by their last valuation up to it
+type NetWorthPoint struct {
+	Period      string      `json:"period"`
+	Accounts    float64     `json:"accounts"`
+	Assets      float64     `json:"assets"`
+	Liabilities float64     `json:"liabilities"`
+	NetWorth    float64     `json:"net_worth"`
+	Items       []ItemValue `json:"items"`
+}
+
+type ItemValue struct {
+	ItemID   string   `json:"item_id"`
+	Name     string   `json:"name"`
+	Kind     ItemKind `json:"kind"`
+	Value    float64  `json:"value"`
+	ValuedAt *string  `json:"valued_at"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type CreateItemDTO struct {
+	UserID string
+	Name   string
+	Kind   ItemKind
+}
+
+type CreateValuationDTO struct {
+	UserID   string
+	ItemID   string
+	Value    float64
+	ValuedAt string
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Net worth items table record, an asset or a liability valued manually
+type Item struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Kind      ItemKind  `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Net worth valuations table record
+type Valuation struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	ItemID    string    `json:"item_id"`
+	Value     float64   `json:"value"`
+	ValuedAt  string    `json:"valued_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,181 @@
+package networth
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/oklog/ulid/v2"
+)
+
+type NetWorthRepo interface {
+	CreateItem(db utils.Executer, payload CreateItemDTO) (Item, error)
+	ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Item, error)
+	CountItems(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	DeleteItemByID(db utils.Executer, id string) error
+	CreateValuation(db utils.Executer, payload CreateValuationDTO) (Valuation, error)
+	ListValuations(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Valuation, error)
+}
+
+type NetWorthRepoImpl struct {
+}
+
+func NewNetWorthRepo(db utils.Executer) NetWorthRepo {
+	return &NetWorthRepoImpl{}
+}
+
+func (r *NetWorthRepoImpl) CreateItem(db utils.Executer, payload CreateItemDTO) (Item, error) {
+	query, args, err := squirrel.Insert("net_worth_items").
+		Columns("id", "user_id", "name", "kind").
+		Values(ulid.Make().String(), payload.UserID, payload.Name, payload.Kind).
+		Suffix("RETURNING id, user_id, name, kind, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return Item{}, err
+	}
+
+	var item Item
+	err = db.QueryRow(query, args...).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Name,
+		&item.Kind,
+		&item.CreatedAt,
+	)
+	return item, err
+}
+
+func (r *NetWorthRepoImpl) ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Item, error) {
+	query := squirrel.Select("id", "user_id", "name", "kind", "created_at").
+		From("net_worth_items").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]Item, 0)
+	for rows.Next() {
+		var item Item
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Name,
+			&item.Kind,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *NetWorthRepoImpl) CountItems(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("net_worth_items").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *NetWorthRepoImpl) DeleteItemByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("net_worth_items").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+func (r *NetWorthRepoImpl) CreateValuation(db utils.Executer, payload CreateValuationDTO) (Valuation, error) {
+	query, args, err := squirrel.Insert("net_worth_valuations").
+		Columns("id", "user_id", "item_id", "value", "valued_at").
+		Values(ulid.Make().String(), payload.UserID, payload.ItemID, payload.Value, payload.ValuedAt).
+		Suffix("RETURNING id, user_id, item_id, value, valued_at::text, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return Valuation{}, err
+	}
+
+	var valuation Valuation
+	err = db.QueryRow(query, args...).Scan(
+		&valuation.ID,
+		&valuation.UserID,
+		&valuation.ItemID,
+		&valuation.Value,
+		&valuation.ValuedAt,
+		&valuation.CreatedAt,
+	)
+	return valuation, err
+}
+
+func (r *NetWorthRepoImpl) ListValuations(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Valuation, error) {
+	query := squirrel.Select("id", "user_id", "item_id", "value", "valued_at::text", "created_at").
+		From("net_worth_valuations").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	valuations := make([]Valuation, 0)
+	for rows.Next() {
+		var valuation Valuation
+		if err := rows.Scan(
+			&valuation.ID,
+			&valuation.UserID,
+			&valuation.ItemID,
+			&valuation.Value,
+			&valuation.ValuedAt,
+			&valuation.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		valuations = append(valuations, valuation)
+	}
+
+	return valuations, nil
+}
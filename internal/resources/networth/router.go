@@ -0,0 +1,44 @@
+package networth
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	group := router.Group("/api/v1/net-worth")
+	{
+		group.GET("",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.NetWorth)
+		group.POST("/items",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.CreateItem)
+		group.GET("/items",
+			middlewares.RequireAuthMiddleware(jwtService),
+			middlewares.QueryOptsMiddleware(),
+			handler.ListItems)
+		group.DELETE("/items/:item_id",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.DeleteItemByID)
+		group.POST("/items/:item_id/valuations",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.CreateValuation)
+		group.GET("/items/:item_id/valuations",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ListValuations)
+	}
+}
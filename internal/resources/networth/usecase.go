@@ -0,0 +1,213 @@
+package networth
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// months of each interval, the months whose number they divide close one
+var intervalSteps = map[string]int{
+	"month":   1,
+	"quarter": 3,
+	"year":    12,
+}
+
+type NetWorthUseCase interface {
+	CreateItem(payload CreateItemDTO) (Item, error)
+	ListItems(filter *utils.QueryOptsBuilder) ([]Item, error)
+	CountItems(filter *utils.QueryOptsBuilder) (int, error)
+	DeleteItemByID(id string, userID string) error
+	CreateValuation(payload CreateValuationDTO) (Valuation, error)
+	ListValuations(itemID string, userID string) ([]Valuation, error)
+	NetWorth(userID string, from string, to string, interval string) ([]NetWorthPoint, error)
+}
+
+type NetWorthUseCaseImpl struct {
+	repo           NetWorthRepo
+	summaryUseCase summary.SummaryUseCase
+	db             *sql.DB
+}
+
+func NewNetWorthUseCase(repo NetWorthRepo, summaryUseCase summary.SummaryUseCase, db *sql.DB) NetWorthUseCase {
+	return &NetWorthUseCaseImpl{
+		repo:           repo,
+		summaryUseCase: summaryUseCase,
+		db:             db,
+	}
+}
+
+func (uc *NetWorthUseCaseImpl) CreateItem(payload CreateItemDTO) (Item, error) {
+	item, err := uc.repo.CreateItem(uc.db, payload)
+	if err != nil {
+		return Item{}, ErrFailedToCreateItem
+	}
+	return item, nil
+}
+
+func (uc *NetWorthUseCaseImpl) ListItems(filter *utils.QueryOptsBuilder) ([]Item, error) {
+	items, err := uc.repo.ListItems(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListItems
+	}
+	return items, nil
+}
+
+func (uc *NetWorthUseCaseImpl) CountItems(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.CountItems(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountItems
+	}
+	return count, nil
+}
+
+func (uc *NetWorthUseCaseImpl) checkItem(id string, userID string) error {
+	exists, err := uc.repo.CountItems(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountItems
+	}
+
+	if exists == 0 {
+		return ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (uc *NetWorthUseCaseImpl) DeleteItemByID(id string, userID string) error {
+	if err := uc.checkItem(id, userID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteItemByID(uc.db, id); err != nil {
+		return ErrFailedToDeleteItem
+	}
+
+	return nil
+}
+
+func (uc *NetWorthUseCaseImpl) CreateValuation(payload CreateValuationDTO) (Valuation, error) {
+	if err := uc.checkItem(payload.ItemID, payload.UserID); err != nil {
+		return Valuation{}, err
+	}
+
+	valuation, err := uc.repo.CreateValuation(uc.db, payload)
+	if err != nil {
+		return Valuation{}, ErrFailedToCreateValuation
+	}
+
+	return valuation, nil
+}
+
+func (uc *NetWorthUseCaseImpl) ListValuations(itemID string, userID string) ([]Valuation, error) {
+	if err := uc.checkItem(itemID, userID); err != nil {
+		return nil, err
+	}
+
+	valuations, err := uc.repo.ListValuations(uc.db, utils.QueryOpts().
+		And("item_id", "eq", itemID).
+		OrderBy("valued_at", "desc").
+		OrderBy("created_at", "desc"))
+	if err != nil {
+		return nil, ErrFailedToListValuations
+	}
+
+	return valuations, nil
+}
+
+// lastDay returns the last date of a YYYYMM period in the format of valued_at
+func lastDay(period string) string {
+	date, _ := time.Parse("200601", period)
+	return date.AddDate(0, 1, -1).Format("2006-01-02")
+}
+
+// NetWorthAt values every item by its last valuation up to the end of the
+// period, valuations must be sorted by valued_at so the latest one wins
+func NetWorthAt(period string, accounts float64, items []Item, valuations []Valuation) NetWorthPoint {
+	until := lastDay(period)
+
+	latest := make(map[string]Valuation)
+	for _, valuation := range valuations {
+		if valuation.ValuedAt > until {
+			continue
+		}
+		latest[valuation.ItemID] = valuation
+	}
+
+	point := NetWorthPoint{
+		Period:   period,
+		Accounts: accounts,
+		Items:    make([]ItemValue, 0, len(items)),
+	}
+
+	for _, item := range items {
+		itemValue := ItemValue{ItemID: item.ID, Name: item.Name, Kind: item.Kind}
+		if valuation, ok := latest[item.ID]; ok {
+			itemValue.Value = valuation.Value
+			itemValue.ValuedAt = &valuation.ValuedAt
+		}
+
+		if item.Kind == Liability {
+			point.Liabilities += itemValue.Value
+		} else {
+			point.Assets += itemValue.Value
+		}
+		point.Items = append(point.Items, itemValue)
+	}
+
+	point.NetWorth = point.Accounts + point.Assets - point.Liabilities
+
+	return point
+}
+
+func (uc *NetWorthUseCaseImpl) NetWorth(userID string, from string, to string, interval string) ([]NetWorthPoint, error) {
+	if interval == "" {
+		interval = "month"
+	}
+
+	step, ok := intervalSteps[interval]
+	if !ok {
+		return nil, ErrInvalidInterval
+	}
+
+	// the cumulative balance of every entry stands for the accounts balance
+	summaries, err := uc.summaryUseCase.ListPeriodSummaries(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.ListItems(uc.db, utils.QueryOpts().
+		And("user_id", "eq", userID).
+		OrderBy("name", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListItems
+	}
+
+	valuations, err := uc.repo.ListValuations(uc.db, utils.QueryOpts().
+		And("user_id", "eq", userID).
+		And("valued_at", "lte", lastDay(to)).
+		OrderBy("valued_at", "asc").
+		OrderBy("created_at", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListValuations
+	}
+
+	// a point closes every calendar interval, so quarters end in March, June,
+	// September and December, and the range always ends on its last period
+	// even when the interval is still partial there
+	points := make([]NetWorthPoint, 0)
+	for i, periodSummary := range summaries {
+		month, _ := strconv.Atoi(periodSummary.Period[4:])
+		if month%step != 0 && i != len(summaries)-1 {
+			continue
+		}
+		points = append(points, NetWorthAt(periodSummary.Period, periodSummary.Balance, items, valuations))
+	}
+
+	return points, nil
+}
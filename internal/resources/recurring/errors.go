@@ -0,0 +1,19 @@
+package recurring
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrRecurringNotFound       = utils.NewHTTPError(http.StatusNotFound, "recurring transaction not found")
+	ErrCategoryNotFound        = utils.NewHTTPError(http.StatusNotFound, "category not found")
+	ErrInvalidPeriod           = utils.NewHTTPError(http.StatusBadRequest, "periods must be in the YYYYMM format")
+	ErrInvalidPeriodRange      = utils.NewHTTPError(http.StatusBadRequest, "start_period must not be after end_period")
+	ErrFailedToCreateRecurring = utils.NewHTTPError(http.StatusInternalServerError, "failed to create recurring transaction")
+	ErrFailedToListRecurring   = utils.NewHTTPError(http.StatusInternalServerError, "failed to list recurring transactions")
+	ErrFailedToCountRecurring  = utils.NewHTTPError(http.StatusInternalServerError, "failed to count recurring transactions")
+	ErrFailedToDeleteRecurring = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete recurring transaction")
+	ErrFailedToCheckCategory   = utils.NewHTTPError(http.StatusInternalServerError, "failed to check if category exists")
+)
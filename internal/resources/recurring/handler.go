@@ -0,0 +1,141 @@
+package recurring
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	recurringUseCase RecurringUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		recurringUseCase: NewRecurringUseCase(NewRecurringRepo(db),
+			categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+			db),
+	}
+}
+
+// @Summary Create a recurring transaction
+// @Description Create a template of an expense or income repeated every month, used to project future periods
+// @Tags recurring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateRecurringRequest true "Recurring transaction payload"
+// @Success 201 {object} CreateRecurringResponse "Recurring transaction created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Category not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /recurring [post]
+func (api *API) Create(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateRecurringRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	recurring, err := api.recurringUseCase.Create(CreateRecurringDTO{
+		UserID:      userID,
+		CategoryID:  body.CategoryID,
+		Name:        body.Name,
+		Type:        body.Type,
+		Amount:      body.Amount,
+		StartPeriod: body.StartPeriod,
+		EndPeriod:   body.EndPeriod,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateRecurringResponse{
+		Data: CreateRecurringResponseData{
+			Recurring: recurring,
+		},
+	})
+}
+
+// @Summary List recurring transactions
+// @Description List recurring transactions
+// @Tags recurring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(name:asc)
+// @Param filter query string false "Recurring transaction filter"
+// @Success 200 {object} ListRecurringResponse "List of recurring transactions"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /recurring [get]
+func (api *API) List(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	result, err := api.recurringUseCase.List(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	result, meta, err := utils.Paginate(result, queryOpts, page, perPage, api.recurringUseCase.Count)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListRecurringResponse{
+		Data: ListRecurringResponseData{
+			Recurring: result,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary Delete Recurring Transaction By ID
+// @Description Delete a recurring transaction
+// @Tags recurring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param recurring_id path string true "recurring transaction ID"
+// @Success 204 "Recurring transaction deleted"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /recurring/{recurring_id} [delete]
+func (api *API) DeleteByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("recurring_id")
+
+	err := api.recurringUseCase.DeleteByID(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecurringRepo struct {
+	mock.Mock
+}
+
+func (m *MockRecurringRepo) Create(db utils.Executer, payload recurring.CreateRecurringDTO) (recurring.RecurringTransaction, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(recurring.RecurringTransaction), args.Error(1)
+}
+
+func (m *MockRecurringRepo) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]recurring.RecurringTransaction, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]recurring.RecurringTransaction), args.Error(1)
+}
+
+func (m *MockRecurringRepo) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRecurringRepo) DeleteByID(db utils.Executer, id string) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
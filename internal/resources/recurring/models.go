@@ -0,0 +1,77 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type CreateRecurringRequest struct {
+	Name        string                    `json:"name" binding:"required,min=1,max=100"`
+	CategoryID  *string                   `json:"category_id" binding:"omitempty"`
+	Type        constants.TransactionType `json:"type" binding:"required,oneof=simple_expense income"`
+	Amount      float64                   `json:"amount" binding:"required,gte=-999999,lte=999999"`
+	StartPeriod string                    `json:"start_period" binding:"required,len=6,numeric"`
+	EndPeriod   *string                   `json:"end_period" binding:"omitempty,len=6,numeric"`
+}
+
+type CreateRecurringResponse struct {
+	Data CreateRecurringResponseData `json:"data"`
+}
+
+type CreateRecurringResponseData struct {
+	Recurring RecurringTransaction `json:"recurring"`
+}
+
+type ListRecurringResponse struct {
+	Data  ListRecurringResponseData `json:"data"`
+	Query utils.QueryMeta           `json:"query"`
+}
+
+type ListRecurringResponseData struct {
+	Recurring []RecurringTransaction `json:"recurring"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type CreateRecurringDTO struct {
+	UserID      string
+	CategoryID  *string
+	Name        string
+	Type        constants.TransactionType
+	Amount      float64
+	StartPeriod string
+	EndPeriod   *string
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Recurring transactions table record, a template repeated every month from
+// start period until end period, or forever when it has none
+type RecurringTransaction struct {
+	ID          string                    `json:"id"`
+	UserID      string                    `json:"user_id"`
+	CategoryID  *string                   `json:"category_id"`
+	Name        string                    `json:"name"`
+	Type        constants.TransactionType `json:"type"`
+	Amount      float64                   `json:"amount"`
+	StartPeriod string                    `json:"start_period"`
+	EndPeriod   *string                   `json:"end_period"`
+	CreatedAt   time.Time                 `json:"created_at"`
+}
+
+func (r RecurringTransaction) ActiveIn(period string) bool {
+	return period >= r.StartPeriod && (r.EndPeriod == nil || period <= *r.EndPeriod)
+}
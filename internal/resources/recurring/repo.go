@@ -0,0 +1,126 @@
+package recurring
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/oklog/ulid/v2"
+)
+
+type RecurringRepo interface {
+	Create(db utils.Executer, payload CreateRecurringDTO) (RecurringTransaction, error)
+	List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]RecurringTransaction, error)
+	Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	DeleteByID(db utils.Executer, id string) error
+}
+
+type RecurringRepoImpl struct {
+}
+
+func NewRecurringRepo(db utils.Executer) RecurringRepo {
+	return &RecurringRepoImpl{}
+}
+
+func (r *RecurringRepoImpl) Create(db utils.Executer, payload CreateRecurringDTO) (RecurringTransaction, error) {
+	query, args, err := squirrel.Insert("recurring_transactions").
+		Columns("id", "user_id", "category_id", "name", "type", "amount", "start_period", "end_period").
+		Values(ulid.Make().String(), payload.UserID, payload.CategoryID, payload.Name, payload.Type, payload.Amount, payload.StartPeriod, payload.EndPeriod).
+		Suffix("RETURNING id, user_id, category_id, name, type, amount, start_period, end_period, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return RecurringTransaction{}, err
+	}
+
+	var recurring RecurringTransaction
+	err = db.QueryRow(query, args...).Scan(
+		&recurring.ID,
+		&recurring.UserID,
+		&recurring.CategoryID,
+		&recurring.Name,
+		&recurring.Type,
+		&recurring.Amount,
+		&recurring.StartPeriod,
+		&recurring.EndPeriod,
+		&recurring.CreatedAt,
+	)
+	return recurring, err
+}
+
+func (r *RecurringRepoImpl) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]RecurringTransaction, error) {
+	query := squirrel.Select("id", "user_id", "category_id", "name", "type", "amount", "start_period", "end_period", "created_at").
+		From("recurring_transactions").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]RecurringTransaction, 0)
+	for rows.Next() {
+		var recurring RecurringTransaction
+		if err := rows.Scan(
+			&recurring.ID,
+			&recurring.UserID,
+			&recurring.CategoryID,
+			&recurring.Name,
+			&recurring.Type,
+			&recurring.Amount,
+			&recurring.StartPeriod,
+			&recurring.EndPeriod,
+			&recurring.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, recurring)
+	}
+
+	return result, nil
+}
+
+func (r *RecurringRepoImpl) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("recurring_transactions").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *RecurringRepoImpl) DeleteByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("recurring_transactions").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
@@ -0,0 +1,107 @@
+package recurring
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type RecurringUseCase interface {
+	Create(payload CreateRecurringDTO) (RecurringTransaction, error)
+	List(filter *utils.QueryOptsBuilder) ([]RecurringTransaction, error)
+	Count(filter *utils.QueryOptsBuilder) (int, error)
+	DeleteByID(id string, userID string) error
+}
+
+type RecurringUseCaseImpl struct {
+	repo              RecurringRepo
+	categoriesUseCase categories.CategoriesUseCase
+	db                *sql.DB
+}
+
+func NewRecurringUseCase(repo RecurringRepo, categoriesUseCase categories.CategoriesUseCase, db *sql.DB) RecurringUseCase {
+	return &RecurringUseCaseImpl{
+		repo:              repo,
+		categoriesUseCase: categoriesUseCase,
+		db:                db,
+	}
+}
+
+func (uc *RecurringUseCaseImpl) Create(payload CreateRecurringDTO) (RecurringTransaction, error) {
+	if _, err := time.Parse("200601", payload.StartPeriod); err != nil {
+		return RecurringTransaction{}, ErrInvalidPeriod
+	}
+
+	if payload.EndPeriod != nil {
+		if _, err := time.Parse("200601", *payload.EndPeriod); err != nil {
+			return RecurringTransaction{}, ErrInvalidPeriod
+		}
+		if *payload.EndPeriod < payload.StartPeriod {
+			return RecurringTransaction{}, ErrInvalidPeriodRange
+		}
+	}
+
+	if payload.CategoryID != nil {
+		categoryExists, err := uc.categoriesUseCase.Count(utils.QueryOpts().
+			And("id", "eq", *payload.CategoryID).
+			And("user_id", "eq", payload.UserID))
+		if err != nil {
+			return RecurringTransaction{}, ErrFailedToCheckCategory
+		}
+
+		if categoryExists == 0 {
+			return RecurringTransaction{}, ErrCategoryNotFound
+		}
+	}
+
+	// same sign convention of entries, expenses are negative and incomes positive
+	if (payload.Type == constants.SimpleExpense && payload.Amount > 0) ||
+		(payload.Type == constants.Income && payload.Amount < 0) {
+		payload.Amount = payload.Amount * -1
+	}
+
+	recurring, err := uc.repo.Create(uc.db, payload)
+	if err != nil {
+		return RecurringTransaction{}, ErrFailedToCreateRecurring
+	}
+
+	return recurring, nil
+}
+
+func (uc *RecurringUseCaseImpl) List(filter *utils.QueryOptsBuilder) ([]RecurringTransaction, error) {
+	result, err := uc.repo.List(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListRecurring
+	}
+	return result, nil
+}
+
+func (uc *RecurringUseCaseImpl) Count(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.Count(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountRecurring
+	}
+	return count, nil
+}
+
+func (uc *RecurringUseCaseImpl) DeleteByID(id string, userID string) error {
+	exists, err := uc.repo.Count(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountRecurring
+	}
+
+	if exists == 0 {
+		return ErrRecurringNotFound
+	}
+
+	if err := uc.repo.DeleteByID(uc.db, id); err != nil {
+		return ErrFailedToDeleteRecurring
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package reports
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrFailedToAggregate       = utils.NewHTTPError(http.StatusInternalServerError, "failed to aggregate entries")
+	ErrMissingGroupBy          = utils.NewHTTPError(http.StatusBadRequest, "group_by must have at least one dimension")
+	ErrInvalidPeriod           = utils.NewHTTPError(http.StatusBadRequest, "from must be a period in the YYYYMM format")
+	ErrInvalidMonths           = utils.NewHTTPError(http.StatusBadRequest, "months must be a number between 1 and 120")
+	ErrFailedToListCommitments = utils.NewHTTPError(http.StatusInternalServerError, "failed to list committed installments")
+	ErrPivotNotGrouped         = utils.NewHTTPError(http.StatusBadRequest, "pivot must be one of the group_by dimensions")
+)
@@ -0,0 +1,126 @@
+package reports
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	reportsUseCase ReportsUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		reportsUseCase: NewReportsUseCase(NewReportsRepo(db), db),
+	}
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// @Summary Aggregate entries
+// @Description Group entries by the given dimensions and compute measures over their amount, optionally pivoting one dimension into columns
+// @Tags reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param group_by query string true "Dimensions: period, category, type, weekday, payee, account, tag" example(period,category)
+// @Param measures query string false "Measures: sum, count, avg, min, max" default(sum)
+// @Param pivot query string false "Dimension to turn into columns" example(category)
+// @Param filter query string false "Entries filter" example(period ge '202501')
+// @Success 200 {object} AggregateResponse "Aggregated report"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /reports/aggregate [get]
+func (api *API) Aggregate(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := utils.ForCount(ctx.MustGet("query_opts").(*utils.QueryOptsBuilder)).And("user_id", "eq", userID)
+
+	payload := AggregateDTO{
+		GroupBy:  splitList(ctx.Query("group_by")),
+		Measures: splitList(ctx.Query("measures")),
+	}
+
+	if pivot := strings.TrimSpace(ctx.Query("pivot")); pivot != "" {
+		payload.Pivot = &pivot
+	}
+
+	report, err := api.reportsUseCase.Aggregate(payload, queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AggregateResponse{
+		Data: AggregateResponseData{
+			Report: report,
+		},
+	})
+}
+
+// @Summary List future commitments
+// @Description List, for each upcoming period, the installments already committed and the remaining installments and amount of each purchase
+// @Tags reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param from query string false "First period, the current one by default" example(202501)
+// @Param months query int false "Periods to list" default(12)
+// @Success 200 {object} ListCommitmentsResponse "Committed installments per period"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /reports/commitments [get]
+func (api *API) ListCommitments(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	from := ctx.DefaultQuery("from", time.Now().Format("200601"))
+
+	months := 12
+	if value, ok := ctx.GetQuery("months"); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			apiErr := ErrInvalidMonths
+			ctx.JSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		months = parsed
+	}
+
+	periods, err := api.reportsUseCase.ListCommitments(userID, from, months)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	totalCommitted := 0.0
+	for _, period := range periods {
+		totalCommitted += period.Total
+	}
+
+	ctx.JSON(http.StatusOK, ListCommitmentsResponse{
+		Data: ListCommitmentsResponseData{
+			TotalCommitted: totalCommitted,
+			Periods:        periods,
+		},
+	})
+}
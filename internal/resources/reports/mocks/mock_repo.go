@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/reports"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportsRepo struct {
+	mock.Mock
+}
+
+func (m *MockReportsRepo) Aggregate(db utils.Executer, groupBy []string, measureNames []string, filter *utils.QueryOptsBuilder) (reports.AggregateReport, error) {
+	args := m.Called(db, groupBy, measureNames, filter)
+	return args.Get(0).(reports.AggregateReport), args.Error(1)
+}
+
+func (m *MockReportsRepo) ListFutureInstallments(db utils.Executer, userID string, from string, to string) ([]reports.CommittedInstallment, error) {
+	args := m.Called(db, userID, from, to)
+	return args.Get(0).([]reports.CommittedInstallment), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package reports
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type AggregateResponse struct {
+	Data AggregateResponseData `json:"data"`
+}
+
+type AggregateResponseData struct {
+	Report AggregateReport `json:"report"`
+}
+
+type ListCommitmentsResponse struct {
+	Data ListCommitmentsResponseData `json:"data"`
+}
+
+type ListCommitmentsResponseData struct {
+	TotalCommitted float64            `json:"total_committed"`
+	Periods        []CommitmentPeriod `json:"periods"`
+}
+
+// Sum of the installments due in a period and the purchases behind them
+type CommitmentPeriod struct {
+	Period       string                 `json:"period"`
+	Total        float64                `json:"total"`
+	Installments []CommittedInstallment `json:"installments"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type AggregateDTO struct {
+	GroupBy  []string
+	Measures []string
+	Pivot    *string
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Tabular result of an aggregation, every row has one value per column
+type AggregateReport struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// Installment entry of a purchase, remaining values count this installment
+// and every one after it
+type CommittedInstallment struct {
+	TransactionID         string  `json:"transaction_id"`
+	Name                  string  `json:"name"`
+	CategoryID            *string `json:"category_id"`
+	CategoryName          *string `json:"category_name"`
+	Period                string  `json:"-"`
+	Amount                float64 `json:"amount"`
+	Installment           int     `json:"installment"`
+	TotalInstallments     int     `json:"total_installments"`
+	RemainingInstallments int     `json:"remaining_installments"`
+	RemainingAmount       float64 `json:"remaining_amount"`
+}
@@ -0,0 +1,174 @@
+package reports
+
+import (
+	"slices"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Group by dimensions and the SQL over v_entries that computes them, every
+// dimension is exposed as text so rows can be scanned without knowing its type
+var dimensions = map[string]string{
+	"period":   "period",
+	"category": "category_name",
+	"type":     "category::text",
+	"weekday":  "EXTRACT(ISODOW FROM reference_date)::int::text",
+	"payee":    "payee",
+	"account":  "account",
+	"tag":      "tag",
+}
+
+// Grouping by tag reads one row per tag of each entry, so an entry with many
+// tags is counted in each of them and entries without tags fall in null
+const tagsFrom = "v_entries LEFT JOIN LATERAL unnest(tags) AS tag ON true"
+
+var measures = map[string]string{
+	"sum":   "COALESCE(SUM(amount), 0)",
+	"count": "COUNT(*)",
+	"avg":   "COALESCE(AVG(amount), 0)",
+	"min":   "COALESCE(MIN(amount), 0)",
+	"max":   "COALESCE(MAX(amount), 0)",
+}
+
+// Columns of v_entries that can be referenced by the filter of a report
+var filterableFields = []string{
+	"id", "transaction_id", "name", "description", "amount", "period", "user_id",
+	"category", "total_amount", "installment", "total_installments", "created_at",
+	"reference_date", "category_id", "category_name", "category_color", "payee", "account",
+}
+
+// Remaining values come from the installment window of v_entries, summing
+// from the last installment back to each one before narrowing to the periods
+const listFutureInstallmentsQuery = `
+SELECT
+    transaction_id,
+    name,
+    category_id,
+    category_name,
+    period,
+    amount,
+    installment,
+    total_installments,
+    remaining_installments,
+    remaining_amount
+FROM (
+    SELECT
+        *,
+        total_installments - installment + 1 AS remaining_installments,
+        SUM(amount) OVER (PARTITION BY transaction_id ORDER BY installment DESC) AS remaining_amount
+    FROM v_entries
+    WHERE user_id = $1 AND category = 'installment'
+) i
+WHERE period BETWEEN $2 AND $3
+ORDER BY period, name, transaction_id`
+
+type ReportsRepo interface {
+	Aggregate(db utils.Executer, groupBy []string, measureNames []string, filter *utils.QueryOptsBuilder) (AggregateReport, error)
+	ListFutureInstallments(db utils.Executer, userID string, from string, to string) ([]CommittedInstallment, error)
+}
+
+type ReportsRepoImpl struct {
+}
+
+func NewReportsRepo(db utils.Executer) ReportsRepo {
+	return &ReportsRepoImpl{}
+}
+
+// Aggregate expects groupBy and measureNames to be already validated against
+// dimensions and measures, they are the only input that reaches the SQL text
+func (r *ReportsRepoImpl) Aggregate(db utils.Executer, groupBy []string, measureNames []string, filter *utils.QueryOptsBuilder) (AggregateReport, error) {
+	columns := make([]string, 0, len(groupBy)+len(measureNames))
+	from := "v_entries"
+	if slices.Contains(groupBy, "tag") {
+		from = tagsFrom
+	}
+
+	query := squirrel.Select().
+		From(from).
+		PlaceholderFormat(squirrel.Dollar)
+
+	for _, dimension := range groupBy {
+		query = query.Column(dimensions[dimension] + " AS " + dimension).
+			GroupBy(dimensions[dimension]).
+			OrderBy(dimension + " ASC")
+		columns = append(columns, dimension)
+	}
+
+	for _, measure := range measureNames {
+		query = query.Column(measures[measure] + " AS " + measure)
+		columns = append(columns, measure)
+	}
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return AggregateReport{}, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return AggregateReport{}, err
+	}
+	defer rows.Close()
+
+	report := AggregateReport{Columns: columns, Rows: make([][]any, 0)}
+	for rows.Next() {
+		dimensionValues := make([]*string, len(groupBy))
+		measureValues := make([]float64, len(measureNames))
+		dest := make([]any, 0, len(columns))
+		for i := range dimensionValues {
+			dest = append(dest, &dimensionValues[i])
+		}
+		for i := range measureValues {
+			dest = append(dest, &measureValues[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return AggregateReport{}, err
+		}
+
+		row := make([]any, 0, len(columns))
+		for _, value := range dimensionValues {
+			row = append(row, value)
+		}
+		for _, value := range measureValues {
+			row = append(row, value)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	return report, rows.Err()
+}
+
+func (r *ReportsRepoImpl) ListFutureInstallments(db utils.Executer, userID string, from string, to string) ([]CommittedInstallment, error) {
+	rows, err := db.Query(listFutureInstallmentsQuery, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	installments := make([]CommittedInstallment, 0)
+	for rows.Next() {
+		var installment CommittedInstallment
+		if err := rows.Scan(
+			&installment.TransactionID,
+			&installment.Name,
+			&installment.CategoryID,
+			&installment.CategoryName,
+			&installment.Period,
+			&installment.Amount,
+			&installment.Installment,
+			&installment.TotalInstallments,
+			&installment.RemainingInstallments,
+			&installment.RemainingAmount,
+		); err != nil {
+			return nil, err
+		}
+		installments = append(installments, installment)
+	}
+
+	return installments, rows.Err()
+}
@@ -0,0 +1,182 @@
+package reports
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type ReportsUseCase interface {
+	Aggregate(payload AggregateDTO, filter *utils.QueryOptsBuilder) (AggregateReport, error)
+	ListCommitments(userID string, from string, months int) ([]CommitmentPeriod, error)
+}
+
+type ReportsUseCaseImpl struct {
+	repo ReportsRepo
+	db   *sql.DB
+}
+
+func NewReportsUseCase(repo ReportsRepo, db *sql.DB) ReportsUseCase {
+	return &ReportsUseCaseImpl{
+		repo: repo,
+		db:   db,
+	}
+}
+
+func validateAggregate(payload AggregateDTO, filter *utils.QueryOptsBuilder) error {
+	if len(payload.GroupBy) == 0 {
+		return ErrMissingGroupBy
+	}
+
+	for i, dimension := range payload.GroupBy {
+		if _, ok := dimensions[dimension]; !ok {
+			return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown dimension '%s'", dimension))
+		}
+		if slices.Contains(payload.GroupBy[:i], dimension) {
+			return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("dimension '%s' is repeated", dimension))
+		}
+	}
+
+	for i, measure := range payload.Measures {
+		if _, ok := measures[measure]; !ok {
+			return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown measure '%s'", measure))
+		}
+		if slices.Contains(payload.Measures[:i], measure) {
+			return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("measure '%s' is repeated", measure))
+		}
+	}
+
+	for _, field := range filter.ConditionFields() {
+		if !slices.Contains(filterableFields, field) {
+			return utils.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("field '%s' cannot be used in filter", field))
+		}
+	}
+
+	if payload.Pivot != nil && !slices.Contains(payload.GroupBy, *payload.Pivot) {
+		return ErrPivotNotGrouped
+	}
+
+	return nil
+}
+
+func (uc *ReportsUseCaseImpl) Aggregate(payload AggregateDTO, filter *utils.QueryOptsBuilder) (AggregateReport, error) {
+	if len(payload.Measures) == 0 {
+		payload.Measures = []string{"sum"}
+	}
+
+	if err := validateAggregate(payload, filter); err != nil {
+		return AggregateReport{}, err
+	}
+
+	report, err := uc.repo.Aggregate(uc.db, payload.GroupBy, payload.Measures, filter)
+	if err != nil {
+		return AggregateReport{}, ErrFailedToAggregate
+	}
+
+	if payload.Pivot != nil {
+		report = pivotReport(report, payload.GroupBy, payload.Measures, *payload.Pivot)
+	}
+
+	return report, nil
+}
+
+// pivotReport turns the values of the pivot dimension into columns named
+// value:measure, keeping one row per combination of the remaining dimensions
+func pivotReport(report AggregateReport, groupBy []string, measureNames []string, pivot string) AggregateReport {
+	pivotIndex := slices.Index(groupBy, pivot)
+
+	label := func(value any) string {
+		if str, ok := value.(*string); ok && str != nil {
+			return *str
+		}
+		return "null"
+	}
+
+	pivotValues := make([]string, 0)
+	for _, row := range report.Rows {
+		value := label(row[pivotIndex])
+		if !slices.Contains(pivotValues, value) {
+			pivotValues = append(pivotValues, value)
+		}
+	}
+	slices.Sort(pivotValues)
+
+	columns := make([]string, 0)
+	for i, dimension := range groupBy {
+		if i != pivotIndex {
+			columns = append(columns, dimension)
+		}
+	}
+	for _, value := range pivotValues {
+		for _, measure := range measureNames {
+			columns = append(columns, value+":"+measure)
+		}
+	}
+
+	pivoted := AggregateReport{Columns: columns, Rows: make([][]any, 0)}
+	rowIndexes := make(map[string]int)
+	for _, row := range report.Rows {
+		key := make([]any, 0, len(groupBy)-1)
+		keyLabel := ""
+		for i := range groupBy {
+			if i != pivotIndex {
+				key = append(key, row[i])
+				keyLabel += label(row[i]) + "\x00"
+			}
+		}
+
+		index, ok := rowIndexes[keyLabel]
+		if !ok {
+			index = len(pivoted.Rows)
+			rowIndexes[keyLabel] = index
+			pivoted.Rows = append(pivoted.Rows, append(key, make([]any, len(pivotValues)*len(measureNames))...))
+		}
+
+		offset := len(groupBy) - 1 + slices.Index(pivotValues, label(row[pivotIndex]))*len(measureNames)
+		for i := range measureNames {
+			pivoted.Rows[index][offset+i] = row[len(groupBy)+i]
+		}
+	}
+
+	return pivoted
+}
+
+const maxCommitmentMonths = 120
+
+func (uc *ReportsUseCaseImpl) ListCommitments(userID string, from string, months int) ([]CommitmentPeriod, error) {
+	fromDate, err := time.Parse("200601", from)
+	if err != nil {
+		return nil, ErrInvalidPeriod
+	}
+
+	if months < 1 || months > maxCommitmentMonths {
+		return nil, ErrInvalidMonths
+	}
+
+	to := fromDate.AddDate(0, months-1, 0).Format("200601")
+
+	installments, err := uc.repo.ListFutureInstallments(uc.db, userID, from, to)
+	if err != nil {
+		return nil, ErrFailedToListCommitments
+	}
+
+	periods := make([]CommitmentPeriod, 0)
+	for _, installment := range installments {
+		if len(periods) == 0 || periods[len(periods)-1].Period != installment.Period {
+			periods = append(periods, CommitmentPeriod{
+				Period:       installment.Period,
+				Installments: make([]CommittedInstallment, 0),
+			})
+		}
+
+		current := &periods[len(periods)-1]
+		current.Total += installment.Amount
+		current.Installments = append(current.Installments, installment)
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,23 @@
+package rules
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrRuleNotFound          = utils.NewHTTPError(http.StatusNotFound, "rule not found")
+	ErrCategoryNotFound      = utils.NewHTTPError(http.StatusNotFound, "category not found")
+	ErrEmptyConditions       = utils.NewHTTPError(http.StatusBadRequest, "rule must have at least one condition")
+	ErrEmptyActions          = utils.NewHTTPError(http.StatusBadRequest, "rule must have at least one action")
+	ErrInvalidAmountRange    = utils.NewHTTPError(http.StatusBadRequest, "amount_min must not be greater than amount_max")
+	ErrFailedToCheckCategory = utils.NewHTTPError(http.StatusInternalServerError, "failed to check if category exists")
+	ErrFailedToCreateRule    = utils.NewHTTPError(http.StatusInternalServerError, "failed to create rule")
+	ErrFailedToListRules     = utils.NewHTTPError(http.StatusInternalServerError, "failed to list rules")
+	ErrFailedToCountRules    = utils.NewHTTPError(http.StatusInternalServerError, "failed to count rules")
+	ErrFailedToUpdateRule    = utils.NewHTTPError(http.StatusInternalServerError, "failed to update rule")
+	ErrFailedToDeleteRule    = utils.NewHTTPError(http.StatusInternalServerError, "failed to delete rule")
+	ErrFailedToListTargets   = utils.NewHTTPError(http.StatusInternalServerError, "failed to list transactions to run rules on")
+	ErrFailedToApplyRules    = utils.NewHTTPError(http.StatusInternalServerError, "failed to apply rules to transactions")
+)
@@ -0,0 +1,235 @@
+package rules
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	rulesUseCase RulesUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		rulesUseCase: NewRulesUseCase(NewRulesRepo(db),
+			categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+			db),
+	}
+}
+
+// @Summary Create a rule
+// @Description Create a rule that changes matching transactions when they are created or imported, rules run in ascending priority
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateRuleRequest true "Rule payload"
+// @Success 201 {object} CreateRuleResponse "Rule created"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Category not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /rules [post]
+func (api *API) Create(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateRuleRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	rule, err := api.rulesUseCase.Create(CreateRuleDTO{
+		UserID:     userID,
+		Name:       body.Name,
+		Priority:   body.Priority,
+		Conditions: body.Conditions,
+		Actions:    body.Actions,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreateRuleResponse{
+		Data: CreateRuleResponseData{
+			Rule: rule,
+		},
+	})
+}
+
+// @Summary List rules
+// @Description List rules
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Param order_by query string false "Sort field" example(priority:asc)
+// @Param filter query string false "Rule filter"
+// @Success 200 {object} ListRulesResponse "List of rules"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /rules [get]
+func (api *API) List(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+
+	rules, err := api.rulesUseCase.List(queryOpts)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	page := ctx.GetInt("page")
+	perPage := ctx.GetInt("per_page")
+	rules, meta, err := utils.Paginate(rules, queryOpts, page, perPage, api.rulesUseCase.Count)
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListRulesResponse{
+		Data: ListRulesResponseData{
+			Rules: rules,
+		},
+		Query: meta,
+	})
+}
+
+// @Summary Update Rule By ID
+// @Description Update the name, priority, conditions or actions of a rule
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param rule_id path string true "rule ID"
+// @Param body body UpdateRuleRequest true "Rule payload"
+// @Success 200 {object} UpdateRuleResponse "Rule updated"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /rules/{rule_id} [patch]
+func (api *API) Update(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("rule_id")
+	var body UpdateRuleRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	if !utils.HasAtLeastOneField(body) {
+		apiErr := utils.NewHTTPError(
+			http.StatusBadRequest,
+			"At least one field must be provided for update",
+		)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	rule, err := api.rulesUseCase.Update(id, userID, UpdateRuleDTO{
+		Name:       body.Name,
+		Priority:   body.Priority,
+		Conditions: body.Conditions,
+		Actions:    body.Actions,
+	})
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, UpdateRuleResponse{
+		Data: UpdateRuleResponseData{
+			Rule: rule,
+		},
+	})
+}
+
+// @Summary Delete Rule By ID
+// @Description Delete a rule
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param rule_id path string true "rule ID"
+// @Success 204 "Rule deleted"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /rules/{rule_id} [delete]
+func (api *API) DeleteByID(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("rule_id")
+
+	err := api.rulesUseCase.DeleteByID(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Run rules on existing transactions
+// @Description Apply the rules to every existing transaction, with dry_run only the changes that would be made are returned. Category and payee are only filled when empty unless overwrite is set
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body RunRulesRequest true "Run options"
+// @Success 200 {object} RunRulesResponse "Changed transactions"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /rules/run [post]
+func (api *API) Run(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body RunRulesRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	changes, err := api.rulesUseCase.Run(userID, body.DryRun, body.Overwrite)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RunRulesResponse{
+		Data: RunRulesResponseData{
+			DryRun:  body.DryRun,
+			Changes: changes,
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type CreateRuleRequest struct {
+	Name       string         `json:"name" binding:"required,min=1,max=100"`
+	Priority   int            `json:"priority" binding:"gte=0,lte=10000"`
+	Conditions RuleConditions `json:"conditions"`
+	Actions    RuleActions    `json:"actions"`
+}
+
+type CreateRuleResponse struct {
+	Data CreateRuleResponseData `json:"data"`
+}
+
+type CreateRuleResponseData struct {
+	Rule Rule `json:"rule"`
+}
+
+type ListRulesResponse struct {
+	Data  ListRulesResponseData `json:"data"`
+	Query utils.QueryMeta       `json:"query"`
+}
+
+type ListRulesResponseData struct {
+	Rules []Rule `json:"rules"`
+}
+
+type UpdateRuleRequest struct {
+	Name       *string         `json:"name" binding:"omitempty,min=1,max=100"`
+	Priority   *int            `json:"priority" binding:"omitempty,gte=0,lte=10000"`
+	Conditions *RuleConditions `json:"conditions" binding:"omitempty"`
+	Actions    *RuleActions    `json:"actions" binding:"omitempty"`
+}
+
+type UpdateRuleResponse struct {
+	Data UpdateRuleResponseData `json:"data"`
+}
+
+type UpdateRuleResponseData struct {
+	Rule Rule `json:"rule"`
+}
+
+type RunRulesRequest struct {
+	DryRun    bool `json:"dry_run"`
+	Overwrite bool `json:"overwrite"`
+}
+
+type RunRulesResponse struct {
+	Data RunRulesResponseData `json:"data"`
+}
+
+type RunRulesResponseData struct {
+	DryRun  bool         `json:"dry_run"`
+	Changes []RuleChange `json:"changes"`
+}
+
+// A transaction changed by running the rules, with the rules that matched it
+type RuleChange struct {
+	TransactionID string   `json:"transaction_id"`
+	RuleIDs       []string `json:"rule_ids"`
+	Before        Subject  `json:"before"`
+	After         Subject  `json:"after"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+type CreateRuleDTO struct {
+	UserID     string
+	Name       string
+	Priority   int
+	Conditions RuleConditions
+	Actions    RuleActions
+}
+
+type UpdateRuleDTO struct {
+	Name       *string
+	Priority   *int
+	Conditions *RuleConditions
+	Actions    *RuleActions
+}
+
+// Subject is what rules read and change of a transaction, amount is the
+// absolute total of its entries
+type Subject struct {
+	Name       string                    `json:"name"`
+	Note       *string                   `json:"note"`
+	Type       constants.TransactionType `json:"type"`
+	Amount     float64                   `json:"amount"`
+	Account    *string                   `json:"account"`
+	CategoryID *string                   `json:"category_id"`
+	Payee      *string                   `json:"payee"`
+	Tags       []string                  `json:"tags"`
+}
+
+// A transaction of the user as rules see it
+type RuleTarget struct {
+	TransactionID string
+	Subject       Subject
+}
+
+// Every condition set must match, texts are compared ignoring case
+type RuleConditions struct {
+	NameContains *string                    `json:"name_contains,omitempty" binding:"omitempty,min=1,max=100"`
+	AmountMin    *float64                   `json:"amount_min,omitempty" binding:"omitempty,gte=0"`
+	AmountMax    *float64                   `json:"amount_max,omitempty" binding:"omitempty,gte=0"`
+	Type         *constants.TransactionType `json:"type,omitempty" binding:"omitempty,oneof=installment simple_expense income"`
+	Account      *string                    `json:"account,omitempty" binding:"omitempty,min=1,max=100"`
+	NoteContains *string                    `json:"note_contains,omitempty" binding:"omitempty,min=1,max=400"`
+}
+
+type RuleActions struct {
+	SetCategoryID *string  `json:"set_category_id,omitempty"`
+	AddTags       []string `json:"add_tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Rename        *string  `json:"rename,omitempty" binding:"omitempty,min=1,max=100"`
+	SetPayee      *string  `json:"set_payee,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Rules table record, conditions and actions are stored as jsonb
+type Rule struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	Name       string         `json:"name"`
+	Priority   int            `json:"priority"`
+	Conditions RuleConditions `json:"conditions"`
+	Actions    RuleActions    `json:"actions"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
@@ -0,0 +1,258 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+)
+
+const listRuleTargetsQuery = `
+SELECT
+    t.id,
+    t.name,
+    t.description,
+    t.category,
+    ABS(SUM(e.amount)),
+    t.account,
+    t.category_id,
+    t.payee,
+    t.tags
+FROM transactions t
+JOIN entries e ON e.transaction_id = t.id
+WHERE t.user_id = $1
+GROUP BY t.id
+ORDER BY t.created_at, t.id`
+
+type RulesRepo interface {
+	Create(db utils.Executer, payload CreateRuleDTO) (Rule, error)
+	List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Rule, error)
+	Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	Update(db utils.Executer, id string, payload UpdateRuleDTO) (Rule, error)
+	DeleteByID(db utils.Executer, id string) error
+	ListTargets(db utils.Executer, userID string) ([]RuleTarget, error)
+	UpdateTarget(db utils.Executer, transactionID string, subject Subject) error
+}
+
+type RulesRepoImpl struct {
+}
+
+func NewRulesRepo(db utils.Executer) RulesRepo {
+	return &RulesRepoImpl{}
+}
+
+type ruleScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRule(row ruleScanner) (Rule, error) {
+	var rule Rule
+	var conditions, actions []byte
+	if err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&rule.Name,
+		&rule.Priority,
+		&conditions,
+		&actions,
+		&rule.CreatedAt,
+	); err != nil {
+		return Rule{}, err
+	}
+
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return Rule{}, err
+	}
+	if err := json.Unmarshal(actions, &rule.Actions); err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r *RulesRepoImpl) Create(db utils.Executer, payload CreateRuleDTO) (Rule, error) {
+	conditions, err := json.Marshal(payload.Conditions)
+	if err != nil {
+		return Rule{}, err
+	}
+	actions, err := json.Marshal(payload.Actions)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	query, args, err := squirrel.Insert("rules").
+		Columns("id", "user_id", "name", "priority", "conditions", "actions").
+		Values(ulid.Make().String(), payload.UserID, payload.Name, payload.Priority, conditions, actions).
+		Suffix("RETURNING id, user_id, name, priority, conditions, actions, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return scanRule(db.QueryRow(query, args...))
+}
+
+func (r *RulesRepoImpl) List(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Rule, error) {
+	query := squirrel.Select("id", "user_id", "name", "priority", "conditions", "actions", "created_at").
+		From("rules").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *RulesRepoImpl) Count(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	countQuery := squirrel.
+		Select("COUNT(*)").
+		From("rules").
+		PlaceholderFormat(squirrel.Dollar)
+
+	countQuery = utils.QueryOptsToSquirrel(countQuery, filter)
+
+	sql, args, err := countQuery.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow(sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *RulesRepoImpl) Update(db utils.Executer, id string, payload UpdateRuleDTO) (Rule, error) {
+	if !utils.HasAtLeastOneField(payload) {
+		return Rule{}, errors.New("no fields to update")
+	}
+
+	query := squirrel.Update("rules").
+		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING id, user_id, name, priority, conditions, actions, created_at").
+		PlaceholderFormat(squirrel.Dollar)
+
+	if payload.Name != nil {
+		query = query.Set("name", *payload.Name)
+	}
+
+	if payload.Priority != nil {
+		query = query.Set("priority", *payload.Priority)
+	}
+
+	if payload.Conditions != nil {
+		conditions, err := json.Marshal(payload.Conditions)
+		if err != nil {
+			return Rule{}, err
+		}
+		query = query.Set("conditions", conditions)
+	}
+
+	if payload.Actions != nil {
+		actions, err := json.Marshal(payload.Actions)
+		if err != nil {
+			return Rule{}, err
+		}
+		query = query.Set("actions", actions)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return scanRule(db.QueryRow(sql, args...))
+}
+
+func (r *RulesRepoImpl) DeleteByID(db utils.Executer, id string) error {
+	sql, args, err := squirrel.Delete("rules").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
+
+func (r *RulesRepoImpl) ListTargets(db utils.Executer, userID string) ([]RuleTarget, error) {
+	rows, err := db.Query(listRuleTargetsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make([]RuleTarget, 0)
+	for rows.Next() {
+		var target RuleTarget
+		if err := rows.Scan(
+			&target.TransactionID,
+			&target.Subject.Name,
+			&target.Subject.Note,
+			&target.Subject.Type,
+			&target.Subject.Amount,
+			&target.Subject.Account,
+			&target.Subject.CategoryID,
+			&target.Subject.Payee,
+			pq.Array(&target.Subject.Tags),
+		); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, rows.Err()
+}
+
+// UpdateTarget persists the fields rules can change
+func (r *RulesRepoImpl) UpdateTarget(db utils.Executer, transactionID string, subject Subject) error {
+	sql, args, err := squirrel.Update("transactions").
+		Set("name", subject.Name).
+		Set("category_id", subject.CategoryID).
+		Set("payee", subject.Payee).
+		Set("tags", pq.Array(subject.Tags)).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": transactionID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+
+	return err
+}
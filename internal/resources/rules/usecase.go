@@ -0,0 +1,349 @@
+package rules
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+type RulesUseCase interface {
+	Create(payload CreateRuleDTO) (Rule, error)
+	List(filter *utils.QueryOptsBuilder) ([]Rule, error)
+	Count(filter *utils.QueryOptsBuilder) (int, error)
+	Update(id string, userID string, payload UpdateRuleDTO) (Rule, error)
+	DeleteByID(id string, userID string) error
+	Apply(userID string, subject Subject) (Subject, error)
+	Run(userID string, dryRun bool, overwrite bool) ([]RuleChange, error)
+}
+
+type RulesUseCaseImpl struct {
+	repo              RulesRepo
+	categoriesUseCase categories.CategoriesUseCase
+	db                *sql.DB
+}
+
+func NewRulesUseCase(repo RulesRepo, categoriesUseCase categories.CategoriesUseCase, db *sql.DB) RulesUseCase {
+	return &RulesUseCaseImpl{
+		repo:              repo,
+		categoriesUseCase: categoriesUseCase,
+		db:                db,
+	}
+}
+
+func containsFold(text string, substr string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(substr))
+}
+
+func (c RuleConditions) IsEmpty() bool {
+	return !utils.HasAtLeastOneField(c)
+}
+
+func (c RuleConditions) Matches(subject Subject) bool {
+	if c.NameContains != nil && !containsFold(subject.Name, *c.NameContains) {
+		return false
+	}
+
+	if c.NoteContains != nil && (subject.Note == nil || !containsFold(*subject.Note, *c.NoteContains)) {
+		return false
+	}
+
+	if c.AmountMin != nil && subject.Amount < *c.AmountMin {
+		return false
+	}
+
+	if c.AmountMax != nil && subject.Amount > *c.AmountMax {
+		return false
+	}
+
+	if c.Type != nil && subject.Type != *c.Type {
+		return false
+	}
+
+	if c.Account != nil && (subject.Account == nil || !strings.EqualFold(*subject.Account, *c.Account)) {
+		return false
+	}
+
+	return true
+}
+
+func (a RuleActions) IsEmpty() bool {
+	return a.SetCategoryID == nil && len(a.AddTags) == 0 && a.Rename == nil && a.SetPayee == nil
+}
+
+// ApplyRules runs the rules, already sorted by priority, over a subject.
+// Conditions are always checked against the original subject so a rename
+// doesn't change which rules match. The first matching rule that sets a field
+// wins, and category and payee are only filled when empty unless overwrite is
+// set, while tags of every matching rule are added. It returns the resulting
+// subject and the IDs of the rules that matched
+func ApplyRules(rules []Rule, subject Subject, overwrite bool) (Subject, []string) {
+	result := subject
+	result.Tags = make([]string, 0, len(subject.Tags))
+	result.Tags = append(result.Tags, subject.Tags...)
+
+	matched := make([]string, 0)
+	renamed, categorized, payeeSet := false, false, false
+	for _, rule := range rules {
+		if rule.Conditions.IsEmpty() || !rule.Conditions.Matches(subject) {
+			continue
+		}
+		matched = append(matched, rule.ID)
+
+		if rule.Actions.Rename != nil && !renamed {
+			result.Name = *rule.Actions.Rename
+			renamed = true
+		}
+
+		if rule.Actions.SetCategoryID != nil && !categorized && (overwrite || subject.CategoryID == nil) {
+			result.CategoryID = rule.Actions.SetCategoryID
+			categorized = true
+		}
+
+		if rule.Actions.SetPayee != nil && !payeeSet && (overwrite || subject.Payee == nil) {
+			result.Payee = rule.Actions.SetPayee
+			payeeSet = true
+		}
+
+		for _, tag := range rule.Actions.AddTags {
+			if !slices.Contains(result.Tags, tag) {
+				result.Tags = append(result.Tags, tag)
+			}
+		}
+	}
+
+	return result, matched
+}
+
+// changed tells if rules changed any field they can set
+func changed(before Subject, after Subject) bool {
+	samePointer := func(a, b *string) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+
+	return before.Name != after.Name ||
+		!samePointer(before.CategoryID, after.CategoryID) ||
+		!samePointer(before.Payee, after.Payee) ||
+		!slices.Equal(before.Tags, after.Tags)
+}
+
+func (uc *RulesUseCaseImpl) validateRule(userID string, conditions RuleConditions, actions RuleActions) error {
+	if conditions.IsEmpty() {
+		return ErrEmptyConditions
+	}
+
+	if actions.IsEmpty() {
+		return ErrEmptyActions
+	}
+
+	if conditions.AmountMin != nil && conditions.AmountMax != nil && *conditions.AmountMin > *conditions.AmountMax {
+		return ErrInvalidAmountRange
+	}
+
+	if actions.SetCategoryID != nil {
+		categoryExists, err := uc.categoriesUseCase.Count(utils.QueryOpts().
+			And("id", "eq", *actions.SetCategoryID).
+			And("user_id", "eq", userID))
+		if err != nil {
+			return ErrFailedToCheckCategory
+		}
+
+		if categoryExists == 0 {
+			return ErrCategoryNotFound
+		}
+	}
+
+	return nil
+}
+
+func (uc *RulesUseCaseImpl) Create(payload CreateRuleDTO) (Rule, error) {
+	if err := uc.validateRule(payload.UserID, payload.Conditions, payload.Actions); err != nil {
+		return Rule{}, err
+	}
+
+	rule, err := uc.repo.Create(uc.db, payload)
+	if err != nil {
+		return Rule{}, ErrFailedToCreateRule
+	}
+
+	return rule, nil
+}
+
+func (uc *RulesUseCaseImpl) List(filter *utils.QueryOptsBuilder) ([]Rule, error) {
+	rules, err := uc.repo.List(uc.db, filter)
+	if err != nil {
+		return nil, ErrFailedToListRules
+	}
+	return rules, nil
+}
+
+func (uc *RulesUseCaseImpl) Count(filter *utils.QueryOptsBuilder) (int, error) {
+	count, err := uc.repo.Count(uc.db, filter)
+	if err != nil {
+		return 0, ErrFailedToCountRules
+	}
+	return count, nil
+}
+
+func (uc *RulesUseCaseImpl) Update(id string, userID string, payload UpdateRuleDTO) (Rule, error) {
+	rules, err := uc.repo.List(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return Rule{}, ErrFailedToListRules
+	}
+
+	if len(rules) == 0 {
+		return Rule{}, ErrRuleNotFound
+	}
+
+	conditions, actions := rules[0].Conditions, rules[0].Actions
+	if payload.Conditions != nil {
+		conditions = *payload.Conditions
+	}
+	if payload.Actions != nil {
+		actions = *payload.Actions
+	}
+
+	if err := uc.validateRule(userID, conditions, actions); err != nil {
+		return Rule{}, err
+	}
+
+	rule, err := uc.repo.Update(uc.db, id, payload)
+	if err != nil {
+		return Rule{}, ErrFailedToUpdateRule
+	}
+
+	return rule, nil
+}
+
+func (uc *RulesUseCaseImpl) DeleteByID(id string, userID string) error {
+	exists, err := uc.repo.Count(uc.db, utils.QueryOpts().
+		And("id", "eq", id).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return ErrFailedToCountRules
+	}
+
+	if exists == 0 {
+		return ErrRuleNotFound
+	}
+
+	if err := uc.repo.DeleteByID(uc.db, id); err != nil {
+		return ErrFailedToDeleteRule
+	}
+
+	return nil
+}
+
+func (uc *RulesUseCaseImpl) listUserRules(userID string) ([]Rule, error) {
+	rules, err := uc.repo.List(uc.db, utils.QueryOpts().
+		And("user_id", "eq", userID).
+		OrderBy("priority", "asc").
+		OrderBy("created_at", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListRules
+	}
+	return rules, nil
+}
+
+// Apply runs the rules of the user over a transaction about to be created.
+// A category set by a rule may have been deleted since, in that case the
+// subject is left uncategorized
+func (uc *RulesUseCaseImpl) Apply(userID string, subject Subject) (Subject, error) {
+	rules, err := uc.listUserRules(userID)
+	if err != nil {
+		return Subject{}, err
+	}
+
+	result, _ := ApplyRules(rules, subject, false)
+
+	if result.CategoryID != nil && result.CategoryID != subject.CategoryID {
+		categoryExists, err := uc.categoriesUseCase.Count(utils.QueryOpts().
+			And("id", "eq", *result.CategoryID).
+			And("user_id", "eq", userID))
+		if err != nil {
+			return Subject{}, ErrFailedToCheckCategory
+		}
+
+		if categoryExists == 0 {
+			result.CategoryID = subject.CategoryID
+		}
+	}
+
+	return result, nil
+}
+
+// Run applies the rules of the user to every transaction already created and
+// returns what changed, persisting the changes unless it's a dry run
+func (uc *RulesUseCaseImpl) Run(userID string, dryRun bool, overwrite bool) ([]RuleChange, error) {
+	rules, err := uc.listUserRules(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userCategories, err := uc.categoriesUseCase.List(utils.QueryOpts().And("user_id", "eq", userID))
+	if err != nil {
+		return nil, ErrFailedToCheckCategory
+	}
+
+	categoryIDs := make(map[string]bool)
+	for _, category := range userCategories {
+		categoryIDs[category.ID] = true
+	}
+
+	// rules pointing to deleted categories keep their other actions
+	for i, rule := range rules {
+		if rule.Actions.SetCategoryID != nil && !categoryIDs[*rule.Actions.SetCategoryID] {
+			rules[i].Actions.SetCategoryID = nil
+		}
+	}
+
+	targets, err := uc.repo.ListTargets(uc.db, userID)
+	if err != nil {
+		return nil, ErrFailedToListTargets
+	}
+
+	changes := make([]RuleChange, 0)
+	for _, target := range targets {
+		if target.Subject.Tags == nil {
+			target.Subject.Tags = make([]string, 0)
+		}
+
+		after, matched := ApplyRules(rules, target.Subject, overwrite)
+		if !changed(target.Subject, after) {
+			continue
+		}
+
+		changes = append(changes, RuleChange{
+			TransactionID: target.TransactionID,
+			RuleIDs:       matched,
+			Before:        target.Subject,
+			After:         after,
+		})
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return nil, ErrFailedToApplyRules
+	}
+
+	for _, change := range changes {
+		if err := uc.repo.UpdateTarget(tx, change.TransactionID, change.After); err != nil {
+			tx.Rollback()
+			return nil, ErrFailedToApplyRules
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrFailedToApplyRules
+	}
+
+	return changes, nil
+}
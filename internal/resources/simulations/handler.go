@@ -0,0 +1,95 @@
+package simulations
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	simulationsUseCase SimulationsUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		simulationsUseCase: NewSimulationsUseCase(
+			forecast.NewForecastUseCase(forecast.NewForecastRepo(db),
+				summary.NewSummaryUseCase(summary.NewSummaryRepo(db), db),
+				recurring.NewRecurringUseCase(recurring.NewRecurringRepo(db),
+					categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+					db),
+				db)),
+	}
+}
+
+// @Summary Simulate purchases
+// @Description Project the next months as if the given transactions were created, next to the baseline forecast, without persisting them
+// @Tags simulations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body CreateSimulationRequest true "Hypothetical transactions"
+// @Success 200 {object} CreateSimulationResponse "Baseline and simulated projections"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /simulations [post]
+func (api *API) Create(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body CreateSimulationRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	months := 6
+	if body.Months != nil {
+		months = *body.Months
+	}
+
+	payloads := make([]transactions.CreateTransactionDTO, len(body.Transactions))
+	for i, transaction := range body.Transactions {
+		entries := make([]transactions.CreateEntryDTO, len(transaction.Entries))
+		for j, entry := range transaction.Entries {
+			entries[j] = transactions.CreateEntryDTO{
+				Amount:        entry.Amount,
+				ReferenceDate: entry.ReferenceDate,
+			}
+		}
+
+		payloads[i] = transactions.CreateTransactionDTO{
+			UserID:     userID,
+			Name:       transaction.Name,
+			CategoryID: transaction.CategoryID,
+			Note:       transaction.Note,
+			Type:       transaction.Type,
+			Entries:    entries,
+		}
+	}
+
+	simulation, err := api.simulationsUseCase.Simulate(userID, months, payloads)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, CreateSimulationResponse{
+		Data: CreateSimulationResponseData{
+			Simulation: simulation,
+		},
+	})
+}
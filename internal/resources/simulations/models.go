@@ -0,0 +1,44 @@
+package simulations
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type CreateSimulationRequest struct {
+	Months       *int                                    `json:"months" binding:"omitempty,min=1,max=60"`
+	Transactions []transactions.CreateTransactionRequest `json:"transactions" binding:"required,min=1,max=20,dive"`
+}
+
+type CreateSimulationResponse struct {
+	Data CreateSimulationResponseData `json:"data"`
+}
+
+type CreateSimulationResponseData struct {
+	Simulation Simulation `json:"simulation"`
+}
+
+// Baseline and simulated projections of the same period side by side
+type SimulationPeriod struct {
+	Period           string  `json:"period"`
+	BaselineNet      float64 `json:"baseline_net"`
+	SimulatedNet     float64 `json:"simulated_net"`
+	BaselineBalance  float64 `json:"baseline_balance"`
+	SimulatedBalance float64 `json:"simulated_balance"`
+	Difference       float64 `json:"difference"`
+	Negative         bool    `json:"negative"`
+}
+
+type Simulation struct {
+	BaselineOpeningBalance  float64            `json:"baseline_opening_balance"`
+	SimulatedOpeningBalance float64            `json:"simulated_opening_balance"`
+	Periods                 []SimulationPeriod `json:"periods"`
+	NegativePeriods         []string           `json:"negative_periods"`
+	Baseline                forecast.Forecast  `json:"baseline"`
+	Simulated               forecast.Forecast  `json:"simulated"`
+}
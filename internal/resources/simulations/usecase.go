@@ -0,0 +1,73 @@
+package simulations
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+)
+
+type SimulationsUseCase interface {
+	Simulate(userID string, months int, payloads []transactions.CreateTransactionDTO) (Simulation, error)
+}
+
+type SimulationsUseCaseImpl struct {
+	forecastUseCase forecast.ForecastUseCase
+}
+
+func NewSimulationsUseCase(forecastUseCase forecast.ForecastUseCase) SimulationsUseCase {
+	return &SimulationsUseCaseImpl{
+		forecastUseCase: forecastUseCase,
+	}
+}
+
+// Simulate validates the hypothetical transactions as if they were created and
+// applies their entries in memory on top of the forecast, nothing is persisted
+func (uc *SimulationsUseCaseImpl) Simulate(userID string, months int, payloads []transactions.CreateTransactionDTO) (Simulation, error) {
+	entries := make([]forecast.ProjectedEntry, 0)
+	for _, payload := range payloads {
+		if err := transactions.ValidateCreateTransaction(payload); err != nil {
+			return Simulation{}, err
+		}
+
+		for _, entry := range payload.Entries {
+			referenceDate, _ := time.Parse("2006-01-02", entry.ReferenceDate)
+			entries = append(entries, forecast.ProjectedEntry{
+				Period: referenceDate.Format("200601"),
+				Amount: transactions.SignedAmount(payload.Type, entry.Amount),
+				Income: payload.Type == constants.Income,
+			})
+		}
+	}
+
+	baseline, err := uc.forecastUseCase.Forecast(userID, months)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	simulated := baseline.WithEntries(entries)
+
+	simulation := Simulation{
+		BaselineOpeningBalance:  baseline.OpeningBalance,
+		SimulatedOpeningBalance: simulated.OpeningBalance,
+		Periods:                 make([]SimulationPeriod, len(baseline.Periods)),
+		NegativePeriods:         simulated.NegativePeriods,
+		Baseline:                baseline,
+		Simulated:               simulated,
+	}
+
+	for i, period := range baseline.Periods {
+		simulation.Periods[i] = SimulationPeriod{
+			Period:           period.Period,
+			BaselineNet:      period.Net,
+			SimulatedNet:     simulated.Periods[i].Net,
+			BaselineBalance:  period.Balance,
+			SimulatedBalance: simulated.Periods[i].Balance,
+			Difference:       simulated.Periods[i].Balance - period.Balance,
+			Negative:         simulated.Periods[i].Negative,
+		}
+	}
+
+	return simulation, nil
+}
@@ -0,0 +1,14 @@
+package summary
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrInvalidPeriod         = utils.NewHTTPError(http.StatusBadRequest, "from and to must be periods in the YYYYMM format")
+	ErrInvalidPeriodRange    = utils.NewHTTPError(http.StatusBadRequest, "from must not be after to")
+	ErrPeriodRangeTooLong    = utils.NewHTTPError(http.StatusBadRequest, "period range must have at most 120 periods")
+	ErrFailedToListSummaries = utils.NewHTTPError(http.StatusInternalServerError, "failed to list period summaries")
+)
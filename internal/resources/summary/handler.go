@@ -0,0 +1,51 @@
+package summary
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type API struct {
+	summaryUseCase SummaryUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		summaryUseCase: NewSummaryUseCase(NewSummaryRepo(db), db),
+	}
+}
+
+// @Summary List period summaries
+// @Description List income, expenses, net result, cumulative balance and entries count of each period in a range
+// @Tags summary
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "First period" example(202501)
+// @Param to query string true "Last period" example(202512)
+// @Success 200 {object} ListPeriodSummariesResponse "Summary of each period"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /summary [get]
+func (api *API) ListPeriodSummaries(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	summaries, err := api.summaryUseCase.ListPeriodSummaries(userID, ctx.Query("from"), ctx.Query("to"))
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListPeriodSummariesResponse{
+		Data: ListPeriodSummariesResponseData{
+			Periods: summaries,
+		},
+	})
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSummaryRepo struct {
+	mock.Mock
+}
+
+func (m *MockSummaryRepo) ListPeriodSummaries(db utils.Executer, userID string, from string, to string) ([]summary.PeriodSummary, error) {
+	args := m.Called(db, userID, from, to)
+	return args.Get(0).([]summary.PeriodSummary), args.Error(1)
+}
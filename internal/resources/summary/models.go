@@ -0,0 +1,29 @@
+package summary
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type ListPeriodSummariesResponse struct {
+	Data ListPeriodSummariesResponseData `json:"data"`
+}
+
+type ListPeriodSummariesResponseData struct {
+	Periods []PeriodSummary `json:"periods"`
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Totals of a period, balance is the net of every period up to this one
+type PeriodSummary struct {
+	Period       string  `json:"period"`
+	Income       float64 `json:"income"`
+	Expenses     float64 `json:"expenses"`
+	Net          float64 `json:"net"`
+	Balance      float64 `json:"balance"`
+	EntriesCount int     `json:"entries_count"`
+}
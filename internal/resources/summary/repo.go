@@ -0,0 +1,76 @@
+package summary
+
+import (
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+// Every period between from and to is returned, even without entries, and the
+// balance starts from the net of all the periods before from
+const listPeriodSummariesQuery = `
+WITH periods AS (
+    SELECT TO_CHAR(p, 'YYYYMM') AS period
+    FROM GENERATE_SERIES(TO_DATE($2, 'YYYYMM'), TO_DATE($3, 'YYYYMM'), INTERVAL '1 month') p
+),
+totals AS (
+    SELECT
+        period,
+        SUM(amount) FILTER (WHERE category = 'income') AS income,
+        SUM(amount) FILTER (WHERE category <> 'income') AS expenses,
+        SUM(amount) AS net,
+        COUNT(*) AS entries_count
+    FROM v_entries
+    WHERE user_id = $1 AND period <= $3
+    GROUP BY period
+),
+opening AS (
+    SELECT COALESCE(SUM(net), 0) AS balance
+    FROM totals
+    WHERE period < $2
+)
+SELECT
+    p.period,
+    COALESCE(t.income, 0),
+    COALESCE(t.expenses, 0),
+    COALESCE(t.net, 0),
+    (SELECT balance FROM opening) + SUM(COALESCE(t.net, 0)) OVER (ORDER BY p.period),
+    COALESCE(t.entries_count, 0)
+FROM periods p
+LEFT JOIN totals t ON t.period = p.period
+ORDER BY p.period`
+
+type SummaryRepo interface {
+	ListPeriodSummaries(db utils.Executer, userID string, from string, to string) ([]PeriodSummary, error)
+}
+
+type SummaryRepoImpl struct {
+}
+
+func NewSummaryRepo(db utils.Executer) SummaryRepo {
+	return &SummaryRepoImpl{}
+}
+
+func (r *SummaryRepoImpl) ListPeriodSummaries(db utils.Executer, userID string, from string, to string) ([]PeriodSummary, error) {
+	rows, err := db.Query(listPeriodSummariesQuery, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]PeriodSummary, 0)
+	for rows.Next() {
+		var summary PeriodSummary
+		if err := rows.Scan(
+			&summary.Period,
+			&summary.Income,
+			&summary.Expenses,
+			&summary.Net,
+			&summary.Balance,
+			&summary.EntriesCount,
+		); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
@@ -0,0 +1,62 @@
+package summary
+
+import (
+	"database/sql"
+	"time"
+)
+
+const maxPeriods = 120
+
+type SummaryUseCase interface {
+	ListPeriodSummaries(userID string, from string, to string) ([]PeriodSummary, error)
+}
+
+type SummaryUseCaseImpl struct {
+	repo SummaryRepo
+	db   *sql.DB
+}
+
+func NewSummaryUseCase(repo SummaryRepo, db *sql.DB) SummaryUseCase {
+	return &SummaryUseCaseImpl{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// ValidatePeriodRange checks that from and to are periods in order and at
+// most maxPeriods apart, for every listing over a range of periods
+func ValidatePeriodRange(from string, to string) error {
+	fromDate, err := time.Parse("200601", from)
+	if err != nil {
+		return ErrInvalidPeriod
+	}
+
+	toDate, err := time.Parse("200601", to)
+	if err != nil {
+		return ErrInvalidPeriod
+	}
+
+	if fromDate.After(toDate) {
+		return ErrInvalidPeriodRange
+	}
+
+	months := (toDate.Year()-fromDate.Year())*12 + int(toDate.Month()-fromDate.Month()) + 1
+	if months > maxPeriods {
+		return ErrPeriodRangeTooLong
+	}
+
+	return nil
+}
+
+func (uc *SummaryUseCaseImpl) ListPeriodSummaries(userID string, from string, to string) ([]PeriodSummary, error) {
+	if err := ValidatePeriodRange(from, to); err != nil {
+		return nil, err
+	}
+
+	summaries, err := uc.repo.ListPeriodSummaries(uc.db, userID, from, to)
+	if err != nil {
+		return nil, ErrFailedToListSummaries
+	}
+
+	return summaries, nil
+}
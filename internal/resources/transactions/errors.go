@@ -10,7 +10,17 @@ import (
 var (
 	ErrFailedToFetchEntries                 = fmt.Errorf("failed to fetch entries")
 	ErrToCountEntries                       = fmt.Errorf("failed to count entries")
+	ErrToSearchEntries                      = utils.NewHTTPError(http.StatusInternalServerError, "failed to search entries")
+	ErrSearchWithCursor                     = utils.NewHTTPError(http.StatusBadRequest, "cursor pagination is not available when searching with q")
+	ErrToAggregateEntries                   = utils.NewHTTPError(http.StatusInternalServerError, "failed to aggregate entries")
 	ItWasNotPossibleDeleteTransactionErr    = utils.NewHTTPError(http.StatusInternalServerError, "It was not possible to delete transaction")
 	TransactionNotFound                     = utils.NewHTTPError(http.StatusNotFound, "Transaction not found")
 	AnErrorOccuredWhileFetchingTransactions = utils.NewHTTPError(http.StatusInternalServerError, "An error occured while fetching transactions")
+	ErrFailedToListItems                    = utils.NewHTTPError(http.StatusInternalServerError, "failed to list transaction items")
+	ErrInvalidPixPayload                    = utils.NewHTTPError(http.StatusBadRequest, "payload is not a valid BR Code")
+	ErrInvalidPixCRC                        = utils.NewHTTPError(http.StatusBadRequest, "payload CRC is invalid")
+	ErrNotPixPayload                        = utils.NewHTTPError(http.StatusBadRequest, "payload is not a PIX BR Code")
+	ErrFailedToListPayees                   = utils.NewHTTPError(http.StatusInternalServerError, "failed to list payees")
+	ErrInvalidExportFormat                  = utils.NewHTTPError(http.StatusBadRequest, "format must be csv or xlsx")
+	ErrInvalidDecimalSeparator              = utils.NewHTTPError(http.StatusBadRequest, "decimal_separator must be . or ,")
 )
@@ -0,0 +1,245 @@
+package transactions
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+var entryExportColumns = []string{"date", "name", "description", "category", "type", "installment", "amount", "payee", "account", "tags"}
+
+// EntriesWriter writes entries one at a time to an export file, Close must be
+// called to finish the file
+type EntriesWriter interface {
+	Write(entry ViewEntry) error
+	Close() error
+}
+
+// NewEntriesWriter returns the writer of an export format, the decimal
+// separator only applies to csv since xlsx stores numbers unformatted
+func NewEntriesWriter(w io.Writer, format string, decimalSeparator string) (EntriesWriter, error) {
+	if decimalSeparator != "." && decimalSeparator != "," {
+		return nil, ErrInvalidDecimalSeparator
+	}
+
+	switch format {
+	case ExportFormatCSV:
+		return NewEntriesCSVWriter(w, decimalSeparator)
+	case ExportFormatXLSX:
+		return NewEntriesXLSXWriter(w)
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
+
+func ExportContentType(format string) string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func exportInstallment(entry ViewEntry) string {
+	return fmt.Sprintf("%d/%d", entry.Installment, entry.TotalInstallments)
+}
+
+func exportText(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// csvText keeps spreadsheets from running user text as a formula, text
+// starting like one gets an apostrophe in front so it's read as text
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type entriesCSVWriter struct {
+	w                *csv.Writer
+	decimalSeparator string
+}
+
+// NewEntriesCSVWriter writes the header right away. With a comma as decimal
+// separator the columns are split by semicolons and the file starts with a
+// UTF-8 BOM, which is what spreadsheets in those locales expect
+func NewEntriesCSVWriter(w io.Writer, decimalSeparator string) (EntriesWriter, error) {
+	if decimalSeparator == "," {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return nil, err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if decimalSeparator == "," {
+		cw.Comma = ';'
+	}
+
+	if err := cw.Write(entryExportColumns); err != nil {
+		return nil, err
+	}
+
+	return &entriesCSVWriter{w: cw, decimalSeparator: decimalSeparator}, nil
+}
+
+func (cw *entriesCSVWriter) Write(entry ViewEntry) error {
+	amount := strconv.FormatFloat(entry.Amount, 'f', 2, 64)
+	if cw.decimalSeparator == "," {
+		amount = strings.Replace(amount, ".", ",", 1)
+	}
+
+	return cw.w.Write([]string{
+		entry.ReferenceDate,
+		csvText(entry.Name),
+		csvText(exportText(entry.Description)),
+		csvText(exportText(entry.CategoryName)),
+		string(entry.Type),
+		exportInstallment(entry),
+		amount,
+		csvText(exportText(entry.Payee)),
+		csvText(exportText(entry.Account)),
+		csvText(strings.Join(entry.Tags, ", ")),
+	})
+}
+
+func (cw *entriesCSVWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Parts of the package besides the sheet, which are the same for every file.
+// Style 1 shows a date and style 2 a number with two decimals
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Entries" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`},
+}
+
+const (
+	xlsxDateStyle   = 1
+	xlsxAmountStyle = 2
+)
+
+// day 0 of the 1900 date system, shifted to absorb its leap year bug
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type entriesXLSXWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+// NewEntriesXLSXWriter writes a single sheet workbook. The sheet is the last
+// part of the package so its rows go straight to w as they're written
+func NewEntriesXLSXWriter(w io.Writer) (EntriesWriter, error) {
+	zw := zip.NewWriter(w)
+
+	for _, part := range xlsxStaticParts {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	xw := &entriesXLSXWriter{zip: zw, sheet: bufio.NewWriter(sw)}
+	xw.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	xw.startRow()
+	for i, column := range entryExportColumns {
+		xw.stringCell(i, column)
+	}
+	xw.sheet.WriteString("</row>")
+
+	return xw, nil
+}
+
+func (xw *entriesXLSXWriter) Write(entry ViewEntry) error {
+	xw.startRow()
+
+	if date, err := time.Parse("2006-01-02", entry.ReferenceDate); err == nil {
+		xw.numberCell(0, strconv.Itoa(int(date.Sub(xlsxEpoch).Hours()/24)), xlsxDateStyle)
+	} else {
+		xw.stringCell(0, entry.ReferenceDate)
+	}
+	xw.stringCell(1, entry.Name)
+	xw.stringCell(2, exportText(entry.Description))
+	xw.stringCell(3, exportText(entry.CategoryName))
+	xw.stringCell(4, string(entry.Type))
+	xw.stringCell(5, exportInstallment(entry))
+	xw.numberCell(6, strconv.FormatFloat(entry.Amount, 'f', 2, 64), xlsxAmountStyle)
+	xw.stringCell(7, exportText(entry.Payee))
+	xw.stringCell(8, exportText(entry.Account))
+	xw.stringCell(9, strings.Join(entry.Tags, ", "))
+
+	_, err := xw.sheet.WriteString("</row>")
+	return err
+}
+
+func (xw *entriesXLSXWriter) Close() error {
+	if _, err := xw.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zip.Close()
+}
+
+func (xw *entriesXLSXWriter) startRow() {
+	xw.row++
+	fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.row)
+}
+
+func (xw *entriesXLSXWriter) cellRef(column int) string {
+	// the export has less than 26 columns, so a single letter is enough
+	return fmt.Sprintf("%c%d", 'A'+column, xw.row)
+}
+
+// stringCell writes text as an inline string, which spreadsheets never
+// evaluate, so text starting with = isn't run as a formula
+func (xw *entriesXLSXWriter) stringCell(column int, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, xw.cellRef(column))
+	xml.EscapeText(xw.sheet, []byte(value))
+	xw.sheet.WriteString("</t></is></c>")
+}
+
+func (xw *entriesXLSXWriter) numberCell(column int, value string, style int) {
+	fmt.Fprintf(xw.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, xw.cellRef(column), style, value)
+}
@@ -2,9 +2,14 @@ package transactions
 
 import (
 	"database/sql"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
 	"github.com/felipe1496/open-wallet/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,15 +20,17 @@ type API struct {
 }
 
 func NewHandler(db *sql.DB) *API {
+	categoriesUseCase := categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db)
 	return &API{
 		transactionsUseCase: NewTransactionsUseCase(NewTransactionsRepo(db),
-			categories.NewCategoriesUseCase(categories.NewCategoriesRepo(db), db),
+			categoriesUseCase,
+			rules.NewRulesUseCase(rules.NewRulesRepo(db), categoriesUseCase, db),
 			db),
 	}
 }
 
 // @Summary List entries
-// @Description List a detailed view of entries joined with transactions for a given period
+// @Description List a detailed view of entries joined with transactions for a given period, with totals over every entry matching the filter
 // @Tags transactions
 // @Security BearerAuth
 // @Accept json
@@ -31,7 +38,11 @@ func NewHandler(db *sql.DB) *API {
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
 // @Param order_by query string false "Sort field" example(name:asc,created_at:desc)
+// @Param cursor query string false "Keyset pagination cursor, send it empty to start paginating by cursor"
+// @Param filter query string false "Entries filter" example(period eq '202501')
+// @Param q query string false "Full-text search over transaction names and descriptions, results are ranked and get a snippet, safe HTML whose only tags are the <mark> around the matches" example(farmácia)
 // @Success 200 {object} ListEntriesResponse "List of entries"
+// @Failure 400 {object} utils.HTTPError "Bad request"
 // @Failure 401 {object} utils.HTTPError "Unauthorized"
 // @Failure 500 {object} utils.HTTPError "Internal server error"
 // @Router /transactions/entries [get]
@@ -40,46 +51,129 @@ func (api *API) ListEntries(ctx *gin.Context) {
 	page := ctx.GetInt("page")
 	perPage := ctx.GetInt("per_page")
 	queryOpts := ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID)
+	search := strings.TrimSpace(ctx.Query("q"))
+
+	var entries []ViewEntry
+	var err error
+	if search != "" {
+		// results are ranked by relevance, which can't be paginated by keyset
+		if queryOpts.CursorPagination {
+			ctx.JSON(ErrSearchWithCursor.StatusCode, ErrSearchWithCursor)
+			return
+		}
 
-	entries, err := api.transactionsUseCase.ListViewEntries(queryOpts)
+		queryOpts.AndExpr(SearchCondition(search))
+		entries, err = api.transactionsUseCase.SearchViewEntries(search, queryOpts)
+	} else {
+		entries, err = api.transactionsUseCase.ListViewEntries(queryOpts)
+	}
 
 	if err != nil {
-		apiErr := err.(*utils.HTTPError)
+		apiErr := utils.GetApiErr(err)
 		ctx.JSON(apiErr.StatusCode, apiErr)
 		return
 	}
 
-	count, err := api.transactionsUseCase.CountViewEntries(utils.QueryOpts().
-		And("user_id", "eq", userID))
+	aggregates, err := api.transactionsUseCase.AggregateViewEntries(utils.ForCount(queryOpts))
 
 	if err != nil {
-		apiErr := err.(*utils.HTTPError)
+		apiErr := utils.GetApiErr(err)
 		ctx.JSON(apiErr.StatusCode, apiErr)
 		return
 	}
 
-	nextPage := len(entries) > perPage
+	entries, meta, err := utils.Paginate(entries, queryOpts, page, perPage, api.transactionsUseCase.CountViewEntries)
 
-	if nextPage {
-		entries = entries[:len(entries)-1]
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
 	}
 
-	totalPages := (count + perPage - 1) / perPage
-
 	ctx.JSON(http.StatusOK, ListEntriesResponse{
 		Data: ListEntriesResponseData{
-			Entries: entries,
-		},
-		Query: utils.QueryMeta{
-			Page:       page,
-			PerPage:    perPage,
-			NextPage:   nextPage,
-			TotalPages: totalPages,
-			TotalItems: count,
+			Entries:    entries,
+			Aggregates: aggregates,
 		},
+		Query: meta,
 	})
 }
 
+// @Summary Export entries
+// @Description Download every entry matching the filter and order_by as a csv or xlsx file, without pagination. Rows are streamed as they're read. When no order is given entries go by date. In csv, text starting with =, +, -, @ gets an apostrophe in front so spreadsheets don't read it as a formula
+// @Tags transactions
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format, csv or xlsx" default(csv)
+// @Param decimal_separator query string false "Decimal separator of csv amounts, . or ,. With , columns are separated by ;" default(.)
+// @Param filter query string false "Filter expression, the same as listing entries" example(period eq '202501')
+// @Param order_by query string false "Order by field, the same as listing entries" example(reference_date:desc)
+// @Success 200 {file} file "Entries file"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /transactions/entries/export [get]
+func (api *API) ExportEntries(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	format := ctx.DefaultQuery("format", ExportFormatCSV)
+	decimalSeparator := ctx.DefaultQuery("decimal_separator", ".")
+	queryOpts := utils.Unpaginated(ctx.MustGet("query_opts").(*utils.QueryOptsBuilder).And("user_id", "eq", userID))
+
+	if len(queryOpts.Orders) == 0 {
+		queryOpts.OrderBy("reference_date", "asc").OrderBy("id", "asc")
+	}
+
+	// validates the options before anything is written
+	if _, err := NewEntriesWriter(io.Discard, format, decimalSeparator); err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	// the response only starts with the first row, so a failed query still
+	// gets a JSON error
+	var writer EntriesWriter
+	start := func() error {
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"entries.%s\"", format))
+		ctx.Header("Content-Type", ExportContentType(format))
+		ctx.Status(http.StatusOK)
+
+		var err error
+		writer, err = NewEntriesWriter(ctx.Writer, format, decimalSeparator)
+		return err
+	}
+
+	err := api.transactionsUseCase.EachViewEntry(queryOpts, func(entry ViewEntry) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(entry)
+	})
+
+	if err == nil && writer == nil {
+		err = start()
+	}
+
+	if err != nil {
+		if writer == nil {
+			apiErr := utils.GetApiErr(err)
+			ctx.JSON(apiErr.StatusCode, apiErr)
+			return
+		}
+		// the status is already sent, the client gets a truncated file
+		log.Printf("failed to export entries: %v", err)
+		ctx.Abort()
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("failed to export entries: %v", err)
+	}
+}
+
 // @Summary Delete Transaction By ID
 // @Description Delete a transaction and all entries related by the ID of the transaction
 // @Tags transactions
@@ -106,8 +200,76 @@ func (api *API) DeleteTransaction(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// @Summary List Transaction Items
+// @Description List the items of a purchase, like the products of an imported invoice
+// @Tags transactions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param transaction_id path string true "transaction ID"
+// @Success 200 {object} ListItemsResponse "Items of the transaction"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Not found"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /transactions/{transaction_id}/items [get]
+func (api *API) ListItems(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	id := ctx.Param("transaction_id")
+
+	items, err := api.transactionsUseCase.ListItems(id, userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListItemsResponse{
+		Data: ListItemsResponseData{
+			Items: items,
+		},
+	})
+}
+
+// @Summary Draft a transaction from PIX
+// @Description Read a PIX "copia e cola" (BR Code) validating its CRC and get an expense paid today prefilled from it. The merchant is matched against the payees already used, ignoring case and accents, taking their most common category, otherwise categories are suggested from the history
+// @Tags transactions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body DraftFromPixRequest true "PIX payload"
+// @Success 200 {object} DraftFromPixResponse "Transaction draft"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /transactions/from-pix [post]
+func (api *API) DraftFromPix(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body DraftFromPixRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	draft, err := api.transactionsUseCase.DraftFromPix(userID, body.Payload)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, DraftFromPixResponse{
+		Data: draft,
+	})
+}
+
 // @Summary Create a transaction
-// @Description Create a transaction with all of it entries
+// @Description Create a transaction with all of it entries, the rules of the user may rename, categorize, set the payee or tag it. When it ends up without a category, categories are suggested from the history
 // @Tags transactions
 // @Security BearerAuth
 // @Accept json
@@ -148,6 +310,9 @@ func (api *API) CreateTransaction(ctx *gin.Context) {
 		CategoryID: body.CategoryID,
 		Note:       body.Note,
 		Type:       body.Type,
+		Payee:      body.Payee,
+		Account:    body.Account,
+		Tags:       body.Tags,
 		Entries:    entriesDTO,
 	})
 
@@ -157,9 +322,17 @@ func (api *API) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
+	// the transaction is already created, so a failed suggestion only means
+	// the client gets none
+	var suggestions []categories.CategorySuggestion
+	if transaction.CategoryID == nil {
+		suggestions, _ = api.transactionsUseCase.SuggestCategories(userID, transaction.Name)
+	}
+
 	ctx.JSON(http.StatusCreated, CreateTransactionResponse{
 		Data: CreateTransactionResponseData{
-			Transaction: transaction,
+			Transaction:         transaction,
+			CategorySuggestions: suggestions,
 		},
 	})
 }
@@ -207,6 +380,9 @@ func (api *API) UpdateTransaction(ctx *gin.Context) {
 		Name:       body.Name,
 		Note:       body.Note,
 		CategoryID: body.CategoryID,
+		Payee:      body.Payee,
+		Account:    body.Account,
+		Tags:       body.Tags,
 		Entries:    entriesDTO,
 	})
 
@@ -11,22 +11,72 @@ type MockTransactionsRepo struct {
 	mock.Mock
 }
 
-func (m *MockTransactionsRepo) CreateEntry(input transactions.CreateEntryDTO, db utils.Executer) (transactions.Entry, error) {
-	args := m.Called(input, db)
+func (m *MockTransactionsRepo) CreateEntry(db utils.Executer, payload transactions.PersistEntryDTO) (transactions.Entry, error) {
+	args := m.Called(db, payload)
 	return args.Get(0).(transactions.Entry), args.Error(1)
 }
 
-func (m *MockTransactionsRepo) CreateTransaction(input transactions.CreateTransactionDTO, db utils.Executer) (transactions.Transaction, error) {
-	args := m.Called(input, db)
+func (m *MockTransactionsRepo) CreateTransaction(db utils.Executer, payload transactions.CreateTransactionDTO) (transactions.Transaction, error) {
+	args := m.Called(db, payload)
 	return args.Get(0).(transactions.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionsRepo) ListTransactions(db utils.Executer) ([]transactions.Transaction, error) {
-	args := m.Called(db)
+func (m *MockTransactionsRepo) CreateItem(db utils.Executer, payload transactions.PersistItemDTO) (transactions.TransactionItem, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(transactions.TransactionItem), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]transactions.TransactionItem, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]transactions.TransactionItem), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) ListViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) ([]transactions.ViewEntry, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).([]transactions.ViewEntry), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) EachViewEntry(db utils.Executer, filter *utils.QueryOptsBuilder, fn func(entry transactions.ViewEntry) error) error {
+	args := m.Called(db, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionsRepo) SearchViewEntries(db utils.Executer, search string, filter *utils.QueryOptsBuilder) ([]transactions.ViewEntry, error) {
+	args := m.Called(db, search, filter)
+	return args.Get(0).([]transactions.ViewEntry), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) CountViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error) {
+	args := m.Called(db, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) AggregateViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) (transactions.EntriesAggregate, error) {
+	args := m.Called(db, filter)
+	return args.Get(0).(transactions.EntriesAggregate), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) DeleteTransactionById(db utils.Executer, id string) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *MockTransactionsRepo) ListTransactions(db utils.Executer, filter *utils.QueryOptsBuilder) ([]transactions.Transaction, error) {
+	args := m.Called(db, filter)
 	return args.Get(0).([]transactions.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionsRepo) ListEntries(db utils.Executer) ([]transactions.Entry, error) {
-	args := m.Called(db)
-	return args.Get(0).([]transactions.Entry), args.Error(1)
+func (m *MockTransactionsRepo) UpdateTransaction(db utils.Executer, id string, payload transactions.UpdateTransactionDTO) (transactions.Transaction, error) {
+	args := m.Called(db, id, payload)
+	return args.Get(0).(transactions.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionsRepo) DeleteEntry(db utils.Executer, filter *utils.QueryOptsBuilder) error {
+	args := m.Called(db, filter)
+	return args.Error(0)
+}
+
+func (m *MockTransactionsRepo) ListPayees(db utils.Executer, userID string) ([]transactions.PayeeUsage, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]transactions.PayeeUsage), args.Error(1)
 }
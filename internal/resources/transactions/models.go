@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
 	"github.com/felipe1496/open-wallet/internal/utils"
 )
 
@@ -17,6 +18,9 @@ type CreateTransactionRequest struct {
 	CategoryID *string                   `json:"category_id" binding:"omitempty"`
 	Note       *string                   `json:"note" binding:"omitempty,min=0,max=400"`
 	Type       constants.TransactionType `json:"type" binding:"required,oneof=installment simple_expense income"`
+	Payee      *string                   `json:"payee" binding:"omitempty,min=1,max=100"`
+	Account    *string                   `json:"account" binding:"omitempty,min=1,max=100"`
+	Tags       []string                  `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
 	Entries    []CreateEntryRequest      `json:"entries" binding:"required,min=1,max=100,dive"`
 }
 
@@ -26,10 +30,13 @@ type CreateEntryRequest struct {
 }
 
 type UpdateTransactionRequest struct {
-	Update     []string              `json:"update" binding:"required,min=1,dive,oneof=name category_id note entries"`
+	Update     []string              `json:"update" binding:"required,min=1,dive,oneof=name category_id note entries payee account tags"`
 	Name       *string               `json:"name" binding:"omitempty,min=1,max=100"`
 	CategoryID *string               `json:"category_id" binding:"omitempty"`
 	Note       *string               `json:"note" binding:"omitempty,min=0,max=400"`
+	Payee      *string               `json:"payee" binding:"omitempty,min=1,max=100"`
+	Account    *string               `json:"account" binding:"omitempty,min=1,max=100"`
+	Tags       *[]string             `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
 	Entries    *[]UpdateEntryRequest `json:"entries" binding:"omitempty,min=1,max=100,dive"`
 }
 
@@ -51,7 +58,24 @@ type CreateTransactionResponse struct {
 }
 
 type CreateTransactionResponseData struct {
-	Transaction Transaction `json:"transaction"`
+	Transaction         Transaction                     `json:"transaction"`
+	CategorySuggestions []categories.CategorySuggestion `json:"category_suggestions,omitempty"`
+}
+
+type ListItemsResponse struct {
+	Data ListItemsResponseData `json:"data"`
+}
+
+type ListItemsResponseData struct {
+	Items []TransactionItem `json:"items"`
+}
+
+type DraftFromPixRequest struct {
+	Payload string `json:"payload" binding:"required,max=512"`
+}
+
+type DraftFromPixResponse struct {
+	Data PixDraft `json:"data"`
 }
 
 type ListEntriesResponse struct {
@@ -60,7 +84,8 @@ type ListEntriesResponse struct {
 }
 
 type ListEntriesResponseData struct {
-	Entries []ViewEntry `json:"entries"`
+	Entries    []ViewEntry      `json:"entries"`
+	Aggregates EntriesAggregate `json:"aggregates"`
 }
 
 // ==============================================================================
@@ -69,12 +94,18 @@ type ListEntriesResponseData struct {
 // ==============================================================================
 
 type CreateTransactionDTO struct {
-	UserID     string
-	Name       string
-	CategoryID *string
-	Note       *string
-	Type       constants.TransactionType
-	Entries    []CreateEntryDTO
+	UserID        string
+	Name          string
+	CategoryID    *string
+	Note          *string
+	Type          constants.TransactionType
+	Payee         *string
+	Account       *string
+	Tags          []string
+	ImportBatchID *string
+	ExternalID    *string
+	Entries       []CreateEntryDTO
+	Items         []CreateItemDTO
 }
 
 type CreateEntryDTO struct {
@@ -87,6 +118,9 @@ type UpdateTransactionDTO struct {
 	Name       *string
 	Note       *string
 	CategoryID *string
+	Payee      *string
+	Account    *string
+	Tags       *[]string
 	Entries    *[]UpdateEntryDTO
 }
 
@@ -95,6 +129,53 @@ type UpdateEntryDTO struct {
 	ReferenceDate string
 }
 
+// A PIX BR Code. Static codes may have no amount and dynamic ones carry a URL
+// where the payment details are, instead of the key
+type PixPayload struct {
+	Key          *string  `json:"key"`
+	URL          *string  `json:"url"`
+	Message      *string  `json:"message"`
+	MerchantName *string  `json:"merchant_name"`
+	MerchantCity *string  `json:"merchant_city"`
+	PostalCode   *string  `json:"postal_code"`
+	Amount       *float64 `json:"amount"`
+	TxID         *string  `json:"txid"`
+	Dynamic      bool     `json:"dynamic"`
+}
+
+// A transaction prefilled from a PIX payment, missing lists what must still
+// be filled before creating it
+type PixDraft struct {
+	Pix                 PixPayload                      `json:"pix"`
+	Transaction         CreateTransactionRequest        `json:"transaction"`
+	MatchedPayee        bool                            `json:"matched_payee"`
+	Missing             []string                        `json:"missing"`
+	CategorySuggestions []categories.CategorySuggestion `json:"category_suggestions,omitempty"`
+}
+
+// A payee already used by the user, with the category it's most used with
+type PayeeUsage struct {
+	Payee      string
+	CategoryID *string
+	Count      int
+}
+
+// A line of a purchase, like a product of an invoice
+type CreateItemDTO struct {
+	Code        *string
+	Description string
+	Quantity    float64
+	Unit        *string
+	UnitPrice   float64
+	Amount      float64
+}
+
+type PersistItemDTO struct {
+	TransactionID string
+	Position      int
+	CreateItemDTO
+}
+
 type PersistEntryDTO struct {
 	TransactionID string
 	Amount        float64
@@ -106,7 +187,8 @@ type PersistEntryDTO struct {
 //    Models that represents database objects
 // ==============================================================================
 
-// View that mixes the entries with the transaction information, riched with some valuable information about the totality of this relationship
+// View that mixes the entries with the transaction information, riched with some valuable information about the totality of this relationship.
+// A search adds its rank and snippet, HTML of the escaped name and description with the matches in <mark>
 type ViewEntry struct {
 	ID                string                    `json:"id"`
 	TransactionID     string                    `json:"transaction_id"`
@@ -115,7 +197,7 @@ type ViewEntry struct {
 	Amount            float64                   `json:"amount"`
 	Period            string                    `json:"period"`
 	UserID            string                    `json:"user_id"`
-	Type              constants.TransactionType `json:"type"`
+	Type              constants.TransactionType `json:"type" db:"category"`
 	TotalAmount       float64                   `json:"total_amount"`
 	Installment       int                       `json:"installment"`
 	TotalInstallments int                       `json:"total_installments"`
@@ -124,6 +206,33 @@ type ViewEntry struct {
 	CategoryID        *string                   `json:"category_id,omitempty"`
 	CategoryName      *string                   `json:"category_name,omitempty"`
 	CategoryColor     *string                   `json:"category_color,omitempty"`
+	Payee             *string                   `json:"payee"`
+	Account           *string                   `json:"account"`
+	Tags              []string                  `json:"tags"`
+	Rank              *float64                  `json:"rank,omitempty"`
+	Snippet           *string                   `json:"snippet,omitempty"`
+}
+
+// Totals over every entry matching a filter, regardless of pagination
+type EntriesAggregate struct {
+	Income      float64                           `json:"income"`
+	Expenses    float64                           `json:"expenses"`
+	Net         float64                           `json:"net"`
+	CountByType map[constants.TransactionType]int `json:"count_by_type"`
+}
+
+// Transaction items table record, items detail a purchase and don't change
+// its entries
+type TransactionItem struct {
+	ID            string  `json:"id"`
+	TransactionID string  `json:"transaction_id"`
+	Position      int     `json:"position"`
+	Code          *string `json:"code"`
+	Description   string  `json:"description"`
+	Quantity      float64 `json:"quantity"`
+	Unit          *string `json:"unit"`
+	UnitPrice     float64 `json:"unit_price"`
+	Amount        float64 `json:"amount"`
 }
 
 // Entries table record
@@ -144,4 +253,7 @@ type Transaction struct {
 	Description *string                   `json:"description"`
 	CreatedAt   time.Time                 `json:"created_at"`
 	CategoryID  *string                   `json:"category_id"`
+	Payee       *string                   `json:"payee"`
+	Account     *string                   `json:"account"`
+	Tags        []string                  `json:"tags"`
 }
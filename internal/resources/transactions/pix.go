@@ -0,0 +1,147 @@
+package transactions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Globally unique identifier of the PIX arrangement in a merchant account
+// information template
+const pixGUI = "br.gov.bcb.pix"
+
+type tlvField struct {
+	ID    string
+	Value string
+}
+
+// parseTLV splits an EMV payload in its fields, each one a two digit ID, a
+// two digit length and the value
+func parseTLV(payload []rune) ([]tlvField, error) {
+	fields := make([]tlvField, 0)
+	for i := 0; i < len(payload); {
+		if i+4 > len(payload) {
+			return nil, ErrInvalidPixPayload
+		}
+
+		id := string(payload[i : i+2])
+		length, err := strconv.Atoi(string(payload[i+2 : i+4]))
+		if err != nil || i+4+length > len(payload) {
+			return nil, ErrInvalidPixPayload
+		}
+
+		fields = append(fields, tlvField{ID: id, Value: string(payload[i+4 : i+4+length])})
+		i += 4 + length
+	}
+	return fields, nil
+}
+
+func tlvValue(fields []tlvField, id string) *string {
+	for _, field := range fields {
+		if field.ID == id && field.Value != "" {
+			value := field.Value
+			return &value
+		}
+	}
+	return nil
+}
+
+// PixCRC16 is the CRC-16/CCITT-FALSE (polynomial 0x1021, initial value
+// 0xFFFF) that closes a BR Code
+func PixCRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for _, b := range []byte(data) {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// ParsePix reads a PIX BR Code, the EMV merchant presented QR payload of a
+// "copia e cola", validating its CRC
+func ParsePix(payload string) (PixPayload, error) {
+	payload = strings.TrimSpace(payload)
+
+	fields, err := parseTLV([]rune(payload))
+	if err != nil {
+		return PixPayload{}, err
+	}
+
+	if len(fields) < 2 || fields[0].ID != "00" || fields[0].Value != "01" {
+		return PixPayload{}, ErrInvalidPixPayload
+	}
+
+	last := fields[len(fields)-1]
+	if last.ID != "63" || len(last.Value) != 4 {
+		return PixPayload{}, ErrInvalidPixPayload
+	}
+
+	if !strings.EqualFold(last.Value, PixCRC16(payload[:len(payload)-4])) {
+		return PixPayload{}, ErrInvalidPixCRC
+	}
+
+	// point of initiation 12 means the QR code is for a single payment
+	initiation := tlvValue(fields, "01")
+	pix := PixPayload{
+		Dynamic:      initiation != nil && *initiation == "12",
+		MerchantName: tlvValue(fields, "59"),
+		MerchantCity: tlvValue(fields, "60"),
+		PostalCode:   tlvValue(fields, "61"),
+	}
+
+	// merchant account information may be in any template from 26 to 51
+	found := false
+	for _, field := range fields {
+		id, _ := strconv.Atoi(field.ID)
+		if id < 26 || id > 51 {
+			continue
+		}
+
+		account, err := parseTLV([]rune(field.Value))
+		if err != nil {
+			return PixPayload{}, err
+		}
+
+		gui := tlvValue(account, "00")
+		if gui == nil || !strings.EqualFold(*gui, pixGUI) {
+			continue
+		}
+
+		pix.Key = tlvValue(account, "01")
+		pix.Message = tlvValue(account, "02")
+		pix.URL = tlvValue(account, "25")
+		found = true
+		break
+	}
+
+	if !found {
+		return PixPayload{}, ErrNotPixPayload
+	}
+
+	if amount := tlvValue(fields, "54"); amount != nil {
+		value, err := strconv.ParseFloat(*amount, 64)
+		if err != nil || value <= 0 {
+			return PixPayload{}, ErrInvalidPixPayload
+		}
+		pix.Amount = &value
+	}
+
+	// *** means the payment has no txid
+	if additional := tlvValue(fields, "62"); additional != nil {
+		additionalFields, err := parseTLV([]rune(*additional))
+		if err != nil {
+			return PixPayload{}, err
+		}
+		if txid := tlvValue(additionalFields, "05"); txid != nil && *txid != "***" {
+			pix.TxID = txid
+		}
+	}
+
+	return pix, nil
+}
@@ -2,22 +2,31 @@ package transactions
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/felipe1496/open-wallet/internal/constants"
 	"github.com/felipe1496/open-wallet/internal/utils"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/oklog/ulid/v2"
 )
 
 type TransactionsRepo interface {
 	CreateEntry(db utils.Executer, payload PersistEntryDTO) (Entry, error)
 	CreateTransaction(db utils.Executer, payload CreateTransactionDTO) (Transaction, error)
+	CreateItem(db utils.Executer, payload PersistItemDTO) (TransactionItem, error)
+	ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]TransactionItem, error)
 	ListViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ViewEntry, error)
+	EachViewEntry(db utils.Executer, filter *utils.QueryOptsBuilder, fn func(entry ViewEntry) error) error
+	SearchViewEntries(db utils.Executer, search string, filter *utils.QueryOptsBuilder) ([]ViewEntry, error)
 	CountViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) (int, error)
+	AggregateViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) (EntriesAggregate, error)
 	DeleteTransactionById(db utils.Executer, id string) error
 	ListTransactions(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Transaction, error)
 	UpdateTransaction(db utils.Executer, id string, payload UpdateTransactionDTO) (Transaction, error)
 	DeleteEntry(db utils.Executer, filter *utils.QueryOptsBuilder) error
+	ListPayees(db utils.Executer, userID string) ([]PayeeUsage, error)
 }
 
 type TransactionsRepoImpl struct {
@@ -50,11 +59,78 @@ func (r *TransactionsRepoImpl) CreateEntry(db utils.Executer, payload PersistEnt
 	return entry, err
 }
 
+func (r *TransactionsRepoImpl) CreateItem(db utils.Executer, payload PersistItemDTO) (TransactionItem, error) {
+	query, args, err := squirrel.Insert("transaction_items").
+		Columns("id", "transaction_id", "position", "code", "description", "quantity", "unit", "unit_price", "amount").
+		Values(ulid.Make().String(), payload.TransactionID, payload.Position, payload.Code, payload.Description, payload.Quantity, payload.Unit, payload.UnitPrice, payload.Amount).
+		Suffix("RETURNING id, transaction_id, position, code, description, quantity, unit, unit_price, amount").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return TransactionItem{}, err
+	}
+
+	var item TransactionItem
+	err = db.QueryRow(query, args...).Scan(
+		&item.ID,
+		&item.TransactionID,
+		&item.Position,
+		&item.Code,
+		&item.Description,
+		&item.Quantity,
+		&item.Unit,
+		&item.UnitPrice,
+		&item.Amount,
+	)
+
+	return item, err
+}
+
+func (r *TransactionsRepoImpl) ListItems(db utils.Executer, filter *utils.QueryOptsBuilder) ([]TransactionItem, error) {
+	query := squirrel.Select("id", "transaction_id", "position", "code", "description", "quantity", "unit", "unit_price", "amount").
+		From("transaction_items").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]TransactionItem, 0)
+	for rows.Next() {
+		var item TransactionItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.TransactionID,
+			&item.Position,
+			&item.Code,
+			&item.Description,
+			&item.Quantity,
+			&item.Unit,
+			&item.UnitPrice,
+			&item.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
 func (r *TransactionsRepoImpl) CreateTransaction(db utils.Executer, payload CreateTransactionDTO) (Transaction, error) {
 	query, args, err := squirrel.Insert("transactions").
-		Columns("id", "user_id", "category", "name", "description", "category_id").
-		Values(ulid.Make().String(), payload.UserID, payload.Type, payload.Name, &payload.Note, &payload.CategoryID).
-		Suffix("RETURNING id, user_id, category, name, description, created_at, category_id").
+		Columns("id", "user_id", "category", "name", "description", "category_id", "payee", "account", "tags", "import_batch_id", "external_id").
+		Values(ulid.Make().String(), payload.UserID, payload.Type, payload.Name, &payload.Note, &payload.CategoryID, payload.Payee, payload.Account, pq.Array(payload.Tags), payload.ImportBatchID, payload.ExternalID).
+		Suffix("RETURNING id, user_id, category, name, description, created_at, category_id, payee, account, tags").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -70,12 +146,15 @@ func (r *TransactionsRepoImpl) CreateTransaction(db utils.Executer, payload Crea
 		&transaction.Description,
 		&transaction.CreatedAt,
 		&transaction.CategoryID,
+		&transaction.Payee,
+		&transaction.Account,
+		pq.Array(&transaction.Tags),
 	)
 	return transaction, err
 }
 
 func (r *TransactionsRepoImpl) ListViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) ([]ViewEntry, error) {
-	query := squirrel.Select("id", "transaction_id", "name", "description", "amount", "period", "user_id", "category", "total_amount", "installment", "total_installments", "created_at", "reference_date::text", "category_id", "category_name", "category_color").
+	query := squirrel.Select("id", "transaction_id", "name", "description", "amount", "period", "user_id", "category", "total_amount", "installment", "total_installments", "created_at", "reference_date::text", "category_id", "category_name", "category_color", "payee", "account", "tags").
 		From("v_entries").
 		PlaceholderFormat(squirrel.Dollar)
 
@@ -95,6 +174,136 @@ func (r *TransactionsRepoImpl) ListViewEntries(db utils.Executer, filter *utils.
 
 	defer rows.Close()
 
+	var entries []ViewEntry = []ViewEntry{}
+	for rows.Next() {
+		entry, err := scanViewEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// EachViewEntry calls fn for every entry matching the filter as rows are read,
+// without holding them all in memory. An error from fn stops the iteration and
+// is returned as is
+func (r *TransactionsRepoImpl) EachViewEntry(db utils.Executer, filter *utils.QueryOptsBuilder, fn func(entry ViewEntry) error) error {
+	query := squirrel.Select("id", "transaction_id", "name", "description", "amount", "period", "user_id", "category", "total_amount", "installment", "total_installments", "created_at", "reference_date::text", "category_id", "category_name", "category_color", "payee", "account", "tags").
+		From("v_entries").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanViewEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanViewEntry(rows rowScanner) (ViewEntry, error) {
+	var entry ViewEntry
+	err := rows.Scan(
+		&entry.ID,
+		&entry.TransactionID,
+		&entry.Name,
+		&entry.Description,
+		&entry.Amount,
+		&entry.Period,
+		&entry.UserID,
+		&entry.Type,
+		&entry.TotalAmount,
+		&entry.Installment,
+		&entry.TotalInstallments,
+		&entry.CreatedAt,
+		&entry.ReferenceDate,
+		&entry.CategoryID,
+		&entry.CategoryName,
+		&entry.CategoryColor,
+		&entry.Payee,
+		&entry.Account,
+		pq.Array(&entry.Tags),
+	)
+
+	return entry, err
+}
+
+// searchDocument must match the expression of transactions_search_idx
+const searchDocument = "to_tsvector('portuguese_unaccent', coalesce(name, '') || ' ' || coalesce(description, ''))"
+
+const searchQuery = "websearch_to_tsquery('portuguese_unaccent', ?)"
+
+// SearchCondition matches entries whose transaction name or description
+// matches a full-text search. It filters by transaction_id so the condition
+// reaches the transactions index through the window functions of v_entries
+func SearchCondition(search string) squirrel.Sqlizer {
+	return squirrel.Expr("transaction_id IN (SELECT id FROM transactions WHERE "+searchDocument+" @@ "+searchQuery+")", search)
+}
+
+// htmlEscapes are replaced in the text of a snippet, & first so the
+// entities of the others aren't escaped again
+var htmlEscapes = [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"'", "&#39;"}}
+
+// SearchSnippet highlights the matches of a search in the transaction name and
+// description with <mark>. The text is HTML-escaped before ts_headline so the
+// marks are the only markup of the snippet
+func SearchSnippet(search string) squirrel.Sqlizer {
+	text := "name || coalesce(' - ' || description, '')"
+	for _, escape := range htmlEscapes {
+		text = "replace(" + text + ", '" + strings.ReplaceAll(escape[0], "'", "''") + "', '" + escape[1] + "')"
+	}
+	return squirrel.Expr("ts_headline('portuguese_unaccent', "+text+", "+searchQuery+", 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')", search)
+}
+
+// SearchViewEntries lists entries ranked by relevance with a highlighted
+// snippet, the filter must already hold the SearchCondition of the same search
+func (r *TransactionsRepoImpl) SearchViewEntries(db utils.Executer, search string, filter *utils.QueryOptsBuilder) ([]ViewEntry, error) {
+	query := squirrel.Select("id", "transaction_id", "name", "description", "amount", "period", "user_id", "category", "total_amount", "installment", "total_installments", "created_at", "reference_date::text", "category_id", "category_name", "category_color", "payee", "account", "tags").
+		Column(squirrel.Expr("ts_rank("+searchDocument+", "+searchQuery+") AS rank", search)).
+		Column(SearchSnippet(search)).
+		From("v_entries").
+		OrderBy("rank DESC").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
 	var entries []ViewEntry = []ViewEntry{}
 	for rows.Next() {
 		var entry ViewEntry
@@ -115,6 +324,11 @@ func (r *TransactionsRepoImpl) ListViewEntries(db utils.Executer, filter *utils.
 			&entry.CategoryID,
 			&entry.CategoryName,
 			&entry.CategoryColor,
+			&entry.Payee,
+			&entry.Account,
+			pq.Array(&entry.Tags),
+			&entry.Rank,
+			&entry.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -150,6 +364,50 @@ func (r *TransactionsRepoImpl) CountViewEntries(db utils.Executer, filter *utils
 	return count, nil
 }
 
+func (r *TransactionsRepoImpl) AggregateViewEntries(db utils.Executer, filter *utils.QueryOptsBuilder) (EntriesAggregate, error) {
+	query := squirrel.
+		Select(
+			"COALESCE(SUM(amount) FILTER (WHERE category = 'income'), 0)",
+			"COALESCE(SUM(amount) FILTER (WHERE category <> 'income'), 0)",
+			"COALESCE(SUM(amount), 0)",
+			"COUNT(*) FILTER (WHERE category = 'simple_expense')",
+			"COUNT(*) FILTER (WHERE category = 'income')",
+			"COUNT(*) FILTER (WHERE category = 'installment')",
+		).
+		From("v_entries").
+		PlaceholderFormat(squirrel.Dollar)
+
+	query = utils.QueryOptsToSquirrel(query, filter)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return EntriesAggregate{}, err
+	}
+
+	var aggregate EntriesAggregate
+	var simpleExpenses, incomes, installments int
+	err = db.QueryRow(sql, args...).Scan(
+		&aggregate.Income,
+		&aggregate.Expenses,
+		&aggregate.Net,
+		&simpleExpenses,
+		&incomes,
+		&installments,
+	)
+
+	if err != nil {
+		return EntriesAggregate{}, err
+	}
+
+	aggregate.CountByType = map[constants.TransactionType]int{
+		constants.SimpleExpense: simpleExpenses,
+		constants.Income:        incomes,
+		constants.Installment:   installments,
+	}
+
+	return aggregate, nil
+}
+
 func (r *TransactionsRepoImpl) DeleteTransactionById(db utils.Executer, id string) error {
 	sql, args, err := squirrel.Delete("transactions").
 		Where(squirrel.Eq{"id": id}).
@@ -165,7 +423,7 @@ func (r *TransactionsRepoImpl) DeleteTransactionById(db utils.Executer, id strin
 	return err
 }
 func (r *TransactionsRepoImpl) ListTransactions(db utils.Executer, filter *utils.QueryOptsBuilder) ([]Transaction, error) {
-	query := squirrel.Select("id", "user_id", "category", "name", "description", "created_at").
+	query := squirrel.Select("id", "user_id", "category", "name", "description", "created_at", "category_id", "payee", "account", "tags").
 		From("transactions").
 		PlaceholderFormat(squirrel.Dollar)
 
@@ -194,6 +452,10 @@ func (r *TransactionsRepoImpl) ListTransactions(db utils.Executer, filter *utils
 			&transaction.Name,
 			&transaction.Description,
 			&transaction.CreatedAt,
+			&transaction.CategoryID,
+			&transaction.Payee,
+			&transaction.Account,
+			pq.Array(&transaction.Tags),
 		); err != nil {
 			return nil, err
 		}
@@ -209,7 +471,8 @@ func (r *TransactionsRepoImpl) UpdateTransaction(db utils.Executer, id string, p
 	}
 
 	query := squirrel.Update("transactions").Where(squirrel.Eq{"id": id}).
-		Suffix("RETURNING id, user_id, category, name, description, created_at, category_id").
+		Set("updated_at", squirrel.Expr("now()")).
+		Suffix("RETURNING id, user_id, category, name, description, created_at, category_id, payee, account, tags").
 		PlaceholderFormat(squirrel.Dollar)
 
 	for _, field := range payload.Update {
@@ -220,6 +483,16 @@ func (r *TransactionsRepoImpl) UpdateTransaction(db utils.Executer, id string, p
 			query = query.Set("description", payload.Note)
 		case "category_id":
 			query = query.Set("category_id", payload.CategoryID)
+		case "payee":
+			query = query.Set("payee", payload.Payee)
+		case "account":
+			query = query.Set("account", payload.Account)
+		case "tags":
+			tags := make([]string, 0)
+			if payload.Tags != nil {
+				tags = *payload.Tags
+			}
+			query = query.Set("tags", pq.Array(tags))
 		}
 	}
 
@@ -237,6 +510,9 @@ func (r *TransactionsRepoImpl) UpdateTransaction(db utils.Executer, id string, p
 		&transaction.Description,
 		&transaction.CreatedAt,
 		&transaction.CategoryID,
+		&transaction.Payee,
+		&transaction.Account,
+		pq.Array(&transaction.Tags),
 	)
 
 	if err != nil {
@@ -263,3 +539,37 @@ func (r *TransactionsRepoImpl) DeleteEntry(db utils.Executer, filter *utils.Quer
 
 	return err
 }
+
+// ListPayees lists the payees of the user with how many transactions use
+// them and their most common category
+func (r *TransactionsRepoImpl) ListPayees(db utils.Executer, userID string) ([]PayeeUsage, error) {
+	sql, args, err := squirrel.Select("payee", "mode() WITHIN GROUP (ORDER BY category_id)", "COUNT(*)").
+		From("transactions").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.NotEq{"payee": nil}).
+		GroupBy("payee").
+		OrderBy("COUNT(*) DESC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payees := make([]PayeeUsage, 0)
+	for rows.Next() {
+		var payee PayeeUsage
+		if err := rows.Scan(&payee.Payee, &payee.CategoryID, &payee.Count); err != nil {
+			return nil, err
+		}
+		payees = append(payees, payee)
+	}
+
+	return payees, nil
+}
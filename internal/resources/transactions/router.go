@@ -25,14 +25,24 @@ func Router(router *gin.Engine) {
 			middlewares.RequireAuthMiddleware(jwtService),
 			middlewares.QueryOptsMiddleware(),
 			handler.ListEntries)
+		transactionsGroup.GET("/entries/export",
+			middlewares.RequireAuthMiddleware(jwtService),
+			middlewares.QueryOptsMiddleware(),
+			handler.ExportEntries)
 		transactionsGroup.DELETE("/:transaction_id",
 			middlewares.RequireAuthMiddleware(jwtService),
 			handler.DeleteTransaction)
 		transactionsGroup.POST("",
 			middlewares.RequireAuthMiddleware(jwtService),
 			handler.CreateTransaction)
+		transactionsGroup.POST("/from-pix",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.DraftFromPix)
 		transactionsGroup.PATCH("/:transaction_id",
 			middlewares.RequireAuthMiddleware(jwtService),
 			handler.UpdateTransaction)
+		transactionsGroup.GET("/:transaction_id/items",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ListItems)
 	}
 }
@@ -3,32 +3,43 @@ package transactions
 import (
 	"database/sql"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/felipe1496/open-wallet/internal/constants"
 	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
 	"github.com/felipe1496/open-wallet/internal/utils"
 )
 
 type TransactionsUseCase interface {
 	ListViewEntries(filter *utils.QueryOptsBuilder) ([]ViewEntry, error)
+	EachViewEntry(filter *utils.QueryOptsBuilder, fn func(entry ViewEntry) error) error
+	SearchViewEntries(search string, filter *utils.QueryOptsBuilder) ([]ViewEntry, error)
 	CountViewEntries(filter *utils.QueryOptsBuilder) (int, error)
+	AggregateViewEntries(filter *utils.QueryOptsBuilder) (EntriesAggregate, error)
 	DeleteTransactionById(id string) error
 	CreateTransaction(payload CreateTransactionDTO) (Transaction, error)
 	UpdateTransaction(transactionID string, userID string, payload UpdateTransactionDTO) (Transaction, error)
+	SuggestCategories(userID string, name string) ([]categories.CategorySuggestion, error)
+	ListItems(transactionID string, userID string) ([]TransactionItem, error)
+	DraftFromPix(userID string, payload string) (PixDraft, error)
 }
 
 type TransactionsUseCaseImpl struct {
 	repo              TransactionsRepo
 	categoriesUseCase categories.CategoriesUseCase
+	rulesUseCase      rules.RulesUseCase
 	db                *sql.DB
 }
 
-func NewTransactionsUseCase(repo TransactionsRepo, categoriesUseCase categories.CategoriesUseCase, db *sql.DB) TransactionsUseCase {
+func NewTransactionsUseCase(repo TransactionsRepo, categoriesUseCase categories.CategoriesUseCase, rulesUseCase rules.RulesUseCase, db *sql.DB) TransactionsUseCase {
 	return &TransactionsUseCaseImpl{
 		repo,
 		categoriesUseCase,
+		rulesUseCase,
 		db,
 	}
 }
@@ -43,6 +54,36 @@ func (uc *TransactionsUseCaseImpl) ListViewEntries(filter *utils.QueryOptsBuilde
 	return entries, nil
 }
 
+// EachViewEntry streams the entries matching the filter to fn. Errors returned
+// by fn are passed through so the caller can tell them from a failed query
+func (uc *TransactionsUseCaseImpl) EachViewEntry(filter *utils.QueryOptsBuilder, fn func(entry ViewEntry) error) error {
+	var fnErr error
+	err := uc.repo.EachViewEntry(uc.db, filter, func(entry ViewEntry) error {
+		fnErr = fn(entry)
+		return fnErr
+	})
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	if err != nil {
+		return ErrFailedToFetchEntries
+	}
+
+	return nil
+}
+
+func (uc *TransactionsUseCaseImpl) SearchViewEntries(search string, filter *utils.QueryOptsBuilder) ([]ViewEntry, error) {
+	entries, err := uc.repo.SearchViewEntries(uc.db, search, filter)
+
+	if err != nil {
+		return []ViewEntry{}, ErrToSearchEntries
+	}
+
+	return entries, nil
+}
+
 func (uc *TransactionsUseCaseImpl) CountViewEntries(filter *utils.QueryOptsBuilder) (int, error) {
 	count, err := uc.repo.CountViewEntries(uc.db, filter)
 
@@ -53,6 +94,16 @@ func (uc *TransactionsUseCaseImpl) CountViewEntries(filter *utils.QueryOptsBuild
 	return count, nil
 }
 
+func (uc *TransactionsUseCaseImpl) AggregateViewEntries(filter *utils.QueryOptsBuilder) (EntriesAggregate, error) {
+	aggregate, err := uc.repo.AggregateViewEntries(uc.db, filter)
+
+	if err != nil {
+		return EntriesAggregate{}, ErrToAggregateEntries
+	}
+
+	return aggregate, nil
+}
+
 func (uc *TransactionsUseCaseImpl) DeleteTransactionById(id string) error {
 	transactionExists, err := uc.repo.ListTransactions(uc.db, utils.QueryOpts().And("id", "eq", id))
 
@@ -138,20 +189,55 @@ func validateTransaction(entries []validateTransactionPropsEntry, transactionTyp
 	return nil
 }
 
+// ValidateCreateTransaction applies the same rules of CreateTransaction
+// without persisting anything
+func ValidateCreateTransaction(payload CreateTransactionDTO) error {
+	entries := make([]validateTransactionPropsEntry, 0)
+	if payload.Entries != nil {
+		for _, entry := range payload.Entries {
+			entries = append(entries, validateTransactionPropsEntry{
+				Amount:        entry.Amount,
+				ReferenceDate: entry.ReferenceDate,
+			})
+		}
+	}
+	return validateTransaction(entries, payload.Type)
+}
+
+// SignedAmount makes expenses and installments negative and incomes positive,
+// the way entries are stored
+func SignedAmount(transactionType constants.TransactionType, amount float64) float64 {
+	if (transactionType == constants.SimpleExpense || transactionType == constants.Installment) && amount > 0 {
+		return amount * -1
+	} else if transactionType == constants.Income && amount < 0 {
+		return amount * -1
+	}
+	return amount
+}
+
 func (uc *TransactionsUseCaseImpl) CreateTransaction(payload CreateTransactionDTO) (Transaction, error) {
 
-	err := validateTransaction(func() []validateTransactionPropsEntry {
-		entries := make([]validateTransactionPropsEntry, 0)
-		if payload.Entries != nil {
-			for _, entry := range payload.Entries {
-				entries = append(entries, validateTransactionPropsEntry{
-					Amount:        entry.Amount,
-					ReferenceDate: entry.ReferenceDate,
-				})
-			}
-		}
-		return entries
-	}(), payload.Type)
+	err := ValidateCreateTransaction(payload)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	total := 0.0
+	for _, entry := range payload.Entries {
+		total += entry.Amount
+	}
+
+	// rules may rename, categorize, set the payee or tag what is being created
+	subject, err := uc.rulesUseCase.Apply(payload.UserID, rules.Subject{
+		Name:       payload.Name,
+		Note:       payload.Note,
+		Type:       payload.Type,
+		Amount:     math.Abs(total),
+		Account:    payload.Account,
+		CategoryID: payload.CategoryID,
+		Payee:      payload.Payee,
+		Tags:       payload.Tags,
+	})
 	if err != nil {
 		return Transaction{}, err
 	}
@@ -163,11 +249,16 @@ func (uc *TransactionsUseCaseImpl) CreateTransaction(payload CreateTransactionDT
 	}
 
 	transaction, err := uc.repo.CreateTransaction(tx, CreateTransactionDTO{
-		UserID:     payload.UserID,
-		Type:       payload.Type,
-		Name:       payload.Name,
-		Note:       payload.Note,
-		CategoryID: payload.CategoryID,
+		UserID:        payload.UserID,
+		Type:          payload.Type,
+		Name:          subject.Name,
+		Note:          payload.Note,
+		CategoryID:    subject.CategoryID,
+		Payee:         subject.Payee,
+		Account:       payload.Account,
+		Tags:          subject.Tags,
+		ImportBatchID: payload.ImportBatchID,
+		ExternalID:    payload.ExternalID,
 	})
 
 	if err != nil {
@@ -176,14 +267,9 @@ func (uc *TransactionsUseCaseImpl) CreateTransaction(payload CreateTransactionDT
 	}
 
 	for _, entry := range payload.Entries {
-		if (payload.Type == constants.SimpleExpense || payload.Type == constants.Installment) && entry.Amount > 0 {
-			entry.Amount = entry.Amount * -1
-		} else if payload.Type == constants.Income && entry.Amount < 0 {
-			entry.Amount = entry.Amount * -1
-		}
 		_, err = uc.repo.CreateEntry(tx, PersistEntryDTO{
 			TransactionID: transaction.ID,
-			Amount:        entry.Amount,
+			Amount:        SignedAmount(payload.Type, entry.Amount),
 			ReferenceDate: entry.ReferenceDate,
 		})
 
@@ -193,6 +279,19 @@ func (uc *TransactionsUseCaseImpl) CreateTransaction(payload CreateTransactionDT
 		}
 	}
 
+	for i, item := range payload.Items {
+		_, err = uc.repo.CreateItem(tx, PersistItemDTO{
+			TransactionID: transaction.ID,
+			Position:      i + 1,
+			CreateItemDTO: item,
+		})
+
+		if err != nil {
+			tx.Rollback()
+			return Transaction{}, utils.NewHTTPError(http.StatusInternalServerError, "failed to create item")
+		}
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -243,12 +342,15 @@ func (uc *TransactionsUseCaseImpl) UpdateTransaction(transactionID string, userI
 		}
 	}
 
-	if utils.ContainsSome(payload.Update, []string{"name", "note", "category_id"}) {
+	if utils.ContainsSome(payload.Update, []string{"name", "note", "category_id", "payee", "account", "tags"}) {
 		_, err = uc.repo.UpdateTransaction(tx, transactionID, UpdateTransactionDTO{
 			Update:     payload.Update,
 			Name:       payload.Name,
 			Note:       payload.Note,
 			CategoryID: payload.CategoryID,
+			Payee:      payload.Payee,
+			Account:    payload.Account,
+			Tags:       payload.Tags,
 		})
 		if err != nil {
 			return Transaction{}, utils.NewHTTPError(http.StatusInternalServerError, "failed to update transaction")
@@ -300,3 +402,112 @@ func (uc *TransactionsUseCaseImpl) UpdateTransaction(transactionID string, userI
 
 	return transactions[0], nil
 }
+
+func (uc *TransactionsUseCaseImpl) SuggestCategories(userID string, name string) ([]categories.CategorySuggestion, error) {
+	return uc.categoriesUseCase.Suggest(userID, name, 3)
+}
+
+func (uc *TransactionsUseCaseImpl) ListItems(transactionID string, userID string) ([]TransactionItem, error) {
+	transactions, err := uc.repo.ListTransactions(uc.db, utils.QueryOpts().
+		And("id", "eq", transactionID).
+		And("user_id", "eq", userID))
+	if err != nil {
+		return nil, AnErrorOccuredWhileFetchingTransactions
+	}
+
+	if len(transactions) == 0 {
+		return nil, TransactionNotFound
+	}
+
+	items, err := uc.repo.ListItems(uc.db, utils.QueryOpts().
+		And("transaction_id", "eq", transactionID).
+		OrderBy("position", "asc"))
+	if err != nil {
+		return nil, ErrFailedToListItems
+	}
+
+	return items, nil
+}
+
+// MatchPayee finds the payee of the user a PIX merchant name refers to,
+// ignoring case and accents. Merchant names are cut at 25 characters, so a
+// payee starting with the name matches too. Payees must come most used first
+func MatchPayee(payees []PayeeUsage, merchantName string) *PayeeUsage {
+	name := categories.NormalizeName(merchantName)
+	if name == "" {
+		return nil
+	}
+
+	for _, payee := range payees {
+		normalized := categories.NormalizeName(payee.Payee)
+		if normalized == name || (len([]rune(merchantName)) == 25 && strings.HasPrefix(normalized, name)) {
+			return &payee
+		}
+	}
+	return nil
+}
+
+// BuildPixDraft maps a PIX payment onto an expense paid today, named after
+// the payee it matched or the merchant
+func BuildPixDraft(pix PixPayload, match *PayeeUsage, today string) PixDraft {
+	draft := PixDraft{
+		Pix: pix,
+		Transaction: CreateTransactionRequest{
+			Type:    constants.SimpleExpense,
+			Note:    pix.Message,
+			Entries: make([]CreateEntryRequest, 0),
+		},
+		Missing: make([]string, 0),
+	}
+
+	switch {
+	case match != nil:
+		payee := match.Payee
+		draft.Transaction.Name = payee
+		draft.Transaction.Payee = &payee
+		draft.Transaction.CategoryID = match.CategoryID
+		draft.MatchedPayee = true
+	case pix.MerchantName != nil:
+		payee := *pix.MerchantName
+		draft.Transaction.Name = payee
+		draft.Transaction.Payee = &payee
+	default:
+		draft.Missing = append(draft.Missing, "name")
+	}
+
+	if pix.Amount != nil {
+		draft.Transaction.Entries = append(draft.Transaction.Entries, CreateEntryRequest{
+			Amount:        -*pix.Amount,
+			ReferenceDate: today,
+		})
+	} else {
+		draft.Missing = append(draft.Missing, "amount")
+	}
+
+	return draft
+}
+
+func (uc *TransactionsUseCaseImpl) DraftFromPix(userID string, payload string) (PixDraft, error) {
+	pix, err := ParsePix(payload)
+	if err != nil {
+		return PixDraft{}, err
+	}
+
+	var match *PayeeUsage
+	if pix.MerchantName != nil {
+		payees, err := uc.repo.ListPayees(uc.db, userID)
+		if err != nil {
+			return PixDraft{}, ErrFailedToListPayees
+		}
+		match = MatchPayee(payees, *pix.MerchantName)
+	}
+
+	draft := BuildPixDraft(pix, match, time.Now().Format("2006-01-02"))
+
+	// a failed suggestion only means the draft gets none
+	if draft.Transaction.CategoryID == nil && draft.Transaction.Name != "" {
+		draft.CategorySuggestions, _ = uc.categoriesUseCase.Suggest(userID, draft.Transaction.Name, 3)
+	}
+
+	return draft, nil
+}
@@ -0,0 +1,274 @@
+package userdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// ArchiveVersion is written in every archive, restoring a newer one is refused
+const ArchiveVersion = 1
+
+const (
+	FormatJSON = "json"
+	FormatZip  = "zip"
+)
+
+// name of the archive inside a zip
+const archiveFileName = "open-wallet.json"
+
+// EncodeArchive writes an archive as indented JSON, alone or zipped
+func EncodeArchive(archive Archive, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if format != FormatZip {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create(archiveFileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeArchive reads an archive written by EncodeArchive, zipped or not
+func DecodeArchive(data []byte) (Archive, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return Archive{}, ErrInvalidArchive
+		}
+
+		file, err := zr.Open(archiveFileName)
+		if err != nil {
+			return Archive{}, ErrInvalidArchive
+		}
+		defer file.Close()
+
+		// the zip is only as large as the upload, its content may not be
+		data, err = io.ReadAll(io.LimitReader(file, maxArchiveSize+1))
+		if err != nil || len(data) > maxArchiveSize {
+			return Archive{}, ErrInvalidArchive
+		}
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return Archive{}, ErrInvalidArchive
+	}
+
+	if archive.Version < 1 {
+		return Archive{}, ErrInvalidArchive
+	}
+
+	if archive.Version > ArchiveVersion {
+		return Archive{}, ErrUnsupportedArchiveVersion
+	}
+
+	return archive, nil
+}
+
+// remapper gives each id of an archive a new one, ids must be unique in their
+// table and references can only point to ids of the archive
+type remapper struct {
+	newID func() string
+	ids   map[string]map[string]string
+}
+
+func (r *remapper) assign(table string, id string) (string, error) {
+	if r.ids[table] == nil {
+		r.ids[table] = map[string]string{}
+	}
+	if _, ok := r.ids[table][id]; ok || id == "" {
+		return "", ErrInvalidArchive
+	}
+
+	r.ids[table][id] = r.newID()
+	return r.ids[table][id], nil
+}
+
+func (r *remapper) reference(table string, id string) (string, error) {
+	mapped, ok := r.ids[table][id]
+	if !ok {
+		return "", ErrArchiveBrokenReference
+	}
+	return mapped, nil
+}
+
+func (r *remapper) optionalReference(table string, id *string) (*string, error) {
+	if id == nil {
+		return nil, nil
+	}
+	mapped, err := r.reference(table, *id)
+	return &mapped, err
+}
+
+// RemapArchive gives every record of an archive a new id and updates the
+// references between them, so it can be restored next to any other data
+func RemapArchive(archive Archive, newID func() string) (Archive, error) {
+	r := &remapper{newID: newID, ids: map[string]map[string]string{}}
+	var err error
+
+	categories := make([]ArchiveCategory, len(archive.Categories))
+	for i, category := range archive.Categories {
+		if category.ID, err = r.assign("categories", category.ID); err != nil {
+			return Archive{}, err
+		}
+		categories[i] = category
+	}
+	archive.Categories = categories
+
+	transactions := make([]ArchiveTransaction, len(archive.Transactions))
+	for i, transaction := range archive.Transactions {
+		if transaction.ID, err = r.assign("transactions", transaction.ID); err != nil {
+			return Archive{}, err
+		}
+		if transaction.CategoryID, err = r.optionalReference("categories", transaction.CategoryID); err != nil {
+			return Archive{}, err
+		}
+
+		entries := make([]ArchiveEntry, len(transaction.Entries))
+		for j, entry := range transaction.Entries {
+			if entry.ID, err = r.assign("entries", entry.ID); err != nil {
+				return Archive{}, err
+			}
+			entries[j] = entry
+		}
+		transaction.Entries = entries
+
+		items := make([]ArchiveItem, len(transaction.Items))
+		for j, item := range transaction.Items {
+			if item.ID, err = r.assign("transaction_items", item.ID); err != nil {
+				return Archive{}, err
+			}
+			items[j] = item
+		}
+		transaction.Items = items
+
+		transactions[i] = transaction
+	}
+	archive.Transactions = transactions
+
+	budgets := make([]ArchiveBudget, len(archive.Budgets))
+	for i, budget := range archive.Budgets {
+		if budget.ID, err = r.assign("budgets", budget.ID); err != nil {
+			return Archive{}, err
+		}
+		if budget.CategoryID, err = r.reference("categories", budget.CategoryID); err != nil {
+			return Archive{}, err
+		}
+		budgets[i] = budget
+	}
+	archive.Budgets = budgets
+
+	movements := make([]ArchiveMovement, len(archive.EnvelopeMovements))
+	for i, movement := range archive.EnvelopeMovements {
+		if movement.ID, err = r.assign("envelope_movements", movement.ID); err != nil {
+			return Archive{}, err
+		}
+		if movement.FromCategoryID, err = r.optionalReference("categories", movement.FromCategoryID); err != nil {
+			return Archive{}, err
+		}
+		if movement.ToCategoryID, err = r.optionalReference("categories", movement.ToCategoryID); err != nil {
+			return Archive{}, err
+		}
+		movements[i] = movement
+	}
+	archive.EnvelopeMovements = movements
+
+	recurring := make([]ArchiveRecurring, len(archive.RecurringTransactions))
+	for i, item := range archive.RecurringTransactions {
+		if item.ID, err = r.assign("recurring_transactions", item.ID); err != nil {
+			return Archive{}, err
+		}
+		if item.CategoryID, err = r.optionalReference("categories", item.CategoryID); err != nil {
+			return Archive{}, err
+		}
+		recurring[i] = item
+	}
+	archive.RecurringTransactions = recurring
+
+	items := make([]ArchiveNetWorthItem, len(archive.NetWorthItems))
+	for i, item := range archive.NetWorthItems {
+		if item.ID, err = r.assign("net_worth_items", item.ID); err != nil {
+			return Archive{}, err
+		}
+
+		valuations := make([]ArchiveValuation, len(item.Valuations))
+		for j, valuation := range item.Valuations {
+			if valuation.ID, err = r.assign("net_worth_valuations", valuation.ID); err != nil {
+				return Archive{}, err
+			}
+			valuations[j] = valuation
+		}
+		item.Valuations = valuations
+
+		items[i] = item
+	}
+	archive.NetWorthItems = items
+
+	rules := make([]ArchiveRule, len(archive.Rules))
+	for i, rule := range archive.Rules {
+		if rule.ID, err = r.assign("rules", rule.ID); err != nil {
+			return Archive{}, err
+		}
+		if rule.Actions, err = remapRuleActions(r, rule.Actions); err != nil {
+			return Archive{}, err
+		}
+		rules[i] = rule
+	}
+	archive.Rules = rules
+
+	profiles := make([]ArchiveImportProfile, len(archive.ImportProfiles))
+	for i, profile := range archive.ImportProfiles {
+		if profile.ID, err = r.assign("import_profiles", profile.ID); err != nil {
+			return Archive{}, err
+		}
+		profiles[i] = profile
+	}
+	archive.ImportProfiles = profiles
+
+	return archive, nil
+}
+
+// remapRuleActions replaces the category a rule sets, leaving every other
+// action as it was stored
+func remapRuleActions(r *remapper, actions json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(actions, &fields); err != nil || fields == nil {
+		return nil, ErrInvalidArchive
+	}
+
+	raw, ok := fields["set_category_id"]
+	if !ok || string(raw) == "null" {
+		return actions, nil
+	}
+
+	var categoryID string
+	if err := json.Unmarshal(raw, &categoryID); err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	mapped, err := r.reference("categories", categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["set_category_id"], _ = json.Marshal(mapped)
+	return json.Marshal(fields)
+}
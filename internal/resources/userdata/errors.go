@@ -0,0 +1,23 @@
+package userdata
+
+import (
+	"net/http"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+)
+
+var (
+	ErrInvalidExportFormat       = utils.NewHTTPError(http.StatusBadRequest, "format must be json or zip")
+	ErrInvalidArchive            = utils.NewHTTPError(http.StatusBadRequest, "file is not a valid archive")
+	ErrUnsupportedArchiveVersion = utils.NewHTTPError(http.StatusBadRequest, "archive was made by a newer version and can't be restored")
+	ErrArchiveBrokenReference    = utils.NewHTTPError(http.StatusBadRequest, "archive references a record it doesn't have")
+	ErrAccountNotEmpty           = utils.NewHTTPError(http.StatusConflict, "an archive can only be restored into an account without data")
+	ErrFailedToExport            = utils.NewHTTPError(http.StatusInternalServerError, "failed to export account data")
+	ErrFailedToCheckAccount      = utils.NewHTTPError(http.StatusInternalServerError, "failed to check account data")
+	ErrConfirmationMismatch      = utils.NewHTTPError(http.StatusBadRequest, "confirm_username must be the username of the account")
+	ErrDeletionNotScheduled      = utils.NewHTTPError(http.StatusNotFound, "account deletion is not scheduled")
+	ErrFailedToScheduleDeletion  = utils.NewHTTPError(http.StatusInternalServerError, "failed to schedule account deletion")
+	ErrFailedToGetDeletion       = utils.NewHTTPError(http.StatusInternalServerError, "failed to get account deletion")
+	ErrFailedToCancelDeletion    = utils.NewHTTPError(http.StatusInternalServerError, "failed to cancel account deletion")
+	ErrFailedToRestore           = utils.NewHTTPError(http.StatusUnprocessableEntity, "archive has records that can't be restored")
+)
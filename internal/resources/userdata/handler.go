@@ -0,0 +1,230 @@
+package userdata
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// largest archive accepted by a restore, zipped or not
+const maxArchiveSize = 50 << 20
+
+type API struct {
+	userDataUseCase UserDataUseCase
+}
+
+func NewHandler(db *sql.DB) *API {
+	return &API{
+		userDataUseCase: NewUserDataUseCase(NewUserDataRepo(db), db),
+	}
+}
+
+// @Summary Export account data
+// @Description Download every record of the user as a versioned archive: profile, categories, transactions with their entries and items, budgets, envelope movements, recurring transactions, net worth, rules, import profiles and journal settings. Records keep their ids so references between them can be followed. There are no attachments to include
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "Archive format, json or zip with the json inside" default(json)
+// @Success 200 {object} Archive "Account archive"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /users/me/export [get]
+func (api *API) ExportArchive(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	format := ctx.DefaultQuery("format", FormatJSON)
+
+	if format != FormatJSON && format != FormatZip {
+		ctx.JSON(ErrInvalidExportFormat.StatusCode, ErrInvalidExportFormat)
+		return
+	}
+
+	archive, err := api.userDataUseCase.Export(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	data, err := EncodeArchive(archive, format)
+
+	if err != nil {
+		ctx.JSON(ErrFailedToExport.StatusCode, ErrFailedToExport)
+		return
+	}
+
+	contentType := "application/json"
+	if format == FormatZip {
+		contentType = "application/zip"
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"open-wallet-%s.%s\"", archive.ExportedAt.Format(time.DateOnly), format))
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// @Summary Restore account data
+// @Description Restore an archive made by the export into the account, which must not have any data yet. Every record gets a new id and the references between them follow. The name, email and username of the account are kept
+// @Tags users
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Archive, json or zip, at most 50MB"
+// @Success 201 {object} ImportArchiveResponse "Records restored"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 409 {object} utils.HTTPError "Account already has data"
+// @Failure 413 {object} utils.HTTPError "File too large"
+// @Failure 422 {object} utils.HTTPError "Archive has invalid records"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /users/me/import [post]
+func (api *API) ImportArchive(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, "file is required")
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	if header.Size > maxArchiveSize {
+		apiErr := utils.NewHTTPError(http.StatusRequestEntityTooLarge, "file must have at most 50MB")
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, "failed to read file")
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, "failed to read file")
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	archive, err := DecodeArchive(data)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	summary, err := api.userDataUseCase.Import(userID, archive)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ImportArchiveResponse{
+		Data: ImportArchiveResponseData{
+			Restored: summary,
+		},
+	})
+}
+
+// @Summary Delete account
+// @Description Schedule the erasure of the account and all its data after a 30 days grace period, in which it can still be cancelled. The username must be typed again as confirmation. Asking again keeps the first schedule. Once the period ends every record is hard deleted and only an anonymous erasure record is kept
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body RequestDeletionRequest true "Confirmation payload"
+// @Success 202 {object} DeletionResponse "Deletion scheduled"
+// @Failure 400 {object} utils.HTTPError "Bad request"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /users/me [delete]
+func (api *API) RequestDeletion(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	var body RequestDeletionRequest
+
+	err := ctx.ShouldBindJSON(&body)
+
+	if err != nil {
+		apiErr := utils.NewHTTPError(http.StatusBadRequest, err.Error())
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	deletion, err := api.userDataUseCase.RequestDeletion(userID, body.ConfirmUsername)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, DeletionResponse{
+		Data: DeletionResponseData{
+			Deletion: deletion,
+		},
+	})
+}
+
+// @Summary Get account deletion
+// @Description Get when the deletion of the account was asked and when its data will be erased
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} DeletionResponse "Scheduled deletion"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Deletion not scheduled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /users/me/deletion [get]
+func (api *API) GetDeletion(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	deletion, err := api.userDataUseCase.GetDeletion(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, DeletionResponse{
+		Data: DeletionResponseData{
+			Deletion: deletion,
+		},
+	})
+}
+
+// @Summary Cancel account deletion
+// @Description Cancel a scheduled account deletion, possible until the account is erased
+// @Tags users
+// @Security BearerAuth
+// @Success 204 "Deletion cancelled"
+// @Failure 401 {object} utils.HTTPError "Unauthorized"
+// @Failure 404 {object} utils.HTTPError "Deletion not scheduled"
+// @Failure 500 {object} utils.HTTPError "Internal server error"
+// @Router /users/me/deletion [delete]
+func (api *API) CancelDeletion(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	err := api.userDataUseCase.CancelDeletion(userID)
+
+	if err != nil {
+		apiErr := utils.GetApiErr(err)
+		ctx.JSON(apiErr.StatusCode, apiErr)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package userdata
+
+import (
+	"log"
+	"time"
+)
+
+// how often accounts whose grace period ended are looked for
+const erasureInterval = time.Hour
+
+// RunErasureJob erases due accounts right away and then on every interval,
+// it never returns
+func RunErasureJob(uc UserDataUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		erased, err := uc.EraseDueAccounts(time.Now())
+		if err != nil {
+			log.Printf("account erasure failed: %v", err)
+		}
+		if erased > 0 {
+			log.Printf("account erasure: %d accounts erased", erased)
+		}
+
+		<-ticker.C
+	}
+}
@@ -0,0 +1,189 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/userdata"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserDataRepo struct {
+	mock.Mock
+}
+
+func (m *MockUserDataRepo) GetUser(db utils.Executer, userID string) (userdata.ArchiveUser, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(userdata.ArchiveUser), args.Error(1)
+}
+
+func (m *MockUserDataRepo) LockUser(db utils.Executer, userID string) error {
+	args := m.Called(db, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CountRecords(db utils.Executer, userID string) (int, error) {
+	args := m.Called(db, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListCategories(db utils.Executer, userID string) ([]userdata.ArchiveCategory, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveCategory), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListTransactions(db utils.Executer, userID string) ([]userdata.ArchiveTransaction, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveTransaction), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListEntries(db utils.Executer, userID string) ([]userdata.OwnedEntry, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.OwnedEntry), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListItems(db utils.Executer, userID string) ([]userdata.OwnedItem, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.OwnedItem), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListBudgets(db utils.Executer, userID string) ([]userdata.ArchiveBudget, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveBudget), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListMovements(db utils.Executer, userID string) ([]userdata.ArchiveMovement, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveMovement), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListRecurring(db utils.Executer, userID string) ([]userdata.ArchiveRecurring, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveRecurring), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListNetWorthItems(db utils.Executer, userID string) ([]userdata.ArchiveNetWorthItem, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveNetWorthItem), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListValuations(db utils.Executer, userID string) ([]userdata.OwnedValuation, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.OwnedValuation), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListRules(db utils.Executer, userID string) ([]userdata.ArchiveRule, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveRule), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ListImportProfiles(db utils.Executer, userID string) ([]userdata.ArchiveImportProfile, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).([]userdata.ArchiveImportProfile), args.Error(1)
+}
+
+func (m *MockUserDataRepo) GetJournal(db utils.Executer, userID string) (*userdata.ArchiveJournal, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(*userdata.ArchiveJournal), args.Error(1)
+}
+
+func (m *MockUserDataRepo) SetEnvelopeBudgetingSince(db utils.Executer, userID string, since *string) error {
+	args := m.Called(db, userID, since)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateCategory(db utils.Executer, userID string, category userdata.ArchiveCategory) error {
+	args := m.Called(db, userID, category)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateTransaction(db utils.Executer, userID string, transaction userdata.ArchiveTransaction) error {
+	args := m.Called(db, userID, transaction)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateEntry(db utils.Executer, transactionID string, entry userdata.ArchiveEntry) error {
+	args := m.Called(db, transactionID, entry)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateItem(db utils.Executer, transactionID string, item userdata.ArchiveItem) error {
+	args := m.Called(db, transactionID, item)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateBudget(db utils.Executer, userID string, budget userdata.ArchiveBudget) error {
+	args := m.Called(db, userID, budget)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateMovement(db utils.Executer, userID string, movement userdata.ArchiveMovement) error {
+	args := m.Called(db, userID, movement)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateRecurring(db utils.Executer, userID string, recurring userdata.ArchiveRecurring) error {
+	args := m.Called(db, userID, recurring)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateNetWorthItem(db utils.Executer, userID string, item userdata.ArchiveNetWorthItem) error {
+	args := m.Called(db, userID, item)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateValuation(db utils.Executer, userID string, itemID string, valuation userdata.ArchiveValuation) error {
+	args := m.Called(db, userID, itemID, valuation)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateRule(db utils.Executer, userID string, rule userdata.ArchiveRule) error {
+	args := m.Called(db, userID, rule)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateImportProfile(db utils.Executer, userID string, profile userdata.ArchiveImportProfile) error {
+	args := m.Called(db, userID, profile)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) SaveJournal(db utils.Executer, userID string, journal userdata.ArchiveJournal) error {
+	args := m.Called(db, userID, journal)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) GetDeletion(db utils.Executer, userID string) (*userdata.AccountDeletion, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(*userdata.AccountDeletion), args.Error(1)
+}
+
+func (m *MockUserDataRepo) ScheduleDeletion(db utils.Executer, userID string, scheduledFor time.Time) (userdata.AccountDeletion, error) {
+	args := m.Called(db, userID, scheduledFor)
+	return args.Get(0).(userdata.AccountDeletion), args.Error(1)
+}
+
+func (m *MockUserDataRepo) CancelDeletion(db utils.Executer, userID string) error {
+	args := m.Called(db, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) ListDueDeletions(db utils.Executer, now time.Time) ([]string, error) {
+	args := m.Called(db, now)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserDataRepo) EraseRecords(db utils.Executer, userID string) (map[string]int64, error) {
+	args := m.Called(db, userID)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockUserDataRepo) DeleteUser(db utils.Executer, userID string) error {
+	args := m.Called(db, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserDataRepo) CreateErasure(db utils.Executer, payload userdata.AccountErasure) (userdata.AccountErasure, error) {
+	args := m.Called(db, payload)
+	return args.Get(0).(userdata.AccountErasure), args.Error(1)
+}
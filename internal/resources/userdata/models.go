@@ -0,0 +1,232 @@
+package userdata
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ==============================================================================
+// 1. HTTP MODELS
+//    Models that represents request or response objects
+// ==============================================================================
+
+type ImportArchiveResponse struct {
+	Data ImportArchiveResponseData `json:"data"`
+}
+
+type ImportArchiveResponseData struct {
+	Restored RestoreSummary `json:"restored"`
+}
+
+// Number of records restored of each kind
+type RestoreSummary struct {
+	Categories            int  `json:"categories"`
+	Transactions          int  `json:"transactions"`
+	Entries               int  `json:"entries"`
+	Items                 int  `json:"items"`
+	Budgets               int  `json:"budgets"`
+	EnvelopeMovements     int  `json:"envelope_movements"`
+	RecurringTransactions int  `json:"recurring_transactions"`
+	NetWorthItems         int  `json:"net_worth_items"`
+	Valuations            int  `json:"valuations"`
+	Rules                 int  `json:"rules"`
+	ImportProfiles        int  `json:"import_profiles"`
+	JournalSettings       bool `json:"journal_settings"`
+}
+
+// The username of the account, typed again to confirm the deletion
+type RequestDeletionRequest struct {
+	ConfirmUsername string `json:"confirm_username" binding:"required" example:"johndoe"`
+}
+
+type DeletionResponse struct {
+	Data DeletionResponseData `json:"data"`
+}
+
+type DeletionResponseData struct {
+	Deletion AccountDeletion `json:"deletion"`
+}
+
+// ==============================================================================
+// 2. DTO MODELS
+//    Models that represents data transfer objects between api layers
+// ==============================================================================
+
+// Child records read apart from their parents, which they're nested into
+type OwnedEntry struct {
+	TransactionID string
+	Entry         ArchiveEntry
+}
+
+type OwnedItem struct {
+	TransactionID string
+	Item          ArchiveItem
+}
+
+type OwnedValuation struct {
+	ItemID    string
+	Valuation ArchiveValuation
+}
+
+// Archive holds every record of a user. Records keep the ids they had so the
+// references between them can be followed, a restore gives them new ids. The
+// version only changes when a field is removed or changes meaning
+type Archive struct {
+	Version               int                    `json:"version"`
+	ExportedAt            time.Time              `json:"exported_at"`
+	User                  ArchiveUser            `json:"user"`
+	Categories            []ArchiveCategory      `json:"categories"`
+	Transactions          []ArchiveTransaction   `json:"transactions"`
+	Budgets               []ArchiveBudget        `json:"budgets"`
+	EnvelopeMovements     []ArchiveMovement      `json:"envelope_movements"`
+	RecurringTransactions []ArchiveRecurring     `json:"recurring_transactions"`
+	NetWorthItems         []ArchiveNetWorthItem  `json:"net_worth_items"`
+	Rules                 []ArchiveRule          `json:"rules"`
+	ImportProfiles        []ArchiveImportProfile `json:"import_profiles"`
+	JournalSettings       *ArchiveJournal        `json:"journal_settings"`
+}
+
+type ArchiveUser struct {
+	Name                   string    `json:"name"`
+	Email                  string    `json:"email"`
+	Username               string    `json:"username"`
+	AvatarURL              *string   `json:"avatar_url"`
+	EnvelopeBudgetingSince *string   `json:"envelope_budgeting_since"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type ArchiveCategory struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ArchiveTransaction struct {
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description"`
+	CategoryID  *string        `json:"category_id"`
+	Payee       *string        `json:"payee"`
+	Account     *string        `json:"account"`
+	Tags        []string       `json:"tags"`
+	ExternalID  *string        `json:"external_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Entries     []ArchiveEntry `json:"entries"`
+	Items       []ArchiveItem  `json:"items"`
+}
+
+type ArchiveEntry struct {
+	ID            string    `json:"id"`
+	Amount        float64   `json:"amount"`
+	ReferenceDate string    `json:"reference_date"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ArchiveItem struct {
+	ID          string  `json:"id"`
+	Position    int     `json:"position"`
+	Code        *string `json:"code"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	Unit        *string `json:"unit"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+type ArchiveBudget struct {
+	ID         string    `json:"id"`
+	CategoryID string    `json:"category_id"`
+	Period     *string   `json:"period"`
+	Amount     float64   `json:"amount"`
+	Rollover   bool      `json:"rollover"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ArchiveMovement struct {
+	ID             string    `json:"id"`
+	Period         string    `json:"period"`
+	FromCategoryID *string   `json:"from_category_id"`
+	ToCategoryID   *string   `json:"to_category_id"`
+	Amount         float64   `json:"amount"`
+	Note           *string   `json:"note"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ArchiveRecurring struct {
+	ID          string    `json:"id"`
+	CategoryID  *string   `json:"category_id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Amount      float64   `json:"amount"`
+	StartPeriod string    `json:"start_period"`
+	EndPeriod   *string   `json:"end_period"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ArchiveNetWorthItem struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Kind       string             `json:"kind"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Valuations []ArchiveValuation `json:"valuations"`
+}
+
+type ArchiveValuation struct {
+	ID        string    `json:"id"`
+	Value     float64   `json:"value"`
+	ValuedAt  string    `json:"valued_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Conditions and actions are kept as stored, only the category of the actions
+// is remapped on restore
+type ArchiveRule struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Priority   int             `json:"priority"`
+	Conditions json.RawMessage `json:"conditions"`
+	Actions    json.RawMessage `json:"actions"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type ArchiveImportProfile struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Mapping   json.RawMessage `json:"mapping"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type ArchiveJournal struct {
+	AssetsRoot      string            `json:"assets_root"`
+	LiabilitiesRoot string            `json:"liabilities_root"`
+	IncomeRoot      string            `json:"income_root"`
+	ExpensesRoot    string            `json:"expenses_root"`
+	DefaultAccount  string            `json:"default_account"`
+	Uncategorized   string            `json:"uncategorized"`
+	Commodity       string            `json:"commodity"`
+	Accounts        map[string]string `json:"accounts"`
+}
+
+// ==============================================================================
+// 3. DATABASE
+//    Models that represents database objects
+// ==============================================================================
+
+// Deletion of an account scheduled on the users table, until ScheduledFor it
+// can still be cancelled
+type AccountDeletion struct {
+	RequestedAt  time.Time `json:"requested_at"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+// Account erasures table record, SubjectHash is the SHA-256 of the erased
+// user id so an erasure can be proven without keeping who it was
+type AccountErasure struct {
+	ID            string           `json:"id"`
+	SubjectHash   string           `json:"subject_hash"`
+	RequestedAt   time.Time        `json:"requested_at"`
+	ErasedAt      time.Time        `json:"erased_at"`
+	ErasedRecords map[string]int64 `json:"erased_records"`
+}
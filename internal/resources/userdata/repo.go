@@ -0,0 +1,711 @@
+package userdata
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+)
+
+// Tables with the records of a user, every one of them has a user_id column.
+// Settings aren't records, a restore replaces them
+var ownedTables = []string{"categories", "transactions", "budgets", "envelope_movements", "recurring_transactions", "net_worth_items", "net_worth_valuations", "rules", "import_profiles", "import_batches"}
+
+// Order the records of a user are erased in, children before the rows they
+// reference. Entries and items have no user_id and go through their transaction
+var erasureOrder = []string{"entries", "transaction_items", "transactions", "budgets", "envelope_movements", "recurring_transactions", "net_worth_valuations", "net_worth_items", "rules", "import_profiles", "journal_settings", "calendar_feeds", "categories", "import_batches"}
+
+type UserDataRepo interface {
+	GetUser(db utils.Executer, userID string) (ArchiveUser, error)
+	LockUser(db utils.Executer, userID string) error
+	CountRecords(db utils.Executer, userID string) (int, error)
+	ListCategories(db utils.Executer, userID string) ([]ArchiveCategory, error)
+	ListTransactions(db utils.Executer, userID string) ([]ArchiveTransaction, error)
+	ListEntries(db utils.Executer, userID string) ([]OwnedEntry, error)
+	ListItems(db utils.Executer, userID string) ([]OwnedItem, error)
+	ListBudgets(db utils.Executer, userID string) ([]ArchiveBudget, error)
+	ListMovements(db utils.Executer, userID string) ([]ArchiveMovement, error)
+	ListRecurring(db utils.Executer, userID string) ([]ArchiveRecurring, error)
+	ListNetWorthItems(db utils.Executer, userID string) ([]ArchiveNetWorthItem, error)
+	ListValuations(db utils.Executer, userID string) ([]OwnedValuation, error)
+	ListRules(db utils.Executer, userID string) ([]ArchiveRule, error)
+	ListImportProfiles(db utils.Executer, userID string) ([]ArchiveImportProfile, error)
+	GetJournal(db utils.Executer, userID string) (*ArchiveJournal, error)
+	SetEnvelopeBudgetingSince(db utils.Executer, userID string, since *string) error
+	CreateCategory(db utils.Executer, userID string, category ArchiveCategory) error
+	CreateTransaction(db utils.Executer, userID string, transaction ArchiveTransaction) error
+	CreateEntry(db utils.Executer, transactionID string, entry ArchiveEntry) error
+	CreateItem(db utils.Executer, transactionID string, item ArchiveItem) error
+	CreateBudget(db utils.Executer, userID string, budget ArchiveBudget) error
+	CreateMovement(db utils.Executer, userID string, movement ArchiveMovement) error
+	CreateRecurring(db utils.Executer, userID string, recurring ArchiveRecurring) error
+	CreateNetWorthItem(db utils.Executer, userID string, item ArchiveNetWorthItem) error
+	CreateValuation(db utils.Executer, userID string, itemID string, valuation ArchiveValuation) error
+	CreateRule(db utils.Executer, userID string, rule ArchiveRule) error
+	CreateImportProfile(db utils.Executer, userID string, profile ArchiveImportProfile) error
+	SaveJournal(db utils.Executer, userID string, journal ArchiveJournal) error
+	GetDeletion(db utils.Executer, userID string) (*AccountDeletion, error)
+	ScheduleDeletion(db utils.Executer, userID string, scheduledFor time.Time) (AccountDeletion, error)
+	CancelDeletion(db utils.Executer, userID string) error
+	ListDueDeletions(db utils.Executer, now time.Time) ([]string, error)
+	EraseRecords(db utils.Executer, userID string) (map[string]int64, error)
+	DeleteUser(db utils.Executer, userID string) error
+	CreateErasure(db utils.Executer, payload AccountErasure) (AccountErasure, error)
+}
+
+type UserDataRepoImpl struct {
+}
+
+func NewUserDataRepo(db utils.Executer) UserDataRepo {
+	return &UserDataRepoImpl{}
+}
+
+// list runs a select of a user's records ordered by creation, scan reads one row
+func list(db utils.Executer, query squirrel.SelectBuilder, scan func(rows *sql.Rows) error) error {
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func insert(db utils.Executer, table string, columns []string, values ...any) error {
+	sql, args, err := squirrel.Insert(table).
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sql, args...)
+	return err
+}
+
+func (r *UserDataRepoImpl) GetUser(db utils.Executer, userID string) (ArchiveUser, error) {
+	query, args, err := squirrel.Select("name", "email", "username", "avatar_url", "envelope_budgeting_since", "created_at").
+		From("users").
+		Where(squirrel.Eq{"id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return ArchiveUser{}, err
+	}
+
+	var user ArchiveUser
+	err = db.QueryRow(query, args...).Scan(
+		&user.Name,
+		&user.Email,
+		&user.Username,
+		&user.AvatarURL,
+		&user.EnvelopeBudgetingSince,
+		&user.CreatedAt,
+	)
+	return user, err
+}
+
+// LockUser holds the row of a user until the transaction ends
+func (r *UserDataRepoImpl) LockUser(db utils.Executer, userID string) error {
+	query, args, err := squirrel.Select("id").
+		From("users").
+		Where(squirrel.Eq{"id": userID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var id string
+	return db.QueryRow(query, args...).Scan(&id)
+}
+
+// CountRecords counts every record a user owns, zero is an empty account
+func (r *UserDataRepoImpl) CountRecords(db utils.Executer, userID string) (int, error) {
+	query := squirrel.Select()
+	for _, table := range ownedTables {
+		query = query.Column(squirrel.Expr("(SELECT count(*) FROM "+table+" WHERE user_id = ?)", userID))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make([]int, len(ownedTables))
+	dest := make([]any, len(ownedTables))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+
+	if err := db.QueryRow(sql, args...).Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total, nil
+}
+
+func (r *UserDataRepoImpl) ListCategories(db utils.Executer, userID string) ([]ArchiveCategory, error) {
+	categories := make([]ArchiveCategory, 0)
+	err := list(db, squirrel.Select("id", "name", "color", "created_at").
+		From("categories").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var category ArchiveCategory
+			if err := rows.Scan(&category.ID, &category.Name, &category.Color, &category.CreatedAt); err != nil {
+				return err
+			}
+			categories = append(categories, category)
+			return nil
+		})
+	return categories, err
+}
+
+func (r *UserDataRepoImpl) ListTransactions(db utils.Executer, userID string) ([]ArchiveTransaction, error) {
+	transactions := make([]ArchiveTransaction, 0)
+	err := list(db, squirrel.Select("id", "category", "name", "description", "category_id", "payee", "account", "tags", "external_id", "created_at").
+		From("transactions").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var transaction ArchiveTransaction
+			if err := rows.Scan(
+				&transaction.ID,
+				&transaction.Type,
+				&transaction.Name,
+				&transaction.Description,
+				&transaction.CategoryID,
+				&transaction.Payee,
+				&transaction.Account,
+				pq.Array(&transaction.Tags),
+				&transaction.ExternalID,
+				&transaction.CreatedAt,
+			); err != nil {
+				return err
+			}
+			transaction.Entries = []ArchiveEntry{}
+			transaction.Items = []ArchiveItem{}
+			transactions = append(transactions, transaction)
+			return nil
+		})
+	return transactions, err
+}
+
+func (r *UserDataRepoImpl) ListEntries(db utils.Executer, userID string) ([]OwnedEntry, error) {
+	entries := make([]OwnedEntry, 0)
+	err := list(db, squirrel.Select("e.transaction_id", "e.id", "e.amount", "e.reference_date::text", "e.created_at").
+		From("entries e").
+		Join("transactions t ON t.id = e.transaction_id").
+		Where(squirrel.Eq{"t.user_id": userID}).
+		OrderBy("e.reference_date", "e.id"),
+		func(rows *sql.Rows) error {
+			var entry OwnedEntry
+			if err := rows.Scan(
+				&entry.TransactionID,
+				&entry.Entry.ID,
+				&entry.Entry.Amount,
+				&entry.Entry.ReferenceDate,
+				&entry.Entry.CreatedAt,
+			); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	return entries, err
+}
+
+func (r *UserDataRepoImpl) ListItems(db utils.Executer, userID string) ([]OwnedItem, error) {
+	items := make([]OwnedItem, 0)
+	err := list(db, squirrel.Select("i.transaction_id", "i.id", "i.position", "i.code", "i.description", "i.quantity", "i.unit", "i.unit_price", "i.amount").
+		From("transaction_items i").
+		Join("transactions t ON t.id = i.transaction_id").
+		Where(squirrel.Eq{"t.user_id": userID}).
+		OrderBy("i.transaction_id", "i.position"),
+		func(rows *sql.Rows) error {
+			var item OwnedItem
+			if err := rows.Scan(
+				&item.TransactionID,
+				&item.Item.ID,
+				&item.Item.Position,
+				&item.Item.Code,
+				&item.Item.Description,
+				&item.Item.Quantity,
+				&item.Item.Unit,
+				&item.Item.UnitPrice,
+				&item.Item.Amount,
+			); err != nil {
+				return err
+			}
+			items = append(items, item)
+			return nil
+		})
+	return items, err
+}
+
+func (r *UserDataRepoImpl) ListBudgets(db utils.Executer, userID string) ([]ArchiveBudget, error) {
+	budgets := make([]ArchiveBudget, 0)
+	err := list(db, squirrel.Select("id", "category_id", "period", "amount", "rollover", "created_at").
+		From("budgets").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var budget ArchiveBudget
+			if err := rows.Scan(&budget.ID, &budget.CategoryID, &budget.Period, &budget.Amount, &budget.Rollover, &budget.CreatedAt); err != nil {
+				return err
+			}
+			budgets = append(budgets, budget)
+			return nil
+		})
+	return budgets, err
+}
+
+func (r *UserDataRepoImpl) ListMovements(db utils.Executer, userID string) ([]ArchiveMovement, error) {
+	movements := make([]ArchiveMovement, 0)
+	err := list(db, squirrel.Select("id", "period", "from_category_id", "to_category_id", "amount", "note", "created_at").
+		From("envelope_movements").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var movement ArchiveMovement
+			if err := rows.Scan(
+				&movement.ID,
+				&movement.Period,
+				&movement.FromCategoryID,
+				&movement.ToCategoryID,
+				&movement.Amount,
+				&movement.Note,
+				&movement.CreatedAt,
+			); err != nil {
+				return err
+			}
+			movements = append(movements, movement)
+			return nil
+		})
+	return movements, err
+}
+
+func (r *UserDataRepoImpl) ListRecurring(db utils.Executer, userID string) ([]ArchiveRecurring, error) {
+	recurring := make([]ArchiveRecurring, 0)
+	err := list(db, squirrel.Select("id", "category_id", "name", "type", "amount", "start_period", "end_period", "created_at").
+		From("recurring_transactions").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var item ArchiveRecurring
+			if err := rows.Scan(
+				&item.ID,
+				&item.CategoryID,
+				&item.Name,
+				&item.Type,
+				&item.Amount,
+				&item.StartPeriod,
+				&item.EndPeriod,
+				&item.CreatedAt,
+			); err != nil {
+				return err
+			}
+			recurring = append(recurring, item)
+			return nil
+		})
+	return recurring, err
+}
+
+func (r *UserDataRepoImpl) ListNetWorthItems(db utils.Executer, userID string) ([]ArchiveNetWorthItem, error) {
+	items := make([]ArchiveNetWorthItem, 0)
+	err := list(db, squirrel.Select("id", "name", "kind", "created_at").
+		From("net_worth_items").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var item ArchiveNetWorthItem
+			if err := rows.Scan(&item.ID, &item.Name, &item.Kind, &item.CreatedAt); err != nil {
+				return err
+			}
+			item.Valuations = []ArchiveValuation{}
+			items = append(items, item)
+			return nil
+		})
+	return items, err
+}
+
+func (r *UserDataRepoImpl) ListValuations(db utils.Executer, userID string) ([]OwnedValuation, error) {
+	valuations := make([]OwnedValuation, 0)
+	err := list(db, squirrel.Select("item_id", "id", "value", "valued_at::text", "created_at").
+		From("net_worth_valuations").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("valued_at", "id"),
+		func(rows *sql.Rows) error {
+			var valuation OwnedValuation
+			if err := rows.Scan(
+				&valuation.ItemID,
+				&valuation.Valuation.ID,
+				&valuation.Valuation.Value,
+				&valuation.Valuation.ValuedAt,
+				&valuation.Valuation.CreatedAt,
+			); err != nil {
+				return err
+			}
+			valuations = append(valuations, valuation)
+			return nil
+		})
+	return valuations, err
+}
+
+func (r *UserDataRepoImpl) ListRules(db utils.Executer, userID string) ([]ArchiveRule, error) {
+	rules := make([]ArchiveRule, 0)
+	err := list(db, squirrel.Select("id", "name", "priority", "conditions", "actions", "created_at").
+		From("rules").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("priority", "created_at", "id"),
+		func(rows *sql.Rows) error {
+			var rule ArchiveRule
+			if err := rows.Scan(&rule.ID, &rule.Name, &rule.Priority, &rule.Conditions, &rule.Actions, &rule.CreatedAt); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+			return nil
+		})
+	return rules, err
+}
+
+func (r *UserDataRepoImpl) ListImportProfiles(db utils.Executer, userID string) ([]ArchiveImportProfile, error) {
+	profiles := make([]ArchiveImportProfile, 0)
+	err := list(db, squirrel.Select("id", "name", "mapping", "created_at").
+		From("import_profiles").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at", "id"),
+		func(rows *sql.Rows) error {
+			var profile ArchiveImportProfile
+			if err := rows.Scan(&profile.ID, &profile.Name, &profile.Mapping, &profile.CreatedAt); err != nil {
+				return err
+			}
+			profiles = append(profiles, profile)
+			return nil
+		})
+	return profiles, err
+}
+
+// GetJournal returns nil while the user never saved journal settings
+func (r *UserDataRepoImpl) GetJournal(db utils.Executer, userID string) (*ArchiveJournal, error) {
+	query, args, err := squirrel.Select("assets_root", "liabilities_root", "income_root", "expenses_root", "default_account", "uncategorized", "commodity", "accounts").
+		From("journal_settings").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var journal ArchiveJournal
+	var accounts []byte
+	err = db.QueryRow(query, args...).Scan(
+		&journal.AssetsRoot,
+		&journal.LiabilitiesRoot,
+		&journal.IncomeRoot,
+		&journal.ExpensesRoot,
+		&journal.DefaultAccount,
+		&journal.Uncategorized,
+		&journal.Commodity,
+		&accounts,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(accounts, &journal.Accounts); err != nil {
+		return nil, err
+	}
+
+	return &journal, nil
+}
+
+func (r *UserDataRepoImpl) SetEnvelopeBudgetingSince(db utils.Executer, userID string, since *string) error {
+	query, args, err := squirrel.Update("users").
+		Set("envelope_budgeting_since", since).
+		Where(squirrel.Eq{"id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+func (r *UserDataRepoImpl) CreateCategory(db utils.Executer, userID string, category ArchiveCategory) error {
+	return insert(db, "categories",
+		[]string{"id", "user_id", "name", "color", "created_at"},
+		category.ID, userID, category.Name, category.Color, category.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateTransaction(db utils.Executer, userID string, transaction ArchiveTransaction) error {
+	tags := transaction.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return insert(db, "transactions",
+		[]string{"id", "user_id", "category", "name", "description", "category_id", "payee", "account", "tags", "external_id", "created_at"},
+		transaction.ID, userID, transaction.Type, transaction.Name, transaction.Description, transaction.CategoryID, transaction.Payee, transaction.Account, pq.Array(tags), transaction.ExternalID, transaction.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateEntry(db utils.Executer, transactionID string, entry ArchiveEntry) error {
+	return insert(db, "entries",
+		[]string{"id", "transaction_id", "amount", "reference_date", "created_at"},
+		entry.ID, transactionID, entry.Amount, entry.ReferenceDate, entry.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateItem(db utils.Executer, transactionID string, item ArchiveItem) error {
+	return insert(db, "transaction_items",
+		[]string{"id", "transaction_id", "position", "code", "description", "quantity", "unit", "unit_price", "amount"},
+		item.ID, transactionID, item.Position, item.Code, item.Description, item.Quantity, item.Unit, item.UnitPrice, item.Amount)
+}
+
+func (r *UserDataRepoImpl) CreateBudget(db utils.Executer, userID string, budget ArchiveBudget) error {
+	return insert(db, "budgets",
+		[]string{"id", "user_id", "category_id", "period", "amount", "rollover", "created_at"},
+		budget.ID, userID, budget.CategoryID, budget.Period, budget.Amount, budget.Rollover, budget.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateMovement(db utils.Executer, userID string, movement ArchiveMovement) error {
+	return insert(db, "envelope_movements",
+		[]string{"id", "user_id", "period", "from_category_id", "to_category_id", "amount", "note", "created_at"},
+		movement.ID, userID, movement.Period, movement.FromCategoryID, movement.ToCategoryID, movement.Amount, movement.Note, movement.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateRecurring(db utils.Executer, userID string, recurring ArchiveRecurring) error {
+	return insert(db, "recurring_transactions",
+		[]string{"id", "user_id", "category_id", "name", "type", "amount", "start_period", "end_period", "created_at"},
+		recurring.ID, userID, recurring.CategoryID, recurring.Name, recurring.Type, recurring.Amount, recurring.StartPeriod, recurring.EndPeriod, recurring.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateNetWorthItem(db utils.Executer, userID string, item ArchiveNetWorthItem) error {
+	return insert(db, "net_worth_items",
+		[]string{"id", "user_id", "name", "kind", "created_at"},
+		item.ID, userID, item.Name, item.Kind, item.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateValuation(db utils.Executer, userID string, itemID string, valuation ArchiveValuation) error {
+	return insert(db, "net_worth_valuations",
+		[]string{"id", "user_id", "item_id", "value", "valued_at", "created_at"},
+		valuation.ID, userID, itemID, valuation.Value, valuation.ValuedAt, valuation.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateRule(db utils.Executer, userID string, rule ArchiveRule) error {
+	return insert(db, "rules",
+		[]string{"id", "user_id", "name", "priority", "conditions", "actions", "created_at"},
+		rule.ID, userID, rule.Name, rule.Priority, []byte(rule.Conditions), []byte(rule.Actions), rule.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) CreateImportProfile(db utils.Executer, userID string, profile ArchiveImportProfile) error {
+	return insert(db, "import_profiles",
+		[]string{"id", "user_id", "name", "mapping", "created_at"},
+		profile.ID, userID, profile.Name, []byte(profile.Mapping), profile.CreatedAt)
+}
+
+func (r *UserDataRepoImpl) SaveJournal(db utils.Executer, userID string, journal ArchiveJournal) error {
+	if journal.Accounts == nil {
+		journal.Accounts = map[string]string{}
+	}
+
+	accounts, err := json.Marshal(journal.Accounts)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := squirrel.Insert("journal_settings").
+		Columns("user_id", "assets_root", "liabilities_root", "income_root", "expenses_root", "default_account", "uncategorized", "commodity", "accounts").
+		Values(userID, journal.AssetsRoot, journal.LiabilitiesRoot, journal.IncomeRoot, journal.ExpensesRoot, journal.DefaultAccount, journal.Uncategorized, journal.Commodity, accounts).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET
+			assets_root = excluded.assets_root,
+			liabilities_root = excluded.liabilities_root,
+			income_root = excluded.income_root,
+			expenses_root = excluded.expenses_root,
+			default_account = excluded.default_account,
+			uncategorized = excluded.uncategorized,
+			commodity = excluded.commodity,
+			accounts = excluded.accounts,
+			updated_at = now()`).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+// GetDeletion returns nil while the deletion of the account isn't scheduled
+func (r *UserDataRepoImpl) GetDeletion(db utils.Executer, userID string) (*AccountDeletion, error) {
+	query, args, err := squirrel.Select("deletion_requested_at", "deletion_scheduled_for").
+		From("users").
+		Where(squirrel.Eq{"id": userID}).
+		Where(squirrel.NotEq{"deletion_scheduled_for": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var deletion AccountDeletion
+	err = db.QueryRow(query, args...).Scan(&deletion.RequestedAt, &deletion.ScheduledFor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &deletion, nil
+}
+
+func (r *UserDataRepoImpl) ScheduleDeletion(db utils.Executer, userID string, scheduledFor time.Time) (AccountDeletion, error) {
+	query, args, err := squirrel.Update("users").
+		Set("deletion_requested_at", squirrel.Expr("now()")).
+		Set("deletion_scheduled_for", scheduledFor).
+		Where(squirrel.Eq{"id": userID}).
+		Suffix("RETURNING deletion_requested_at, deletion_scheduled_for").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return AccountDeletion{}, err
+	}
+
+	var deletion AccountDeletion
+	err = db.QueryRow(query, args...).Scan(&deletion.RequestedAt, &deletion.ScheduledFor)
+	return deletion, err
+}
+
+func (r *UserDataRepoImpl) CancelDeletion(db utils.Executer, userID string) error {
+	query, args, err := squirrel.Update("users").
+		Set("deletion_requested_at", nil).
+		Set("deletion_scheduled_for", nil).
+		Where(squirrel.Eq{"id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+func (r *UserDataRepoImpl) ListDueDeletions(db utils.Executer, now time.Time) ([]string, error) {
+	ids := make([]string, 0)
+	err := list(db, squirrel.Select("id").
+		From("users").
+		Where(squirrel.LtOrEq{"deletion_scheduled_for": now}).
+		OrderBy("deletion_scheduled_for"),
+		func(rows *sql.Rows) error {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			return nil
+		})
+	return ids, err
+}
+
+// EraseRecords deletes every record of a user, but not the user, and returns
+// how many were deleted from each table
+func (r *UserDataRepoImpl) EraseRecords(db utils.Executer, userID string) (map[string]int64, error) {
+	erased := make(map[string]int64, len(erasureOrder))
+
+	for _, table := range erasureOrder {
+		var owner squirrel.Sqlizer = squirrel.Eq{"user_id": userID}
+		if table == "entries" || table == "transaction_items" {
+			owner = squirrel.Expr("transaction_id IN (SELECT id FROM transactions WHERE user_id = ?)", userID)
+		}
+
+		query, args, err := squirrel.Delete(table).
+			Where(owner).
+			PlaceholderFormat(squirrel.Dollar).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := db.Exec(query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		if erased[table], err = result.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	return erased, nil
+}
+
+func (r *UserDataRepoImpl) DeleteUser(db utils.Executer, userID string) error {
+	query, args, err := squirrel.Delete("users").
+		Where(squirrel.Eq{"id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+func (r *UserDataRepoImpl) CreateErasure(db utils.Executer, payload AccountErasure) (AccountErasure, error) {
+	records, err := json.Marshal(payload.ErasedRecords)
+	if err != nil {
+		return AccountErasure{}, err
+	}
+
+	query, args, err := squirrel.Insert("account_erasures").
+		Columns("id", "subject_hash", "requested_at", "erased_records").
+		Values(ulid.Make().String(), payload.SubjectHash, payload.RequestedAt, records).
+		Suffix("RETURNING id, subject_hash, requested_at, erased_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return AccountErasure{}, err
+	}
+
+	erasure := AccountErasure{ErasedRecords: payload.ErasedRecords}
+	err = db.QueryRow(query, args...).Scan(
+		&erasure.ID,
+		&erasure.SubjectHash,
+		&erasure.RequestedAt,
+		&erasure.ErasedAt,
+	)
+	return erasure, err
+}
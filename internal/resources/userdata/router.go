@@ -0,0 +1,41 @@
+package userdata
+
+import (
+	"log"
+	"os"
+
+	"github.com/felipe1496/open-wallet/db"
+
+	"github.com/felipe1496/open-wallet/internal/middlewares"
+	"github.com/felipe1496/open-wallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(router *gin.Engine) {
+	db, err := db.Conn(os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	jwtService := services.NewJWTService()
+	handler := NewHandler(db)
+	go RunErasureJob(handler.userDataUseCase, erasureInterval)
+	group := router.Group("/api/v1/users/me")
+	{
+		group.DELETE("",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.RequestDeletion)
+		group.GET("/deletion",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.GetDeletion)
+		group.DELETE("/deletion",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.CancelDeletion)
+		group.GET("/export",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ExportArchive)
+		group.POST("/import",
+			middlewares.RequireAuthMiddleware(jwtService),
+			handler.ImportArchive)
+	}
+}
@@ -0,0 +1,426 @@
+package userdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/journal"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type UserDataUseCase interface {
+	Export(userID string) (Archive, error)
+	Import(userID string, archive Archive) (RestoreSummary, error)
+	RequestDeletion(userID string, confirmUsername string) (AccountDeletion, error)
+	GetDeletion(userID string) (AccountDeletion, error)
+	CancelDeletion(userID string) error
+	EraseDueAccounts(now time.Time) (int, error)
+}
+
+// DeletionGracePeriod is how long a deleted account can still be recovered
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+type UserDataUseCaseImpl struct {
+	repo UserDataRepo
+	db   *sql.DB
+}
+
+func NewUserDataUseCase(repo UserDataRepo, db *sql.DB) UserDataUseCase {
+	return &UserDataUseCaseImpl{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// Export reads every record of a user in a single snapshot, so the archive
+// never has an entry without its transaction
+func (uc *UserDataUseCaseImpl) Export(userID string) (Archive, error) {
+	tx, err := uc.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return Archive{}, ErrFailedToExport
+	}
+	defer tx.Rollback()
+
+	archive, err := uc.readArchive(tx, userID)
+	if err != nil {
+		return Archive{}, ErrFailedToExport
+	}
+
+	return archive, nil
+}
+
+func (uc *UserDataUseCaseImpl) readArchive(db utils.Executer, userID string) (Archive, error) {
+	archive := Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+	var err error
+
+	if archive.User, err = uc.repo.GetUser(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.Categories, err = uc.repo.ListCategories(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.Transactions, err = uc.repo.ListTransactions(db, userID); err != nil {
+		return Archive{}, err
+	}
+
+	positions := make(map[string]int, len(archive.Transactions))
+	for i, transaction := range archive.Transactions {
+		positions[transaction.ID] = i
+	}
+
+	entries, err := uc.repo.ListEntries(db, userID)
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, entry := range entries {
+		transaction := &archive.Transactions[positions[entry.TransactionID]]
+		transaction.Entries = append(transaction.Entries, entry.Entry)
+	}
+
+	items, err := uc.repo.ListItems(db, userID)
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, item := range items {
+		transaction := &archive.Transactions[positions[item.TransactionID]]
+		transaction.Items = append(transaction.Items, item.Item)
+	}
+
+	if archive.Budgets, err = uc.repo.ListBudgets(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.EnvelopeMovements, err = uc.repo.ListMovements(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.RecurringTransactions, err = uc.repo.ListRecurring(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.NetWorthItems, err = uc.repo.ListNetWorthItems(db, userID); err != nil {
+		return Archive{}, err
+	}
+
+	itemPositions := make(map[string]int, len(archive.NetWorthItems))
+	for i, item := range archive.NetWorthItems {
+		itemPositions[item.ID] = i
+	}
+
+	valuations, err := uc.repo.ListValuations(db, userID)
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, valuation := range valuations {
+		item := &archive.NetWorthItems[itemPositions[valuation.ItemID]]
+		item.Valuations = append(item.Valuations, valuation.Valuation)
+	}
+
+	if archive.Rules, err = uc.repo.ListRules(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.ImportProfiles, err = uc.repo.ListImportProfiles(db, userID); err != nil {
+		return Archive{}, err
+	}
+	if archive.JournalSettings, err = uc.repo.GetJournal(db, userID); err != nil {
+		return Archive{}, err
+	}
+
+	return archive, nil
+}
+
+// Import restores an archive into an account without data, every record gets
+// a new id. The identity of the user (name, email, username) is kept, only the
+// preferences of the archive are taken
+func (uc *UserDataUseCaseImpl) Import(userID string, archive Archive) (RestoreSummary, error) {
+	archive, err := RemapArchive(archive, func() string {
+		return ulid.Make().String()
+	})
+	if err != nil {
+		return RestoreSummary{}, err
+	}
+
+	if archive.JournalSettings != nil {
+		settings := journal.DefaultSettings(userID)
+		settings.AssetsRoot = archive.JournalSettings.AssetsRoot
+		settings.LiabilitiesRoot = archive.JournalSettings.LiabilitiesRoot
+		settings.IncomeRoot = archive.JournalSettings.IncomeRoot
+		settings.ExpensesRoot = archive.JournalSettings.ExpensesRoot
+		settings.DefaultAccount = archive.JournalSettings.DefaultAccount
+		settings.Uncategorized = archive.JournalSettings.Uncategorized
+		settings.Commodity = archive.JournalSettings.Commodity
+		if archive.JournalSettings.Accounts != nil {
+			settings.Accounts = archive.JournalSettings.Accounts
+		}
+		if journal.ValidateSettings(settings) != nil {
+			return RestoreSummary{}, ErrInvalidArchive
+		}
+	}
+
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return RestoreSummary{}, ErrFailedToRestore
+	}
+	defer tx.Rollback()
+
+	// checked inside the transaction so two restores can't both see an empty account
+	if err := uc.repo.LockUser(tx, userID); err != nil {
+		return RestoreSummary{}, ErrFailedToCheckAccount
+	}
+
+	count, err := uc.repo.CountRecords(tx, userID)
+	if err != nil {
+		return RestoreSummary{}, ErrFailedToCheckAccount
+	}
+	if count > 0 {
+		return RestoreSummary{}, ErrAccountNotEmpty
+	}
+
+	summary, err := uc.restore(tx, userID, archive)
+	if err != nil {
+		return RestoreSummary{}, ErrFailedToRestore
+	}
+
+	if err := tx.Commit(); err != nil {
+		return RestoreSummary{}, ErrFailedToRestore
+	}
+
+	return summary, nil
+}
+
+func (uc *UserDataUseCaseImpl) restore(db utils.Executer, userID string, archive Archive) (RestoreSummary, error) {
+	var summary RestoreSummary
+
+	for _, category := range archive.Categories {
+		if err := uc.repo.CreateCategory(db, userID, category); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.Categories++
+	}
+
+	for _, transaction := range archive.Transactions {
+		if err := uc.repo.CreateTransaction(db, userID, transaction); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.Transactions++
+
+		for _, entry := range transaction.Entries {
+			if err := uc.repo.CreateEntry(db, transaction.ID, entry); err != nil {
+				return RestoreSummary{}, err
+			}
+			summary.Entries++
+		}
+
+		for _, item := range transaction.Items {
+			if err := uc.repo.CreateItem(db, transaction.ID, item); err != nil {
+				return RestoreSummary{}, err
+			}
+			summary.Items++
+		}
+	}
+
+	for _, budget := range archive.Budgets {
+		if err := uc.repo.CreateBudget(db, userID, budget); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.Budgets++
+	}
+
+	for _, movement := range archive.EnvelopeMovements {
+		if err := uc.repo.CreateMovement(db, userID, movement); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.EnvelopeMovements++
+	}
+
+	for _, recurring := range archive.RecurringTransactions {
+		if err := uc.repo.CreateRecurring(db, userID, recurring); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.RecurringTransactions++
+	}
+
+	for _, item := range archive.NetWorthItems {
+		if err := uc.repo.CreateNetWorthItem(db, userID, item); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.NetWorthItems++
+
+		for _, valuation := range item.Valuations {
+			if err := uc.repo.CreateValuation(db, userID, item.ID, valuation); err != nil {
+				return RestoreSummary{}, err
+			}
+			summary.Valuations++
+		}
+	}
+
+	for _, rule := range archive.Rules {
+		if err := uc.repo.CreateRule(db, userID, rule); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.Rules++
+	}
+
+	for _, profile := range archive.ImportProfiles {
+		if err := uc.repo.CreateImportProfile(db, userID, profile); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.ImportProfiles++
+	}
+
+	if archive.JournalSettings != nil {
+		if err := uc.repo.SaveJournal(db, userID, *archive.JournalSettings); err != nil {
+			return RestoreSummary{}, err
+		}
+		summary.JournalSettings = true
+	}
+
+	if err := uc.repo.SetEnvelopeBudgetingSince(db, userID, archive.User.EnvelopeBudgetingSince); err != nil {
+		return RestoreSummary{}, err
+	}
+
+	return summary, nil
+}
+
+// ConfirmsDeletion tells if the confirmation typed by the user is the
+// username of the account, only surrounding spaces are forgiven
+func ConfirmsDeletion(username string, confirmation string) bool {
+	return username != "" && strings.TrimSpace(confirmation) == username
+}
+
+// ErasureSubject is the only trace of an erased user, it proves that a known
+// id was erased but can't be turned back into it
+func ErasureSubject(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestDeletion schedules the erasure of the account after the grace
+// period, asking again keeps the first schedule
+func (uc *UserDataUseCaseImpl) RequestDeletion(userID string, confirmUsername string) (AccountDeletion, error) {
+	user, err := uc.repo.GetUser(uc.db, userID)
+	if err != nil {
+		return AccountDeletion{}, ErrFailedToScheduleDeletion
+	}
+
+	if !ConfirmsDeletion(user.Username, confirmUsername) {
+		return AccountDeletion{}, ErrConfirmationMismatch
+	}
+
+	scheduled, err := uc.repo.GetDeletion(uc.db, userID)
+	if err != nil {
+		return AccountDeletion{}, ErrFailedToScheduleDeletion
+	}
+
+	if scheduled != nil {
+		return *scheduled, nil
+	}
+
+	deletion, err := uc.repo.ScheduleDeletion(uc.db, userID, time.Now().Add(DeletionGracePeriod))
+	if err != nil {
+		return AccountDeletion{}, ErrFailedToScheduleDeletion
+	}
+
+	return deletion, nil
+}
+
+func (uc *UserDataUseCaseImpl) GetDeletion(userID string) (AccountDeletion, error) {
+	deletion, err := uc.repo.GetDeletion(uc.db, userID)
+	if err != nil {
+		return AccountDeletion{}, ErrFailedToGetDeletion
+	}
+
+	if deletion == nil {
+		return AccountDeletion{}, ErrDeletionNotScheduled
+	}
+
+	return *deletion, nil
+}
+
+func (uc *UserDataUseCaseImpl) CancelDeletion(userID string) error {
+	if _, err := uc.GetDeletion(userID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.CancelDeletion(uc.db, userID); err != nil {
+		return ErrFailedToCancelDeletion
+	}
+
+	return nil
+}
+
+// EraseDueAccounts erases every account whose grace period ended by now. An
+// account that fails is left for the next run without stopping the others
+func (uc *UserDataUseCaseImpl) EraseDueAccounts(now time.Time) (int, error) {
+	ids, err := uc.repo.ListDueDeletions(uc.db, now)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	var firstErr error
+	for _, id := range ids {
+		done, err := uc.eraseAccount(id, now)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if done {
+			erased++
+		}
+	}
+
+	return erased, firstErr
+}
+
+// eraseAccount hard deletes a user with all its records and leaves the
+// anonymous erasure record, all in one transaction
+func (uc *UserDataUseCaseImpl) eraseAccount(userID string, now time.Time) (bool, error) {
+	tx, err := uc.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.LockUser(tx, userID); err != nil {
+		return false, err
+	}
+
+	// the deletion may have been cancelled since it was listed
+	deletion, err := uc.repo.GetDeletion(tx, userID)
+	if err != nil {
+		return false, err
+	}
+	if deletion == nil || deletion.ScheduledFor.After(now) {
+		return false, nil
+	}
+
+	erased, err := uc.repo.EraseRecords(tx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := uc.repo.DeleteUser(tx, userID); err != nil {
+		return false, err
+	}
+	erased["users"] = 1
+
+	if _, err := uc.repo.CreateErasure(tx, AccountErasure{
+		SubjectHash:   ErasureSubject(userID),
+		RequestedAt:   deletion.RequestedAt,
+		ErasedRecords: erased,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
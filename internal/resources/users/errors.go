@@ -9,6 +9,7 @@ import (
 var (
 	FailedToFetchUsersError = utils.NewHTTPError(http.StatusInternalServerError, "failed to fetch users")
 	FailedToCreateUserError = utils.NewHTTPError(http.StatusInternalServerError, "failed to create user")
+	FailedToUpdateUserError = utils.NewHTTPError(http.StatusInternalServerError, "failed to update user")
 	UsernameAlreadyExists   = utils.NewHTTPError(http.StatusConflict, "user with this username already exists")
 	EmailAlreadyExists      = utils.NewHTTPError(http.StatusConflict, "user with this email already exists")
 )
@@ -19,3 +19,8 @@ func (m *MockUsersRepo) CreateUser(input users.CreateUserInput) (users.User, err
 	args := m.Called(input)
 	return args.Get(0).(users.User), args.Error(1)
 }
+
+func (m *MockUsersRepo) UpdateUser(id string, input users.UpdateUserInput) (users.User, error) {
+	args := m.Called(id, input)
+	return args.Get(0).(users.User), args.Error(1)
+}
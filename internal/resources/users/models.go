@@ -7,16 +7,23 @@ type CreateUserInput struct {
 	Username  string  `json:"username"`
 }
 
+type UpdateUserInput struct {
+	Update                 []string
+	EnvelopeBudgetingSince *string
+}
+
 type User struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	AvatarURL string `json:"avatar_url"`
-	CreatedAt string `json:"created_at"`
-	Username  string `json:"username"`
+	ID                     string  `json:"id"`
+	Name                   string  `json:"name"`
+	Email                  string  `json:"email"`
+	AvatarURL              string  `json:"avatar_url"`
+	CreatedAt              string  `json:"created_at"`
+	Username               string  `json:"username"`
+	EnvelopeBudgetingSince *string `json:"envelope_budgeting_since"`
 }
 
 type UserFilter struct {
+	ID       string
 	Email    string
 	Username string
 }
@@ -10,6 +10,7 @@ import (
 type UsersRepo interface {
 	ListUsers(filter UserFilter) ([]User, error)
 	CreateUser(input CreateUserInput) (User, error)
+	UpdateUser(id string, input UpdateUserInput) (User, error)
 }
 
 type UsersRepoImpl struct {
@@ -22,10 +23,14 @@ func NewUsersRepo(db *sql.DB) UsersRepo {
 
 func (r *UsersRepoImpl) ListUsers(filter UserFilter) ([]User, error) {
 	query := squirrel.
-		Select("id", "name", "email", "avatar_url", "created_at", "username").
+		Select("id", "name", "email", "avatar_url", "created_at", "username", "envelope_budgeting_since").
 		From("users").
 		PlaceholderFormat(squirrel.Dollar)
 
+	if filter.ID != "" {
+		query = query.Where(squirrel.Eq{"id": filter.ID})
+	}
+
 	if filter.Email != "" {
 		query = query.Where(squirrel.Eq{"email": filter.Email})
 	}
@@ -55,6 +60,7 @@ func (r *UsersRepoImpl) ListUsers(filter UserFilter) ([]User, error) {
 			&user.AvatarURL,
 			&user.CreatedAt,
 			&user.Username,
+			&user.EnvelopeBudgetingSince,
 		); err != nil {
 			return nil, err
 		}
@@ -76,7 +82,7 @@ func (r *UsersRepoImpl) CreateUser(input CreateUserInput) (User, error) {
 			input.Username,
 			squirrel.Expr("NOW()"),
 		).
-		Suffix("RETURNING id, name, email, avatar_url, created_at, username").
+		Suffix("RETURNING id, name, email, avatar_url, created_at, username, envelope_budgeting_since").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
@@ -92,6 +98,43 @@ func (r *UsersRepoImpl) CreateUser(input CreateUserInput) (User, error) {
 		&user.AvatarURL,
 		&user.CreatedAt,
 		&user.Username,
+		&user.EnvelopeBudgetingSince,
+	)
+
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *UsersRepoImpl) UpdateUser(id string, input UpdateUserInput) (User, error) {
+	query := squirrel.Update("users").
+		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING id, name, email, avatar_url, created_at, username, envelope_budgeting_since").
+		PlaceholderFormat(squirrel.Dollar)
+
+	for _, field := range input.Update {
+		switch field {
+		case "envelope_budgeting_since":
+			query = query.Set("envelope_budgeting_since", input.EnvelopeBudgetingSince)
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return User{}, err
+	}
+
+	var user User
+	err = r.db.QueryRow(sql, args...).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.AvatarURL,
+		&user.CreatedAt,
+		&user.Username,
+		&user.EnvelopeBudgetingSince,
 	)
 
 	if err != nil {
@@ -3,6 +3,7 @@ package users
 type UsersUseCase interface {
 	List(filter UserFilter) ([]User, error)
 	Create(input CreateUserInput) (User, error)
+	Update(id string, input UpdateUserInput) (User, error)
 }
 
 type UsersUseCaseImpl struct {
@@ -40,3 +41,12 @@ func (uc *UsersUseCaseImpl) Create(input CreateUserInput) (User, error) {
 
 	return user, nil
 }
+
+func (uc *UsersUseCaseImpl) Update(id string, input UpdateUserInput) (User, error) {
+	user, err := uc.repo.UpdateUser(id, input)
+	if err != nil {
+		return User{}, FailedToUpdateUserError
+	}
+
+	return user, nil
+}
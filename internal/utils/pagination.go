@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+func EncodeCursor(cursor Cursor) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// numbers are kept as json.Number so decimals are compared without float rounding
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var cursor Cursor
+	if err := decoder.Decode(&cursor); err != nil {
+		return Cursor{}, err
+	}
+	return cursor, nil
+}
+
+// rowColumns maps the columns of a model to its fields, a column is named by
+// the db tag of the field or else by its json tag
+func rowColumns(t reflect.Type) map[string][]int {
+	columns := map[string][]int{}
+	for _, field := range reflect.VisibleFields(t) {
+		name := field.Tag.Get("db")
+		if name == "" {
+			name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+		}
+		if name != "" && name != "-" && field.IsExported() {
+			columns[name] = field.Index
+		}
+	}
+	return columns
+}
+
+// cursorFromRow reads the values of the order fields from the row, so order
+// fields must be columns of the model
+func cursorFromRow(row any, qo *QueryOptsBuilder, backward bool) (*string, error) {
+	value := reflect.Indirect(reflect.ValueOf(row))
+	columns := rowColumns(value.Type())
+
+	fields := qo.OrderFields()
+	cursor := Cursor{Orders: qo.OrderKeys(), Values: make([]any, len(fields)), Backward: backward}
+	for i, field := range fields {
+		index, ok := columns[field]
+		if !ok {
+			return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("order_by field '%s' cannot be used with cursor pagination", field))
+		}
+
+		fieldValue, err := value.FieldByIndexErr(index)
+		if err != nil {
+			return nil, err
+		}
+		for fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+
+		// a nil pointer is a null column, compared as such by the next query
+		if fieldValue.Kind() != reflect.Pointer {
+			cursor.Values[i] = fieldValue.Interface()
+		}
+	}
+
+	encoded, err := EncodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &encoded, nil
+}
+
+// Paginate trims the extra row fetched by QueryOptsMiddleware to probe for a
+// next page and fills the pagination fields of QueryMeta, either by page
+// number or by cursor when keyset pagination was requested. count is the
+// count of the listing, called with the filter of every matching row and,
+// on backward pages, with the rows from the cursor on
+func Paginate[T any](items []T, qo *QueryOptsBuilder, page, perPage int, count func(filter *QueryOptsBuilder) (int, error)) ([]T, QueryMeta, error) {
+	totalItems, err := count(ForCount(qo))
+	if err != nil {
+		return nil, QueryMeta{}, err
+	}
+
+	hasMore := len(items) > perPage
+	if hasMore {
+		items = items[:perPage]
+	}
+
+	meta := QueryMeta{
+		Page:       page,
+		PerPage:    perPage,
+		TotalItems: totalItems,
+		TotalPages: (totalItems + perPage - 1) / perPage,
+		NextPage:   hasMore,
+	}
+
+	if !qo.CursorPagination {
+		return items, meta, nil
+	}
+
+	backward := qo.CursorValue != nil && qo.CursorValue.Backward
+	if backward {
+		slices.Reverse(items)
+	}
+
+	if len(items) == 0 {
+		return items, meta, nil
+	}
+
+	// going forward there is a next page only if the probe found one, and a
+	// previous one whenever we started from a cursor. Going backward the probe
+	// tells about the previous page, and the rows from the cursor on about the next
+	hasNext := hasMore
+	hasPrev := qo.CursorValue != nil
+	if backward {
+		rowsFromCursor, err := count(fromCursor(qo))
+		if err != nil {
+			return nil, QueryMeta{}, err
+		}
+		hasNext = rowsFromCursor > 0
+		hasPrev = hasMore
+	}
+
+	if hasNext {
+		next, err := cursorFromRow(items[len(items)-1], qo, false)
+		if err != nil {
+			return nil, QueryMeta{}, err
+		}
+		meta.NextCursor = next
+	}
+
+	if hasPrev {
+		prev, err := cursorFromRow(items[0], qo, true)
+		if err != nil {
+			return nil, QueryMeta{}, err
+		}
+		meta.PrevCursor = prev
+	}
+
+	meta.NextPage = hasNext
+
+	return items, meta, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
@@ -28,12 +29,23 @@ type Order struct {
 	dir   string
 }
 
+// Cursor holds the order_by values of the row a keyset page starts after (or
+// before, when Backward is set)
+type Cursor struct {
+	Orders   []string `json:"o"`
+	Values   []any    `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
 type QueryOptsBuilder struct {
-	AndConditions []Condition
-	OrGroups      [][]Condition
-	Orders        []Order
-	LimitValue    *int
-	OffsetValue   *int
+	AndConditions    []Condition
+	OrGroups         [][]Condition
+	Exprs            []squirrel.Sqlizer
+	Orders           []Order
+	LimitValue       *int
+	OffsetValue      *int
+	CursorPagination bool
+	CursorValue      *Cursor
 }
 
 func (qo *QueryOptsBuilder) And(field, operator string, value any) *QueryOptsBuilder {
@@ -45,6 +57,13 @@ func (qo *QueryOptsBuilder) And(field, operator string, value any) *QueryOptsBui
 	return qo
 }
 
+// AndExpr adds a condition that can't be expressed as field operator value,
+// like a subquery, it is never built from user input so it's trusted as is
+func (qo *QueryOptsBuilder) AndExpr(expr squirrel.Sqlizer) *QueryOptsBuilder {
+	qo.Exprs = append(qo.Exprs, expr)
+	return qo
+}
+
 type OrBuilder struct {
 	conditions []Condition
 	qo         *QueryOptsBuilder
@@ -77,6 +96,61 @@ func (qo *QueryOptsBuilder) OrderBy(field, direction string) *QueryOptsBuilder {
 	return qo
 }
 
+// ConditionFields lists every field referenced by the and/or conditions
+func (qo *QueryOptsBuilder) ConditionFields() []string {
+	fields := make([]string, 0, len(qo.AndConditions))
+	for _, condition := range qo.AndConditions {
+		fields = append(fields, condition.Field)
+	}
+	for _, orGroup := range qo.OrGroups {
+		for _, condition := range orGroup {
+			fields = append(fields, condition.Field)
+		}
+	}
+	return fields
+}
+
+func (qo *QueryOptsBuilder) HasOrder(field string) bool {
+	for _, order := range qo.Orders {
+		if order.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// LastOrderDir returns the direction of the last order, used as the direction
+// of the tie-breaker in keyset pagination
+func (qo *QueryOptsBuilder) LastOrderDir() string {
+	if len(qo.Orders) == 0 {
+		return "asc"
+	}
+	return qo.Orders[len(qo.Orders)-1].dir
+}
+
+func (qo *QueryOptsBuilder) OrderFields() []string {
+	fields := make([]string, len(qo.Orders))
+	for i, order := range qo.Orders {
+		fields[i] = order.field
+	}
+	return fields
+}
+
+// OrderKeys identifies the active ordering as field:dir pairs, so a cursor is
+// only accepted with the same order_by it was created with
+func (qo *QueryOptsBuilder) OrderKeys() []string {
+	keys := make([]string, len(qo.Orders))
+	for i, order := range qo.Orders {
+		keys[i] = order.field + ":" + order.dir
+	}
+	return keys
+}
+
+func (qo *QueryOptsBuilder) After(cursor Cursor) *QueryOptsBuilder {
+	qo.CursorValue = &cursor
+	return qo
+}
+
 func (qo *QueryOptsBuilder) Limit(limit int) *QueryOptsBuilder {
 	qo.LimitValue = &limit
 	return qo
@@ -101,6 +175,14 @@ func QueryOptsToSquirrel(query squirrel.SelectBuilder, qo *QueryOptsBuilder) squ
 		query = query.Where(orSqlizers)
 	}
 
+	for _, expr := range qo.Exprs {
+		query = query.Where(expr)
+	}
+
+	if qo.CursorValue != nil {
+		query = query.Where(keysetToSquirrel(qo.Orders, *qo.CursorValue, false))
+	}
+
 	if qo.LimitValue != nil {
 		query = query.Limit(uint64(*qo.LimitValue))
 	}
@@ -109,17 +191,67 @@ func QueryOptsToSquirrel(query squirrel.SelectBuilder, qo *QueryOptsBuilder) squ
 		query = query.Offset(uint64(*qo.OffsetValue))
 	}
 
+	// a backward page is read in the reverse order, closest rows to the cursor
+	// first. Nulls are always the greatest values, as postgres sorts them by
+	// default, so the keyset comparisons agree with the order
+	backward := qo.CursorValue != nil && qo.CursorValue.Backward
 	for _, order := range qo.Orders {
-		if order.dir == "asc" {
-			query = query.OrderBy(order.field + " ASC")
+		if (order.dir == "asc") != backward {
+			query = query.OrderBy(order.field + " ASC NULLS LAST")
 		} else {
-			query = query.OrderBy(order.field + " DESC")
+			query = query.OrderBy(order.field + " DESC NULLS FIRST")
 		}
 	}
 
 	return query
 }
 
+// keysetToSquirrel builds the row comparison (a, b) > (x, y) as
+// a > x OR (a = x AND b > y), honoring the direction of each order. With
+// inclusive the row of the cursor itself matches too
+func keysetToSquirrel(orders []Order, cursor Cursor, inclusive bool) squirrel.Sqlizer {
+	keyset := squirrel.Or{}
+	for i, order := range orders {
+		condition := squirrel.And{}
+		for j := 0; j < i; j++ {
+			// squirrel turns a nil value into IS NULL
+			condition = append(condition, squirrel.Eq{orders[j].field: cursor.Values[j]})
+		}
+
+		comparison := keysetComparison(order.field, cursor.Values[i], (order.dir == "asc") != cursor.Backward)
+		if comparison == nil {
+			// nothing comes after a null, which is the greatest value
+			continue
+		}
+		keyset = append(keyset, append(condition, comparison))
+	}
+
+	if inclusive {
+		condition := squirrel.And{}
+		for i, order := range orders {
+			condition = append(condition, squirrel.Eq{order.field: cursor.Values[i]})
+		}
+		keyset = append(keyset, condition)
+	}
+
+	return keyset
+}
+
+// keysetComparison compares a field with a cursor value where null is greater
+// than any value, a plain > or < would never match a null
+func keysetComparison(field string, value any, greater bool) squirrel.Sqlizer {
+	switch {
+	case greater && value == nil:
+		return nil
+	case greater:
+		return squirrel.Or{squirrel.Gt{field: value}, squirrel.Eq{field: nil}}
+	case value == nil:
+		return squirrel.NotEq{field: nil}
+	default:
+		return squirrel.Lt{field: value}
+	}
+}
+
 func DeleteOptsToSquirrel(query squirrel.DeleteBuilder, qo *QueryOptsBuilder) squirrel.DeleteBuilder {
 	for _, andCondition := range qo.AndConditions {
 		query = query.Where(conditionToSquirrel(andCondition))
@@ -133,6 +265,10 @@ func DeleteOptsToSquirrel(query squirrel.DeleteBuilder, qo *QueryOptsBuilder) sq
 		query = query.Where(orSqlizers)
 	}
 
+	for _, expr := range qo.Exprs {
+		query = query.Where(expr)
+	}
+
 	if qo.LimitValue != nil {
 		query = query.Limit(uint64(*qo.LimitValue))
 	}
@@ -156,11 +292,11 @@ func conditionToSquirrel(condition Condition) squirrel.Sqlizer {
 		return squirrel.NotEq{condition.Field: condition.Value}
 	case "lt":
 		return squirrel.Lt{condition.Field: condition.Value}
-	case "lte":
+	case "lte", "le":
 		return squirrel.LtOrEq{condition.Field: condition.Value}
 	case "gt":
 		return squirrel.Gt{condition.Field: condition.Value}
-	case "gte":
+	case "gte", "ge":
 		return squirrel.GtOrEq{condition.Field: condition.Value}
 	case "like":
 		return squirrel.Like{fmt.Sprintf("upper(%s)", condition.Field): strings.ToUpper(fmt.Sprintf("%%%s%%", condition.Value))}
@@ -173,8 +309,33 @@ func ForCount(qo *QueryOptsBuilder) *QueryOptsBuilder {
 	return &QueryOptsBuilder{
 		AndConditions: qo.AndConditions,
 		OrGroups:      qo.OrGroups,
+		Exprs:         qo.Exprs,
 		Orders:        nil,
 		LimitValue:    nil,
 		OffsetValue:   nil,
 	}
 }
+
+// fromCursor is the count filter of the rows from a backward cursor on,
+// which are the ones after a backward page
+func fromCursor(qo *QueryOptsBuilder) *QueryOptsBuilder {
+	forward := *qo.CursorValue
+	forward.Backward = false
+
+	filter := ForCount(qo)
+	filter.Exprs = append(slices.Clone(qo.Exprs), keysetToSquirrel(qo.Orders, forward, true))
+	return filter
+}
+
+// Unpaginated keeps the conditions and the order of a query but drops its
+// limit, offset and cursor, for exports that read every matching row
+func Unpaginated(qo *QueryOptsBuilder) *QueryOptsBuilder {
+	return &QueryOptsBuilder{
+		AndConditions: qo.AndConditions,
+		OrGroups:      qo.OrGroups,
+		Exprs:         qo.Exprs,
+		Orders:        qo.Orders,
+		LimitValue:    nil,
+		OffsetValue:   nil,
+	}
+}
@@ -10,9 +10,11 @@ type Executer interface {
 }
 
 type QueryMeta struct {
-	NextPage   bool `json:"next_page"`
-	Page       int  `json:"page"`
-	PerPage    int  `json:"per_page"`
-	TotalItems int  `json:"total_items"`
-	TotalPages int  `json:"total_pages"`
+	NextPage   bool    `json:"next_page"`
+	Page       int     `json:"page"`
+	PerPage    int     `json:"per_page"`
+	TotalItems int     `json:"total_items"`
+	TotalPages int     `json:"total_pages"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
 }
@@ -0,0 +1 @@
+drop table budgets;
\ No newline at end of file
@@ -0,0 +1,12 @@
+create table budgets (
+    id text primary key,
+    user_id text not null references users(id),
+    category_id text not null references categories(id) on delete cascade,
+    period varchar(6), -- null é o valor padrão mensal da categoria
+    amount decimal(10,2) not null,
+    rollover boolean not null default false,
+    created_at timestamptz not null default now()
+);
+
+create unique index budgets_category_id_period_idx on budgets (category_id, period) where period is not null;
+create unique index budgets_category_id_default_idx on budgets (category_id) where period is null;
@@ -0,0 +1,3 @@
+drop table envelope_movements;
+
+alter table users drop column envelope_budgeting_since;
\ No newline at end of file
@@ -0,0 +1,12 @@
+alter table users add column envelope_budgeting_since varchar(6); -- null quando o modo envelope está desligado
+
+create table envelope_movements (
+    id text primary key,
+    user_id text not null references users(id),
+    period varchar(6) not null,
+    from_category_id text references categories(id) on delete cascade, -- null é o saldo a distribuir
+    to_category_id text references categories(id) on delete cascade,
+    amount decimal(10,2) not null check (amount > 0),
+    note varchar(400),
+    created_at timestamptz not null default now()
+);
@@ -0,0 +1 @@
+drop table recurring_transactions;
\ No newline at end of file
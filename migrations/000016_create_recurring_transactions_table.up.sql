@@ -0,0 +1,12 @@
+create table recurring_transactions (
+    id text primary key,
+    user_id text not null references users(id),
+    category_id text references categories(id) on delete set null,
+    name varchar(100) not null,
+    type varchar(100) not null,
+    amount decimal(10,2) not null,
+    day_of_month smallint not null check (day_of_month between 1 and 31),
+    start_period varchar(6) not null,
+    end_period varchar(6),
+    created_at timestamptz not null default now()
+);
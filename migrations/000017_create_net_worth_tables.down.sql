@@ -0,0 +1,3 @@
+drop table net_worth_valuations;
+
+drop table net_worth_items;
\ No newline at end of file
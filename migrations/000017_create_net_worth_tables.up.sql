@@ -0,0 +1,16 @@
+create table net_worth_items (
+    id text primary key,
+    user_id text not null references users(id),
+    name varchar(100) not null,
+    kind varchar(20) not null check (kind in ('asset', 'liability')),
+    created_at timestamptz not null default now()
+);
+
+create table net_worth_valuations (
+    id text primary key,
+    user_id text not null references users(id),
+    item_id text not null references net_worth_items(id) on delete cascade,
+    value decimal(12,2) not null check (value >= 0),
+    valued_at date not null,
+    created_at timestamptz not null default now()
+);
@@ -0,0 +1,5 @@
+drop index transactions_search_idx;
+
+drop text search configuration portuguese_unaccent;
+
+drop extension if exists unaccent;
@@ -0,0 +1,12 @@
+create extension if not exists unaccent;
+
+-- Configuração em português que ignora acentos, "farmacia" encontra "farmácia"
+create text search configuration portuguese_unaccent (copy = portuguese);
+
+alter text search configuration portuguese_unaccent
+    alter mapping for hword, hword_part, word
+    with unaccent, portuguese_stem;
+
+-- A expressão precisa ser idêntica à usada nas buscas para o índice ser usado
+create index transactions_search_idx on transactions
+using gin (to_tsvector('portuguese_unaccent', coalesce(name, '') || ' ' || coalesce(description, '')));
@@ -0,0 +1 @@
+drop table rules;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Regras aplicadas em ordem crescente de prioridade
+create table rules (
+    id text primary key,
+    user_id text not null references users(id),
+    name varchar(100) not null,
+    priority integer not null default 0,
+    conditions jsonb not null,
+    actions jsonb not null,
+    created_at timestamptz not null default now()
+);
+
+create index rules_user_id_priority_idx on rules (user_id, priority);
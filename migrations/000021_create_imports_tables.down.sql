@@ -0,0 +1,5 @@
+alter table transactions drop column import_batch_id;
+
+drop table import_batches;
+
+drop table import_profiles;
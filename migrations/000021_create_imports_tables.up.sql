@@ -0,0 +1,22 @@
+create table import_profiles (
+    id text primary key,
+    user_id text not null references users(id),
+    name varchar(100) not null,
+    mapping jsonb not null,
+    created_at timestamptz not null default now()
+);
+
+-- Cada importação é um lote, desfazer o lote apaga as transações criadas por ele
+create table import_batches (
+    id text primary key,
+    user_id text not null references users(id),
+    source varchar(20) not null,
+    file_name varchar(255),
+    created_count integer not null default 0,
+    failed_count integer not null default 0,
+    created_at timestamptz not null default now()
+);
+
+alter table transactions add column import_batch_id text references import_batches(id) on delete set null;
+
+create index transactions_import_batch_id_idx on transactions (import_batch_id);
@@ -0,0 +1,5 @@
+alter table import_batches drop column skipped_count;
+
+drop index transactions_user_id_external_id_idx;
+
+alter table transactions drop column external_id;
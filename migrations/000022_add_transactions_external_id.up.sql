@@ -0,0 +1,6 @@
+-- Identificador da transação na origem (ex.: FITID do OFX), evita duplicar ao reimportar um arquivo
+alter table transactions add column external_id varchar(255);
+
+create unique index transactions_user_id_external_id_idx on transactions (user_id, external_id) where external_id is not null;
+
+alter table import_batches add column skipped_count integer not null default 0;
@@ -0,0 +1,14 @@
+-- Itens de uma compra (ex.: produtos de uma NF-e), detalham a transação sem alterar seus lançamentos
+create table transaction_items (
+    id text primary key,
+    transaction_id text not null references transactions(id) on delete cascade,
+    position integer not null,
+    code varchar(60),
+    description varchar(120) not null,
+    quantity decimal(15,4) not null,
+    unit varchar(6),
+    unit_price decimal(21,10) not null,
+    amount decimal(10,2) not null
+);
+
+create index transaction_items_transaction_id_idx on transaction_items (transaction_id);
@@ -0,0 +1,13 @@
+-- Esquema de nomes das contas usado na exportação para ledger, hledger e beancount
+create table journal_settings (
+    user_id text primary key references users(id),
+    assets_root varchar(50) not null default 'Assets',
+    liabilities_root varchar(50) not null default 'Liabilities',
+    income_root varchar(50) not null default 'Income',
+    expenses_root varchar(50) not null default 'Expenses',
+    default_account varchar(100) not null default 'Checking', -- usada quando a transação não tem conta
+    uncategorized varchar(100) not null default 'Uncategorized', -- usada quando a transação não tem categoria
+    commodity varchar(24) not null default 'BRL',
+    accounts jsonb not null default '{}', -- conta da transação -> conta completa no diário
+    updated_at timestamptz not null default now()
+);
@@ -0,0 +1,4 @@
+drop table account_erasures;
+
+alter table users drop column deletion_scheduled_for;
+alter table users drop column deletion_requested_at;
@@ -0,0 +1,12 @@
+-- Exclusão da conta agendada, os dados só são apagados depois do prazo de carência
+alter table users add column deletion_requested_at timestamptz;
+alter table users add column deletion_scheduled_for timestamptz;
+
+-- Registro anônimo de cada conta apagada, guarda só o hash do id do usuário e quantos registros foram apagados
+create table account_erasures (
+    id text primary key,
+    subject_hash char(64) not null,
+    requested_at timestamptz not null,
+    erased_at timestamptz not null default now(),
+    erased_records jsonb not null
+);
@@ -0,0 +1 @@
+drop table calendar_feeds;
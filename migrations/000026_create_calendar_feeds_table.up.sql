@@ -0,0 +1,8 @@
+-- Feed iCalendar de cada usuário, a url secreta leva um token do qual só o hash é guardado
+create table calendar_feeds (
+    user_id text primary key references users(id),
+    token_hash char(64) not null unique,
+    reminders jsonb not null default '[{"days_before": 1, "time": "09:00"}]', -- lembretes de cada evento, dias antes e horário
+    created_at timestamptz not null default now(),
+    rotated_at timestamptz not null default now()
+);
@@ -0,0 +1,3 @@
+drop index categories_import_batch_id_idx;
+
+alter table categories drop column import_batch_id;
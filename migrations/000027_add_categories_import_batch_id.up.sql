@@ -0,0 +1,4 @@
+-- Categorias criadas por uma importação, desfazer o lote apaga as que nenhuma outra transação usa
+alter table categories add column import_batch_id text references import_batches(id) on delete set null;
+
+create index categories_import_batch_id_idx on categories (import_batch_id);
@@ -0,0 +1,3 @@
+drop index categories_user_id_idx;
+
+alter table categories drop constraint categories_user_id_fkey;
@@ -0,0 +1,16 @@
+-- Categorias sem usuário ficaram órfãs por falta de chave estrangeira, a migração falha
+-- listando quantas existem em vez de apagá-las, a limpeza deve ser feita e revisada à parte
+do $$
+declare
+    orphans integer;
+begin
+    select count(*) into orphans from categories where user_id not in (select id from users);
+    if orphans > 0 then
+        raise exception '% categories have a user_id with no user, remove or reassign them before adding categories_user_id_fkey', orphans;
+    end if;
+end
+$$;
+
+alter table categories add constraint categories_user_id_fkey foreign key (user_id) references users(id);
+
+create index categories_user_id_idx on categories (user_id);
@@ -0,0 +1 @@
+alter table recurring_transactions add column day_of_month smallint not null default 1 check (day_of_month between 1 and 31);
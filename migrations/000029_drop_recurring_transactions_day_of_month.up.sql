@@ -0,0 +1,2 @@
+-- A projeção é mensal, o dia do mês era exigido na criação mas nunca usado
+alter table recurring_transactions drop column day_of_month;
@@ -0,0 +1,3 @@
+drop index transactions_user_id_updated_at_idx;
+
+alter table transactions drop column updated_at;
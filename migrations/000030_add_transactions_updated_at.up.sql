@@ -0,0 +1,5 @@
+-- Momento da última escrita da transação, as sugestões de categoria comparam o maior deles
+-- com o do modelo em cache para saber se ele ainda vale
+alter table transactions add column updated_at timestamptz not null default now();
+
+create index transactions_user_id_updated_at_idx on transactions (user_id, updated_at);
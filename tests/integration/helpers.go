@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// TxDB is a database whose transactions begin and end without running
+// anything, for use cases that open a transaction around mocked repos. It
+// counts the commits and rollbacks that reach it
+type TxDB struct {
+	*sql.DB
+	Commits   int
+	Rollbacks int
+}
+
+type txConnector struct {
+	txdb *TxDB
+}
+
+func (c txConnector) Connect(context.Context) (driver.Conn, error) {
+	return txConn{c.txdb}, nil
+}
+
+func (c txConnector) Driver() driver.Driver {
+	return nil
+}
+
+type txConn struct {
+	txdb *TxDB
+}
+
+func (c txConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("queries of a TxDB go through the mocked repos")
+}
+
+func (c txConn) Close() error {
+	return nil
+}
+
+func (c txConn) Begin() (driver.Tx, error) {
+	return txConn{c.txdb}, nil
+}
+
+func (c txConn) Commit() error {
+	c.txdb.Commits++
+	return nil
+}
+
+func (c txConn) Rollback() error {
+	c.txdb.Rollbacks++
+	return nil
+}
+
+func SetupTxDB(t *testing.T) *TxDB {
+	txdb := &TxDB{}
+	txdb.DB = sql.OpenDB(txConnector{txdb})
+	t.Cleanup(func() { txdb.DB.Close() })
+	return txdb
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/boletos"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBoleto(t *testing.T) {
+	now := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should read a banking typed line", func(t *testing.T) {
+		boleto, err := boletos.ParseBoleto("34191.09008 00012.345674 89012.345677 1 10770000015075", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, boletos.KindBank, boleto.Kind)
+		assert.Equal(t, "34191107700000150751090000012345678901234567", boleto.Barcode)
+		assert.Equal(t, "34191.09008 00012.345674 89012.345677 1 10770000015075", boleto.TypedLine)
+		assert.Equal(t, "341", *boleto.BankCode)
+		assert.Equal(t, "Itaú", *boleto.BankName)
+		assert.Equal(t, 150.75, *boleto.Amount)
+		// factor 1077 after the restart of 2025-02-22
+		assert.Equal(t, "2025-05-10", *boleto.DueDate)
+	})
+
+	t.Run("should read a banking barcode", func(t *testing.T) {
+		boleto, err := boletos.ParseBoleto("34191107700000150751090000012345678901234567", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "34191.09008 00012.345674 89012.345677 1 10770000015075", boleto.TypedLine)
+	})
+
+	t.Run("should read due factors of the first cycle", func(t *testing.T) {
+		boleto, err := boletos.ParseBoleto("34191107700000150751090000012345678901234567", time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2000-09-18", *boleto.DueDate)
+	})
+
+	t.Run("should read a utility typed line", func(t *testing.T) {
+		boleto, err := boletos.ParseBoleto("83610000001-4 23450048202-6 50510123456-3 78901234567-2", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, boletos.KindUtility, boleto.Kind)
+		assert.Equal(t, "83610000001234500482025051012345678901234567", boleto.Barcode)
+		assert.Equal(t, "Energia elétrica e gás", *boleto.Segment)
+		assert.Nil(t, boleto.BankCode)
+		assert.Equal(t, 123.45, *boleto.Amount)
+		assert.Equal(t, "2025-05-10", *boleto.DueDate)
+	})
+
+	t.Run("should read a utility barcode checked by mod 11", func(t *testing.T) {
+		boleto, err := boletos.ParseBoleto("84880000000999000019999999900000000000000000", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 99.9, *boleto.Amount)
+		assert.Nil(t, boleto.DueDate)
+	})
+
+	t.Run("should reject invalid check digits", func(t *testing.T) {
+		_, err := boletos.ParseBoleto("34191.09008 00012.345675 89012.345677 1 10770000015075", now)
+		assert.EqualError(t, err, "check digit of field 2 is invalid")
+
+		_, err = boletos.ParseBoleto("34191107700000150751090000012345678901234568", now)
+		assert.EqualError(t, err, "check digit of the barcode is invalid")
+
+		_, err = boletos.ParseBoleto("83610000001-4 23450048202-6 50510123456-4 78901234567-2", now)
+		assert.EqualError(t, err, "check digit of block 3 is invalid")
+
+		_, err = boletos.ParseBoleto("1234", now)
+		assert.ErrorIs(t, err, boletos.ErrInvalidCode)
+	})
+}
+
+func TestPrefill(t *testing.T) {
+	boleto, _ := boletos.ParseBoleto("34191107700000150751090000012345678901234567", time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC))
+
+	request, missing := boletos.Prefill(boleto)
+
+	assert.Empty(t, missing)
+	assert.Equal(t, "Boleto Itaú", request.Name)
+	assert.Equal(t, constants.SimpleExpense, request.Type)
+	assert.Equal(t, []string{"boleto"}, request.Tags)
+	assert.Equal(t, -150.75, request.Entries[0].Amount)
+	assert.Equal(t, "2025-05-10", request.Entries[0].ReferenceDate)
+
+	boleto.Amount = nil
+	request, missing = boletos.Prefill(boleto)
+
+	assert.Equal(t, []string{"amount"}, missing)
+	assert.Empty(t, request.Entries)
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/budgets"
+	budgetsMocks "github.com/felipe1496/open-wallet/internal/resources/budgets/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	categoriesMocks "github.com/felipe1496/open-wallet/internal/resources/categories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// budgetsProgress runs ListProgress of the food category with its budgets and
+// the spending of each period, spending is positive like in the progress
+func budgetsProgress(t *testing.T, budgetRows []budgets.Budget, spent map[string]float64, period string) []budgets.BudgetProgress {
+	budgetsRepo := new(budgetsMocks.MockBudgetsRepo)
+	categoriesRepo := new(categoriesMocks.MockCategoriesRepo)
+	uc := budgets.NewBudgetsUseCase(budgetsRepo, categories.NewCategoriesUseCase(categoriesRepo, nil), nil)
+
+	amounts := make([]categories.CategoryAmountPerPeriod, 0, len(spent))
+	for amountPeriod, amount := range spent {
+		amounts = append(amounts, categories.CategoryAmountPerPeriod{ID: "food", Period: amountPeriod, TotalAmount: -amount})
+	}
+
+	budgetsRepo.On("List", mock.Anything, mock.Anything).Return(budgetRows, nil)
+	categoriesRepo.On("ListCategoryAmountPerPeriod", mock.Anything, mock.Anything).Return(amounts, nil)
+	categoriesRepo.On("List", mock.Anything, mock.Anything).
+		Return([]categories.Category{{ID: "food", Name: "Alimentação", Color: "#22c55e"}}, nil)
+
+	progress, err := uc.ListProgress("user-1", period)
+	assert.NoError(t, err)
+	return progress
+}
+
+func monthlyBudget(amount float64, rollover bool, since string) budgets.Budget {
+	createdAt, _ := time.Parse("200601", since)
+	return budgets.Budget{ID: "monthly", CategoryID: "food", Amount: amount, Rollover: rollover, CreatedAt: createdAt}
+}
+
+func periodBudget(period string, amount float64, rollover bool) budgets.Budget {
+	return budgets.Budget{ID: "budget-" + period, CategoryID: "food", Period: &period, Amount: amount, Rollover: rollover}
+}
+
+func TestBudgetsUseCase_ListProgress(t *testing.T) {
+	t.Run("should reject an invalid period", func(t *testing.T) {
+		uc := budgets.NewBudgetsUseCase(new(budgetsMocks.MockBudgetsRepo), nil, nil)
+
+		_, err := uc.ListProgress("user-1", "2025-03")
+
+		assert.ErrorIs(t, err, budgets.ErrInvalidPeriod)
+	})
+
+	t.Run("should carry what was left unspent into the next period", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202501")},
+			map[string]float64{"202501": 300, "202502": 100}, "202502")
+
+		assert.Len(t, progress, 1)
+		assert.Equal(t, 500.0, progress[0].Planned)
+		assert.Equal(t, 200.0, progress[0].RolledOver)
+		assert.Equal(t, 700.0, progress[0].Available)
+		assert.Equal(t, 100.0, progress[0].Spent)
+		assert.Equal(t, 600.0, progress[0].Remaining)
+		assert.Equal(t, 14.29, progress[0].PercentageUsed)
+	})
+
+	t.Run("should carry overspending into the next period", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202501")},
+			map[string]float64{"202501": 650, "202502": 200}, "202502")
+
+		assert.Equal(t, -150.0, progress[0].RolledOver)
+		assert.Equal(t, 350.0, progress[0].Available)
+		assert.Equal(t, 150.0, progress[0].Remaining)
+	})
+
+	t.Run("should accumulate every previous period", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202501")},
+			map[string]float64{"202501": 600, "202502": 300, "202503": 150}, "202503")
+
+		assert.Equal(t, 100.0, progress[0].RolledOver)
+		assert.Equal(t, 600.0, progress[0].Available)
+		assert.Equal(t, 25.0, progress[0].PercentageUsed)
+	})
+
+	t.Run("should not carry anything from before the first budget", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202502")},
+			map[string]float64{"202501": 900, "202502": 100}, "202502")
+
+		assert.Equal(t, 0.0, progress[0].RolledOver)
+		assert.Equal(t, 500.0, progress[0].Available)
+	})
+
+	t.Run("should use the budget of the period over the monthly one", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202501"), periodBudget("202502", 800, true)},
+			map[string]float64{"202501": 400, "202502": 900, "202503": 0}, "202503")
+
+		// 100 saved in January, 100 overspent in February
+		assert.Equal(t, "monthly", progress[0].BudgetID)
+		assert.Equal(t, 0.0, progress[0].RolledOver)
+	})
+
+	t.Run("should restart the rollover after a period with no budget", func(t *testing.T) {
+		budgetRows := []budgets.Budget{periodBudget("202501", 500, true), periodBudget("202503", 400, true)}
+
+		progress := budgetsProgress(t, budgetRows, map[string]float64{"202501": 100, "202502": 50, "202503": 100}, "202503")
+
+		assert.Equal(t, 0.0, progress[0].RolledOver)
+		assert.Equal(t, 400.0, progress[0].Available)
+		assert.Equal(t, 300.0, progress[0].Remaining)
+	})
+
+	t.Run("should restart the rollover after a budget without it", func(t *testing.T) {
+		budgetRows := []budgets.Budget{periodBudget("202501", 500, true), periodBudget("202502", 500, false), periodBudget("202503", 500, true)}
+
+		progress := budgetsProgress(t, budgetRows, map[string]float64{"202501": 100, "202502": 100, "202503": 0}, "202503")
+
+		assert.Equal(t, 0.0, progress[0].RolledOver)
+	})
+
+	t.Run("should not apply the monthly budget before it was created", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{monthlyBudget(500, true, "202503")},
+			map[string]float64{"202502": 100}, "202502")
+
+		assert.Empty(t, progress)
+	})
+
+	t.Run("should restart the rollover until the monthly budget was created", func(t *testing.T) {
+		budgetRows := []budgets.Budget{periodBudget("202501", 500, true), monthlyBudget(500, true, "202503")}
+
+		progress := budgetsProgress(t, budgetRows, map[string]float64{"202501": 100, "202502": 50, "202503": 100}, "202503")
+
+		assert.Equal(t, "monthly", progress[0].BudgetID)
+		assert.Equal(t, 0.0, progress[0].RolledOver)
+		assert.Equal(t, 500.0, progress[0].Available)
+	})
+
+	t.Run("should leave out a category with no budget for the period", func(t *testing.T) {
+		progress := budgetsProgress(t, []budgets.Budget{periodBudget("202501", 500, true)},
+			map[string]float64{"202501": 100}, "202502")
+
+		assert.Empty(t, progress)
+	})
+}
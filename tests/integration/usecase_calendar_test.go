@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/calendar"
+	calendarMocks "github.com/felipe1496/open-wallet/internal/resources/calendar/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	transactionsMocks "github.com/felipe1496/open-wallet/internal/resources/transactions/mocks"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var calendarEntries = []transactions.ViewEntry{
+	{
+		ID:                "entry-1",
+		Name:              "Notebook",
+		Amount:            -1234.5,
+		Type:              constants.Installment,
+		ReferenceDate:     "2025-02-10",
+		Installment:       2,
+		TotalInstallments: 10,
+		CategoryName:      strPtr("Eletrônicos"),
+		Payee:             strPtr("Loja Tech, Cia; Ltda"),
+		Tags:              []string{},
+	},
+	{
+		ID:                "entry-2",
+		Name:              "Conta de luz",
+		Amount:            -89.9,
+		Type:              constants.SimpleExpense,
+		ReferenceDate:     "2025-02-28",
+		Installment:       1,
+		TotalInstallments: 1,
+		Tags:              []string{},
+	},
+}
+
+func TestWriteFeed(t *testing.T) {
+	stamp := time.Date(2025, 2, 1, 12, 30, 0, 0, time.UTC)
+	reminders := []calendar.Reminder{{DaysBefore: 1, Time: "09:00"}, {DaysBefore: 0, Time: "08:30"}}
+	data := string(calendar.WriteFeed(calendarEntries, reminders, stamp))
+
+	t.Run("should end every line with CRLF", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(data, "END:VCALENDAR\r\n"))
+		assert.NotContains(t, strings.ReplaceAll(data, "\r\n", ""), "\n")
+	})
+
+	t.Run("should write an all-day event per entry with amount and installment", func(t *testing.T) {
+		assert.Equal(t, 2, strings.Count(data, "BEGIN:VEVENT\r\n"))
+		assert.Contains(t, data, "UID:entry-1@open-wallet\r\nDTSTAMP:20250201T123000Z\r\nDTSTART;VALUE=DATE:20250210\r\nDTEND;VALUE=DATE:20250211\r\n")
+		assert.Contains(t, data, "SUMMARY:Notebook · R$ 1.234\\,50 (2/10)\r\n")
+		assert.Contains(t, data, "SUMMARY:Conta de luz · R$ 89\\,90\r\n")
+		assert.Contains(t, data, "DESCRIPTION:Category: Eletrônicos\\nPayee: Loja Tech\\, Cia\\; Ltda\r\n")
+	})
+
+	t.Run("should add the reminders to every event", func(t *testing.T) {
+		assert.Equal(t, 4, strings.Count(data, "BEGIN:VALARM\r\nACTION:DISPLAY\r\n"))
+		assert.Equal(t, 2, strings.Count(data, "TRIGGER:-PT15H\r\n"))
+		assert.Equal(t, 2, strings.Count(data, "TRIGGER:PT8H30M\r\n"))
+	})
+
+	t.Run("should fold lines longer than 75 octets", func(t *testing.T) {
+		long := calendarEntries[1]
+		long.Name = strings.Repeat("Mensalidade da academia ", 6)
+		folded := string(calendar.WriteFeed([]transactions.ViewEntry{long}, nil, stamp))
+
+		for _, line := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+		}
+		unfolded := strings.ReplaceAll(folded, "\r\n ", "")
+		assert.Contains(t, unfolded, "SUMMARY:"+long.Name+" · R$ 89\\,90\r\n")
+		assert.NotContains(t, folded, "VALARM")
+	})
+}
+
+func TestValidateReminders(t *testing.T) {
+	assert.NoError(t, calendar.ValidateReminders([]calendar.Reminder{{DaysBefore: 3, Time: "23:59"}}))
+
+	for _, value := range []string{"9:00", "24:00", "09:60", "09h00", ""} {
+		t.Run("should reject "+value, func(t *testing.T) {
+			err := calendar.ValidateReminders([]calendar.Reminder{{DaysBefore: 1, Time: value}})
+			assert.ErrorIs(t, err, calendar.ErrInvalidReminderTime)
+		})
+	}
+}
+
+func TestTokenHash(t *testing.T) {
+	hash := calendar.TokenHash("token")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, calendar.TokenHash("token"))
+	assert.NotEqual(t, hash, calendar.TokenHash("other"))
+}
+
+func TestRender(t *testing.T) {
+	t.Run("should list the expenses up to the horizon of the feed", func(t *testing.T) {
+		calendarRepo := new(calendarMocks.MockCalendarRepo)
+		transactionsRepo := new(transactionsMocks.MockTransactionsRepo)
+		uc := calendar.NewCalendarUseCase(calendarRepo, transactions.NewTransactionsUseCase(transactionsRepo, nil, nil, nil), nil)
+
+		var filter *utils.QueryOptsBuilder
+		calendarRepo.On("GetFeedByTokenHash", mock.Anything, calendar.TokenHash("token")).
+			Return(calendar.Feed{UserID: "user-1", Reminders: []calendar.Reminder{}}, nil)
+		transactionsRepo.On("ListViewEntries", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { filter = args.Get(1).(*utils.QueryOptsBuilder) }).
+			Return(calendarEntries, nil)
+
+		data, err := uc.Render("token", time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "BEGIN:VEVENT"))
+
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("*").From("v_entries"), filter).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM v_entries WHERE user_id = ? AND amount < ? AND reference_date >= ? AND reference_date <= ? ORDER BY reference_date ASC NULLS LAST, id ASC NULLS LAST LIMIT 1000", sql)
+		assert.Equal(t, []any{"user-1", 0, "2025-02-01", "2026-02-01"}, args)
+	})
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	categoriesMocks "github.com/felipe1496/open-wallet/internal/resources/categories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuggestFromHistory(t *testing.T) {
+	history := []categories.CategorizedName{
+		{CategoryID: "health", Name: "Drogaria São Paulo"},
+		{CategoryID: "health", Name: "Farmácia Pague Menos"},
+		{CategoryID: "health", Name: "DROGARIA RAIA 12/03"},
+		{CategoryID: "food", Name: "Padaria São Jorge"},
+		{CategoryID: "food", Name: "Mercado Extra"},
+		{CategoryID: "transport", Name: "Uber *trip"},
+	}
+
+	t.Run("should rank the category whose names share more tokens", func(t *testing.T) {
+		suggestions := categories.SuggestFromHistory(history, "Drogaria Araujo")
+
+		assert.Equal(t, "health", suggestions[0].ID)
+		assert.Greater(t, suggestions[0].Confidence, 0.5)
+		assert.Len(t, suggestions, 3)
+	})
+
+	t.Run("should ignore accents and case", func(t *testing.T) {
+		suggestions := categories.SuggestFromHistory(history, "FARMACIA")
+
+		assert.Equal(t, "health", suggestions[0].ID)
+	})
+
+	t.Run("should suggest nothing for unseen names", func(t *testing.T) {
+		assert.Empty(t, categories.SuggestFromHistory(history, "Netflix 2025"))
+		assert.Empty(t, categories.SuggestFromHistory(nil, "Uber"))
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	userCategories := []categories.Category{
+		{ID: "health", Name: "Saúde", Color: "#ef4444"},
+		{ID: "food", Name: "Alimentação", Color: "#22c55e"},
+	}
+
+	// models are cached per user for the whole process, so each case uses its own user
+	setup := func(history []categories.CategorizedName) (categories.CategoriesUseCase, *categoriesMocks.MockCategoriesRepo) {
+		repo := new(categoriesMocks.MockCategoriesRepo)
+		repo.On("GetCategorizedGeneration", mock.Anything, mock.Anything).Return(categories.CategorizedGeneration{Count: len(history)}, nil)
+		repo.On("ListCategorizedNames", mock.Anything, mock.Anything, mock.Anything).Return(history, nil)
+		repo.On("List", mock.Anything, mock.Anything).Return(userCategories, nil)
+		return categories.NewCategoriesUseCase(repo, nil), repo
+	}
+
+	t.Run("should train the model once and reuse it", func(t *testing.T) {
+		uc, repo := setup([]categories.CategorizedName{{CategoryID: "health", Name: "Drogaria Raia"}})
+
+		for range 3 {
+			suggestions, err := uc.Suggest("suggest-cache", "Drogaria", 3)
+			assert.NoError(t, err)
+			assert.Equal(t, "Saúde", suggestions[0].Name)
+		}
+		repo.AssertNumberOfCalls(t, "ListCategorizedNames", 1)
+	})
+
+	t.Run("should retrain when the history changed in the database", func(t *testing.T) {
+		repo := new(categoriesMocks.MockCategoriesRepo)
+		uc := categories.NewCategoriesUseCase(repo, nil)
+		trained := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		renamed := trained.Add(time.Minute)
+
+		repo.On("GetCategorizedGeneration", mock.Anything, "suggest-changed").
+			Return(categories.CategorizedGeneration{Count: 1, LastUpdated: &trained}, nil).Twice()
+		repo.On("GetCategorizedGeneration", mock.Anything, "suggest-changed").
+			Return(categories.CategorizedGeneration{Count: 1, LastUpdated: &renamed}, nil)
+		repo.On("ListCategorizedNames", mock.Anything, "suggest-changed", mock.Anything).
+			Return([]categories.CategorizedName{{CategoryID: "health", Name: "Drogaria Raia"}}, nil).Once()
+		repo.On("ListCategorizedNames", mock.Anything, "suggest-changed", mock.Anything).
+			Return([]categories.CategorizedName{{CategoryID: "food", Name: "Padaria Raia"}}, nil)
+		repo.On("List", mock.Anything, mock.Anything).Return(userCategories, nil)
+
+		for range 2 {
+			suggestions, err := uc.Suggest("suggest-changed", "Raia", 3)
+			assert.NoError(t, err)
+			assert.Equal(t, "health", suggestions[0].ID)
+		}
+
+		// another instance renamed the transaction
+		suggestions, err := uc.Suggest("suggest-changed", "Raia", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "food", suggestions[0].ID)
+		repo.AssertNumberOfCalls(t, "ListCategorizedNames", 2)
+	})
+
+	t.Run("should drop the least recently used model past the cap", func(t *testing.T) {
+		uc, repo := setup([]categories.CategorizedName{{CategoryID: "health", Name: "Drogaria Raia"}})
+
+		_, err := uc.Suggest("suggest-evicted", "Drogaria", 3)
+		assert.NoError(t, err)
+		for i := range 1000 {
+			_, err := uc.Suggest(fmt.Sprintf("suggest-evict-%d", i), "Drogaria", 3)
+			assert.NoError(t, err)
+		}
+
+		_, err = uc.Suggest("suggest-evicted", "Drogaria", 3)
+		assert.NoError(t, err)
+		_, err = uc.Suggest("suggest-evict-999", "Drogaria", 3)
+		assert.NoError(t, err)
+
+		repo.AssertNumberOfCalls(t, "ListCategorizedNames", 1002)
+	})
+
+	t.Run("should keep a model per user", func(t *testing.T) {
+		uc, repo := setup([]categories.CategorizedName{{CategoryID: "food", Name: "Padaria"}})
+
+		_, err := uc.Suggest("suggest-user-1", "Padaria", 3)
+		assert.NoError(t, err)
+		_, err = uc.Suggest("suggest-user-2", "Padaria", 3)
+		assert.NoError(t, err)
+
+		repo.AssertCalled(t, "ListCategorizedNames", mock.Anything, "suggest-user-1", mock.Anything)
+		repo.AssertCalled(t, "ListCategorizedNames", mock.Anything, "suggest-user-2", mock.Anything)
+	})
+
+	t.Run("should skip categories deleted after the model was trained", func(t *testing.T) {
+		uc, _ := setup([]categories.CategorizedName{
+			{CategoryID: "deleted", Name: "Drogaria Raia"},
+			{CategoryID: "deleted", Name: "Drogaria Pacheco"},
+			{CategoryID: "health", Name: "Drogaria Araujo"},
+			{CategoryID: "food", Name: "Padaria Drogaria"},
+		})
+
+		suggestions, err := uc.Suggest("suggest-deleted", "Drogaria", 2)
+		assert.NoError(t, err)
+		assert.Len(t, suggestions, 2)
+		for _, suggestion := range suggestions {
+			assert.NotEqual(t, "deleted", suggestion.ID)
+			assert.NotEmpty(t, suggestion.Name)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	categoriesMocks "github.com/felipe1496/open-wallet/internal/resources/categories/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBuildCategoryTrends(t *testing.T) {
+	amount := func(period string, total float64) categories.CategoryAmountPerPeriod {
+		return categories.CategoryAmountPerPeriod{ID: "food", Name: "Food", Period: period, TotalAmount: total}
+	}
+
+	amounts := []categories.CategoryAmountPerPeriod{
+		amount("202401", -100),
+		amount("202410", -200),
+		amount("202411", -300),
+		amount("202412", -400),
+		amount("202501", -450),
+	}
+
+	t.Run("should compare with the previous month and year", func(t *testing.T) {
+		trends := categories.BuildCategoryTrends(amounts, "202401", "202501", "202501", 3)
+
+		assert.Len(t, trends, 1)
+		period := trends[0].Periods[0]
+		assert.Equal(t, 450.0, period.Spent)
+		assert.Equal(t, 50.0, *period.MoMChange)
+		assert.Equal(t, 12.5, *period.MoMPercentage)
+		assert.Equal(t, 350.0, *period.YoYChange)
+		assert.Equal(t, 350.0, *period.YoYPercentage)
+	})
+
+	t.Run("should average the periods before and measure the deviation", func(t *testing.T) {
+		trends := categories.BuildCategoryTrends(amounts, "202401", "202501", "202501", 3)
+
+		period := trends[0].Periods[0]
+		assert.Equal(t, 300.0, period.RollingAverage3)
+		assert.Equal(t, 150.0, period.Deviation)
+		assert.Equal(t, 50.0, *period.DeviationPercentage)
+		assert.Equal(t, 1000.0/12, period.RollingAverage12)
+	})
+
+	t.Run("should not compare before the first period with entries", func(t *testing.T) {
+		trends := categories.BuildCategoryTrends(amounts, "202401", "202401", "202402", 6)
+
+		first := trends[0].Periods[0]
+		assert.Nil(t, first.MoMChange)
+		assert.Nil(t, first.YoYChange)
+		assert.Equal(t, 0.0, first.RollingAverage6)
+		assert.Nil(t, first.DeviationPercentage)
+
+		second := trends[0].Periods[1]
+		assert.Equal(t, 0.0, second.Spent)
+		assert.Equal(t, 100.0, second.RollingAverage6)
+		assert.Equal(t, -100.0, *second.MoMPercentage)
+		assert.Equal(t, -100.0, *second.MoMChange)
+	})
+}
+
+// filterSQL is the where clause a filter builds, to match the filters the repo gets
+func filterSQL(filter *utils.QueryOptsBuilder) (string, []any) {
+	sql, args, _ := utils.QueryOptsToSquirrel(squirrel.Select("*").From("t"), filter).ToSql()
+	return sql, args
+}
+
+func TestCategoriesUseCase_ListCategoryTrends(t *testing.T) {
+	t.Run("should reject a window that isn't 3, 6 or 12", func(t *testing.T) {
+		uc := categories.NewCategoriesUseCase(new(categoriesMocks.MockCategoriesRepo), nil)
+
+		_, err := uc.ListCategoryTrends("user-1", "202501", "202503", 0)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should validate the range like the other period listings", func(t *testing.T) {
+		uc := categories.NewCategoriesUseCase(new(categoriesMocks.MockCategoriesRepo), nil)
+
+		_, err := uc.ListCategoryTrends("user-1", "202503", "202501", 3)
+		assert.ErrorIs(t, err, summary.ErrInvalidPeriodRange)
+
+		_, err = uc.ListCategoryTrends("user-1", "200001", "202501", 3)
+		assert.ErrorIs(t, err, summary.ErrPeriodRangeTooLong)
+	})
+
+	t.Run("should only read the year before the range", func(t *testing.T) {
+		repo := new(categoriesMocks.MockCategoriesRepo)
+		uc := categories.NewCategoriesUseCase(repo, nil)
+
+		repo.On("FirstCategoryAmountPeriod", mock.Anything, mock.Anything).Return(strPtr("202001"), nil)
+		repo.On("ListCategoryAmountPerPeriod", mock.Anything, mock.MatchedBy(func(filter *utils.QueryOptsBuilder) bool {
+			_, args := filterSQL(filter)
+			return assert.ObjectsAreEqual([]any{"user-1", "202401", "202501"}, args)
+		})).Return([]categories.CategoryAmountPerPeriod{
+			{ID: "food", Name: "Food", Period: "202412", TotalAmount: -300},
+		}, nil)
+
+		trends, err := uc.ListCategoryTrends("user-1", "202501", "202501", 3)
+
+		assert.NoError(t, err)
+		assert.Len(t, trends, 1)
+		// entries start in 2020, so the months read before December count as zero
+		assert.Equal(t, -300.0, *trends[0].Periods[0].MoMChange)
+		assert.Equal(t, 100.0, trends[0].Periods[0].RollingAverage3)
+	})
+
+	t.Run("should list nothing without any entry", func(t *testing.T) {
+		repo := new(categoriesMocks.MockCategoriesRepo)
+		uc := categories.NewCategoriesUseCase(repo, nil)
+
+		repo.On("FirstCategoryAmountPeriod", mock.Anything, mock.Anything).Return((*string)(nil), nil)
+
+		trends, err := uc.ListCategoryTrends("user-1", "202501", "202501", 3)
+
+		assert.NoError(t, err)
+		assert.Empty(t, trends)
+		repo.AssertNotCalled(t, "ListCategoryAmountPerPeriod")
+	})
+}
@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/categories"
+	categoriesMocks "github.com/felipe1496/open-wallet/internal/resources/categories/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/envelopes"
+	envelopesMocks "github.com/felipe1496/open-wallet/internal/resources/envelopes/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/users"
+	usersMocks "github.com/felipe1496/open-wallet/internal/resources/users/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type envelopesMocksSet struct {
+	envelopesRepo  *envelopesMocks.MockEnvelopesRepo
+	categoriesRepo *categoriesMocks.MockCategoriesRepo
+	db             *TxDB
+	uc             envelopes.EnvelopesUseCase
+}
+
+// newEnvelopesUseCase has envelope budgeting enabled since 202501, the user
+// has the food and rent categories
+func newEnvelopesUseCase(t *testing.T) envelopesMocksSet {
+	set := envelopesMocksSet{
+		envelopesRepo:  new(envelopesMocks.MockEnvelopesRepo),
+		categoriesRepo: new(categoriesMocks.MockCategoriesRepo),
+		db:             SetupTxDB(t),
+	}
+	set.envelopesRepo.On("LockUser", mock.Anything, "user-1").Return(nil)
+	usersRepo := new(usersMocks.MockUsersRepo)
+	usersRepo.On("ListUsers", users.UserFilter{ID: "user-1"}).
+		Return([]users.User{{ID: "user-1", EnvelopeBudgetingSince: strPtr("202501")}}, nil)
+	set.categoriesRepo.On("Count", mock.Anything, mock.Anything).Return(1, nil)
+	set.categoriesRepo.On("List", mock.Anything, mock.Anything).Return([]categories.Category{
+		{ID: "food", Name: "Alimentação"},
+		{ID: "rent", Name: "Aluguel"},
+	}, nil)
+
+	set.uc = envelopes.NewEnvelopesUseCase(set.envelopesRepo,
+		categories.NewCategoriesUseCase(set.categoriesRepo, nil),
+		users.NewUsersUseCase(usersRepo),
+		set.db.DB)
+	return set
+}
+
+// withAmounts has the sums of two periods: 5000 of income, 1500 assigned to
+// rent and 800 to food in January, 300 moved from rent to food in February,
+// food spent 1200 and rent 1000
+func (m envelopesMocksSet) withAmounts() {
+	m.withSums([]envelopes.EnvelopeAmount{
+		{CategoryID: nil, Period: "202501", Amount: -2300},
+		{CategoryID: strPtr("rent"), Period: "202501", Amount: 1500},
+		{CategoryID: strPtr("food"), Period: "202501", Amount: 800},
+		{CategoryID: strPtr("rent"), Period: "202502", Amount: -300},
+		{CategoryID: strPtr("food"), Period: "202502", Amount: 300},
+	}, []envelopes.EnvelopeAmount{
+		{CategoryID: nil, Period: "202501", Amount: 5000},
+		{CategoryID: strPtr("food"), Period: "202501", Amount: -700},
+		{CategoryID: strPtr("food"), Period: "202502", Amount: -500},
+		{CategoryID: strPtr("rent"), Period: "202501", Amount: -1000},
+	})
+}
+
+// withSums has the repo sum the given movements and activity from 202501 on
+func (m envelopesMocksSet) withSums(movements []envelopes.EnvelopeAmount, activity []envelopes.EnvelopeAmount) {
+	m.envelopesRepo.On("SumMovements", mock.Anything, "user-1", "202501", mock.Anything).Return(movements, nil)
+	m.envelopesRepo.On("SumActivity", mock.Anything, "user-1", "202501", mock.Anything).Return(activity, nil)
+}
+
+func TestEnvelopesUseCase_ListEnvelopes(t *testing.T) {
+	m := newEnvelopesUseCase(t)
+	m.withAmounts()
+
+	t.Run("should run balances across periods and keep assigned and activity to the period", func(t *testing.T) {
+		overview, err := m.uc.ListEnvelopes("user-1", "202502")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2700.0, overview.AvailableToAssign)
+
+		food := overview.Envelopes[0]
+		assert.Equal(t, "food", food.CategoryID)
+		assert.Equal(t, 300.0, food.Assigned)
+		assert.Equal(t, -500.0, food.Activity)
+		assert.Equal(t, -100.0, food.Balance)
+		assert.True(t, food.Overspent)
+
+		rent := overview.Envelopes[1]
+		assert.Equal(t, -300.0, rent.Assigned)
+		assert.Equal(t, 0.0, rent.Activity)
+		assert.Equal(t, 200.0, rent.Balance)
+		assert.False(t, rent.Overspent)
+	})
+
+	t.Run("should report the overspent envelopes", func(t *testing.T) {
+		report, err := m.uc.ListOverspending("user-1", "202502")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100.0, report.TotalOverspent)
+		assert.Len(t, report.Envelopes, 1)
+		assert.Equal(t, "food", report.Envelopes[0].CategoryID)
+	})
+
+	t.Run("should reject a period before envelope budgeting", func(t *testing.T) {
+		_, err := m.uc.ListEnvelopes("user-1", "202412")
+
+		assert.ErrorIs(t, err, envelopes.ErrPeriodBeforeEnvelopeMode)
+	})
+}
+
+func TestEnvelopesUseCase_Assign(t *testing.T) {
+	t.Run("should assign up to the available to assign", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		m.withAmounts()
+		m.envelopesRepo.On("CreateMovement", mock.Anything, mock.Anything).Return(envelopes.EnvelopeMovement{ID: "movement-1"}, nil)
+
+		movement, err := m.uc.Assign(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202502", ToCategoryID: strPtr("food"), Amount: 2700})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "movement-1", movement.ID)
+		m.envelopesRepo.AssertCalled(t, "LockUser", mock.Anything, "user-1")
+		m.envelopesRepo.AssertCalled(t, "CreateMovement", mock.Anything, mock.MatchedBy(func(payload envelopes.CreateMovementDTO) bool {
+			return payload.FromCategoryID == nil && *payload.ToCategoryID == "food"
+		}))
+		assert.Equal(t, 1, m.db.Commits)
+	})
+
+	t.Run("should not assign more than is available", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		m.withAmounts()
+
+		_, err := m.uc.Assign(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202502", ToCategoryID: strPtr("food"), Amount: 2700.01})
+
+		assert.ErrorIs(t, err, envelopes.ErrNotEnoughToAssign)
+		m.envelopesRepo.AssertNotCalled(t, "CreateMovement")
+		assert.Equal(t, 0, m.db.Commits)
+		assert.Equal(t, 1, m.db.Rollbacks)
+	})
+
+	t.Run("should not assign in a period what a later one already assigned", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		// 1000 of income in January, 800 of it assigned to rent in February
+		m.withSums([]envelopes.EnvelopeAmount{
+			{CategoryID: nil, Period: "202502", Amount: -800},
+			{CategoryID: strPtr("rent"), Period: "202502", Amount: 800},
+		}, []envelopes.EnvelopeAmount{
+			{CategoryID: nil, Period: "202501", Amount: 1000},
+		})
+		m.envelopesRepo.On("CreateMovement", mock.Anything, mock.Anything).Return(envelopes.EnvelopeMovement{ID: "movement-1"}, nil)
+
+		_, err := m.uc.Assign(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202501", ToCategoryID: strPtr("food"), Amount: 300})
+
+		assert.ErrorIs(t, err, envelopes.ErrNotEnoughToAssign)
+
+		_, err = m.uc.Assign(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202501", ToCategoryID: strPtr("food"), Amount: 200})
+
+		assert.NoError(t, err)
+		m.envelopesRepo.AssertNumberOfCalls(t, "CreateMovement", 1)
+	})
+}
+
+func TestEnvelopesUseCase_Move(t *testing.T) {
+	t.Run("should reject moving to the same envelope", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+
+		_, err := m.uc.Move(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202502", FromCategoryID: strPtr("food"), ToCategoryID: strPtr("food"), Amount: 10})
+
+		assert.ErrorIs(t, err, envelopes.ErrSameEnvelope)
+		m.envelopesRepo.AssertNotCalled(t, "CreateMovement")
+	})
+
+	t.Run("should move up to the balance of the source envelope", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		m.withAmounts()
+		m.envelopesRepo.On("CreateMovement", mock.Anything, mock.Anything).Return(envelopes.EnvelopeMovement{ID: "movement-1"}, nil)
+
+		// rent has 200 left at the end of February
+		_, err := m.uc.Move(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202502", FromCategoryID: strPtr("rent"), ToCategoryID: strPtr("food"), Amount: 200})
+
+		assert.NoError(t, err)
+		m.envelopesRepo.AssertNumberOfCalls(t, "CreateMovement", 1)
+	})
+
+	t.Run("should not move what a later period already took from the envelope", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		m.withAmounts()
+
+		// rent has 500 at the end of January but February moves 300 out of it
+		_, err := m.uc.Move(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202501", FromCategoryID: strPtr("rent"), ToCategoryID: strPtr("food"), Amount: 201})
+
+		assert.ErrorIs(t, err, envelopes.ErrNotEnoughInEnvelope)
+		m.envelopesRepo.AssertNotCalled(t, "CreateMovement")
+	})
+
+	t.Run("should not move more than the source envelope has", func(t *testing.T) {
+		m := newEnvelopesUseCase(t)
+		m.withAmounts()
+
+		_, err := m.uc.Move(envelopes.CreateMovementDTO{UserID: "user-1", Period: "202502", FromCategoryID: strPtr("food"), ToCategoryID: strPtr("rent"), Amount: 50})
+
+		assert.ErrorIs(t, err, envelopes.ErrNotEnoughInEnvelope)
+		m.envelopesRepo.AssertNotCalled(t, "CreateMovement")
+	})
+}
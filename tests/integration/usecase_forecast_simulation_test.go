@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecast_WithEntries(t *testing.T) {
+	baseline := forecast.Forecast{
+		OpeningBalance: 1000,
+		Periods: []forecast.ForecastPeriod{
+			{Period: "202511", Income: 500, Expenses: -400, Net: 100, Balance: 1100},
+			{Period: "202512", Income: 500, Expenses: -400, Net: 100, Balance: 1200},
+		},
+		NegativePeriods: []string{},
+	}
+
+	t.Run("should apply entries and recompute balances", func(t *testing.T) {
+		simulated := baseline.WithEntries([]forecast.ProjectedEntry{
+			{Period: "202510", Amount: -300},
+			{Period: "202511", Amount: -600},
+			{Period: "202512", Amount: -600},
+			{Period: "203001", Amount: -600},
+		})
+
+		assert.Equal(t, 700.0, simulated.OpeningBalance)
+		assert.Equal(t, -500.0, simulated.Periods[0].Net)
+		assert.Equal(t, -1000.0, simulated.Periods[0].Expenses)
+		assert.Equal(t, 200.0, simulated.Periods[0].Balance)
+		assert.Equal(t, -300.0, simulated.Periods[1].Balance)
+		assert.Equal(t, []string{"202512"}, simulated.NegativePeriods)
+	})
+
+	t.Run("should not change the baseline", func(t *testing.T) {
+		baseline.WithEntries([]forecast.ProjectedEntry{{Period: "202511", Amount: -600}})
+
+		assert.Equal(t, 100.0, baseline.Periods[0].Net)
+		assert.Equal(t, 1100.0, baseline.Periods[0].Balance)
+	})
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/forecast"
+	forecastMocks "github.com/felipe1496/open-wallet/internal/resources/forecast/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/recurring"
+	recurringMocks "github.com/felipe1496/open-wallet/internal/resources/recurring/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	summaryMocks "github.com/felipe1496/open-wallet/internal/resources/summary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// forecastPeriod is the period n months away from the current one
+func forecastPeriod(n int) string {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, n, 0).Format("200601")
+}
+
+type forecastMocksSet struct {
+	forecastRepo  *forecastMocks.MockForecastRepo
+	summaryRepo   *summaryMocks.MockSummaryRepo
+	recurringRepo *recurringMocks.MockRecurringRepo
+	uc            forecast.ForecastUseCase
+}
+
+func newForecastUseCase() forecastMocksSet {
+	set := forecastMocksSet{
+		forecastRepo:  new(forecastMocks.MockForecastRepo),
+		summaryRepo:   new(summaryMocks.MockSummaryRepo),
+		recurringRepo: new(recurringMocks.MockRecurringRepo),
+	}
+	set.uc = forecast.NewForecastUseCase(set.forecastRepo,
+		summary.NewSummaryUseCase(set.summaryRepo, nil),
+		recurring.NewRecurringUseCase(set.recurringRepo, nil, nil),
+		nil)
+	return set
+}
+
+func TestForecastUseCase_Forecast(t *testing.T) {
+	t.Run("should reject months out of range", func(t *testing.T) {
+		m := newForecastUseCase()
+
+		_, err := m.uc.Forecast("user-1", 0)
+		assert.ErrorIs(t, err, forecast.ErrInvalidMonths)
+
+		_, err = m.uc.Forecast("user-1", forecast.MaxMonths+1)
+		assert.ErrorIs(t, err, forecast.ErrInvalidMonths)
+
+		m.summaryRepo.AssertNotCalled(t, "ListPeriodSummaries")
+	})
+
+	t.Run("should project the periods after the current one", func(t *testing.T) {
+		m := newForecastUseCase()
+
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, "user-1", forecastPeriod(1), forecastPeriod(3)).
+			Return([]summary.PeriodSummary{
+				{Period: forecastPeriod(1), Expenses: -300, Net: -300, Balance: 700},
+				{Period: forecastPeriod(2), Balance: 700},
+				{Period: forecastPeriod(3), Expenses: -200, Net: -200, Balance: 500},
+			}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).
+			Return([]recurring.RecurringTransaction{
+				{Name: "Aluguel", Type: constants.SimpleExpense, Amount: -1500, CategoryID: strPtr("rent"), StartPeriod: "202001"},
+				{Name: "Salário", Type: constants.Income, Amount: 5000, StartPeriod: "202001"},
+				{Name: "Academia", Type: constants.SimpleExpense, Amount: -100, CategoryID: strPtr("gym"), StartPeriod: "202001", EndPeriod: strPtr(forecastPeriod(2))},
+				{Name: "Bônus", Type: constants.Income, Amount: 1000, StartPeriod: forecastPeriod(3)},
+			}, nil)
+		// the three complete periods before the current one
+		m.forecastRepo.On("AverageVariableSpending", mock.Anything, "user-1", forecastPeriod(-3), forecastPeriod(-1), 3).
+			Return([]forecast.CategoryAverage{
+				{CategoryID: strPtr("rent"), Average: -1400},
+				{CategoryID: strPtr("gym"), Average: -90},
+				{CategoryID: strPtr("food"), Average: -600},
+				{CategoryID: nil, Average: -50},
+			}, nil)
+		m.forecastRepo.On("ListScheduledCategories", mock.Anything, "user-1", forecastPeriod(1), forecastPeriod(3)).
+			Return([]forecast.ScheduledCategory{}, nil)
+
+		result, err := m.uc.Forecast("user-1", 3)
+
+		assert.NoError(t, err)
+		// rent and gym are projected by their templates, not by their average
+		assert.Equal(t, 1000.0, result.OpeningBalance)
+		assert.Len(t, result.Periods, 3)
+
+		first := result.Periods[0]
+		assert.Equal(t, forecastPeriod(1), first.Period)
+		assert.Equal(t, -300.0, first.Scheduled)
+		assert.Equal(t, 3400.0, first.Recurring)
+		assert.Equal(t, -650.0, first.Variable)
+		assert.Equal(t, 5000.0, first.Income)
+		assert.Equal(t, -2550.0, first.Expenses)
+		assert.Equal(t, 2450.0, first.Net)
+		assert.Equal(t, 3450.0, first.Balance)
+
+		// the gym ends on the second period and the bonus starts on the third
+		assert.Equal(t, 3400.0, result.Periods[1].Recurring)
+		assert.Equal(t, 6200.0, result.Periods[1].Balance)
+		assert.Equal(t, 4500.0, result.Periods[2].Recurring)
+		assert.Equal(t, 3650.0, result.Periods[2].Net)
+		assert.Equal(t, 9850.0, result.Periods[2].Balance)
+		assert.Empty(t, result.NegativePeriods)
+	})
+
+	t.Run("should flag the periods with a negative balance", func(t *testing.T) {
+		m := newForecastUseCase()
+
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, "user-1", forecastPeriod(1), forecastPeriod(2)).
+			Return([]summary.PeriodSummary{
+				{Period: forecastPeriod(1), Balance: 100},
+				{Period: forecastPeriod(2), Expenses: -80, Net: -80, Balance: 20},
+			}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).Return([]recurring.RecurringTransaction{}, nil)
+		m.forecastRepo.On("AverageVariableSpending", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.CategoryAverage{{CategoryID: strPtr("food"), Average: -60}}, nil)
+		m.forecastRepo.On("ListScheduledCategories", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.ScheduledCategory{}, nil)
+
+		result, err := m.uc.Forecast("user-1", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 40.0, result.Periods[0].Balance)
+		assert.False(t, result.Periods[0].Negative)
+		assert.Equal(t, -100.0, result.Periods[1].Balance)
+		assert.True(t, result.Periods[1].Negative)
+		assert.Equal(t, []string{forecastPeriod(2)}, result.NegativePeriods)
+	})
+
+	t.Run("should not project a template where its category already has entries", func(t *testing.T) {
+		m := newForecastUseCase()
+
+		// the rent of the first period was already registered
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, "user-1", forecastPeriod(1), forecastPeriod(2)).
+			Return([]summary.PeriodSummary{
+				{Period: forecastPeriod(1), Expenses: -1500, Net: -1500, Balance: 500},
+				{Period: forecastPeriod(2), Balance: 500},
+			}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).
+			Return([]recurring.RecurringTransaction{
+				{Name: "Aluguel", Type: constants.SimpleExpense, Amount: -1500, CategoryID: strPtr("rent"), StartPeriod: "202001"},
+				{Name: "Salário", Type: constants.Income, Amount: 5000, StartPeriod: "202001"},
+			}, nil)
+		m.forecastRepo.On("AverageVariableSpending", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.CategoryAverage{}, nil)
+		m.forecastRepo.On("ListScheduledCategories", mock.Anything, "user-1", forecastPeriod(1), forecastPeriod(2)).
+			Return([]forecast.ScheduledCategory{{Period: forecastPeriod(1), CategoryID: "rent"}}, nil)
+
+		result, err := m.uc.Forecast("user-1", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, -1500.0, result.Periods[0].Scheduled)
+		assert.Equal(t, 5000.0, result.Periods[0].Recurring)
+		assert.Equal(t, -1500.0, result.Periods[0].Expenses)
+		assert.Equal(t, 5500.0, result.Periods[0].Balance)
+		assert.Equal(t, 3500.0, result.Periods[1].Recurring)
+		assert.Equal(t, 9000.0, result.Periods[1].Balance)
+	})
+
+	t.Run("should return error when a dependency fails", func(t *testing.T) {
+		m := newForecastUseCase()
+
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]summary.PeriodSummary{}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).
+			Return([]recurring.RecurringTransaction{}, errors.New("db error"))
+
+		_, err := m.uc.Forecast("user-1", 1)
+		assert.ErrorIs(t, err, forecast.ErrFailedToListRecurringForecast)
+
+		m = newForecastUseCase()
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]summary.PeriodSummary{}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).Return([]recurring.RecurringTransaction{}, nil)
+		m.forecastRepo.On("AverageVariableSpending", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.CategoryAverage{}, errors.New("db error"))
+
+		_, err = m.uc.Forecast("user-1", 1)
+		assert.ErrorIs(t, err, forecast.ErrFailedToAverageSpending)
+
+		m = newForecastUseCase()
+		m.summaryRepo.On("ListPeriodSummaries", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]summary.PeriodSummary{}, nil)
+		m.recurringRepo.On("List", mock.Anything, mock.Anything).Return([]recurring.RecurringTransaction{}, nil)
+		m.forecastRepo.On("AverageVariableSpending", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.CategoryAverage{}, nil)
+		m.forecastRepo.On("ListScheduledCategories", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]forecast.ScheduledCategory{}, errors.New("db error"))
+
+		_, err = m.uc.Forecast("user-1", 1)
+		assert.ErrorIs(t, err, forecast.ErrFailedToListScheduled)
+	})
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/imports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestReadCSV(t *testing.T) {
+	t.Run("should detect a brazilian bank export", func(t *testing.T) {
+		// latin-1 encoded, as exported by most brazilian banks
+		data := []byte("Data;Descri\xe7\xe3o;Valor\n03/04/2025;Padaria S\xe3o Jo\xe3o;-1.234,56\n05/04/2025;Sal\xe1rio;5.000,00\n")
+
+		mapping, header, rows, err := imports.ReadCSV(data, imports.CSVMapping{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "latin-1", mapping.Encoding)
+		assert.Equal(t, ";", mapping.Delimiter)
+		assert.Equal(t, ",", mapping.DecimalSeparator)
+		assert.Equal(t, "DD/MM/YYYY", mapping.DateFormat)
+		assert.True(t, *mapping.HasHeader)
+		assert.Equal(t, 0, *mapping.DateColumn)
+		assert.Equal(t, 1, *mapping.NameColumn)
+		assert.Equal(t, 2, *mapping.AmountColumn)
+		assert.Equal(t, []string{"Data", "Descrição", "Valor"}, header)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "Padaria São João", rows[0][1])
+	})
+
+	t.Run("should read files without header using decimal point", func(t *testing.T) {
+		data := []byte("2025-04-03,Coffee shop,-12.50\n2025-04-04,Refund,30.00\n")
+
+		mapping, header, rows, err := imports.ReadCSV(data, imports.CSVMapping{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ",", mapping.Delimiter)
+		assert.Equal(t, ".", mapping.DecimalSeparator)
+		assert.Equal(t, "YYYY-MM-DD", mapping.DateFormat)
+		assert.False(t, *mapping.HasHeader)
+		assert.Nil(t, header)
+		assert.Len(t, rows, 2)
+	})
+
+	t.Run("should keep the columns sent in the mapping", func(t *testing.T) {
+		data := []byte("Valor;Data;Histórico;Documento\n-10,00;03/04/2025;Mercado;123456789\n")
+
+		mapping, _, _, err := imports.ReadCSV(data, imports.CSVMapping{NameColumn: intPtr(2)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *mapping.DateColumn)
+		assert.Equal(t, 0, *mapping.AmountColumn)
+		assert.Equal(t, 2, *mapping.NameColumn)
+	})
+
+	t.Run("should fail when columns can't be detected", func(t *testing.T) {
+		_, _, _, err := imports.ReadCSV([]byte("name;description\nfoo;bar\n"), imports.CSVMapping{})
+
+		assert.ErrorIs(t, err, imports.ErrColumnsNotDetected)
+	})
+}
+
+func TestParseCSVRow(t *testing.T) {
+	hasHeader := true
+	mapping := imports.CSVMapping{
+		HasHeader:        &hasHeader,
+		DecimalSeparator: ",",
+		DateFormat:       "DD/MM/YYYY",
+		DateColumn:       intPtr(0),
+		NameColumn:       intPtr(1),
+		AmountColumn:     intPtr(2),
+		CategoryColumn:   intPtr(3),
+	}
+
+	t.Run("should read expenses and incomes by the amount sign", func(t *testing.T) {
+		expense := imports.ParseCSVRow([]string{"3/4/2025", "Mercado", "-1.234,56", "Food"}, mapping, 2)
+		income := imports.ParseCSVRow([]string{"05/04/2025", "Salário", "5.000,00", ""}, mapping, 3)
+
+		assert.Nil(t, expense.Error)
+		assert.Equal(t, "2025-04-03", expense.Date)
+		assert.Equal(t, -1234.56, expense.Amount)
+		assert.Equal(t, constants.SimpleExpense, expense.Type)
+		assert.Equal(t, "Food", *expense.Category)
+		assert.Equal(t, constants.Income, income.Type)
+		assert.Nil(t, income.Category)
+	})
+
+	t.Run("should invert amounts of credit card exports", func(t *testing.T) {
+		inverted := mapping
+		inverted.InvertAmounts = true
+
+		row := imports.ParseCSVRow([]string{"03/04/2025", "Mercado", "99,90", ""}, inverted, 2)
+
+		assert.Equal(t, -99.9, row.Amount)
+		assert.Equal(t, constants.SimpleExpense, row.Type)
+	})
+
+	t.Run("should report invalid rows", func(t *testing.T) {
+		badDate := imports.ParseCSVRow([]string{"2025-04-03", "Mercado", "-10,00"}, mapping, 2)
+		zero := imports.ParseCSVRow([]string{"03/04/2025", "Mercado", "0,00"}, mapping, 3)
+
+		assert.NotNil(t, badDate.Error)
+		assert.Equal(t, 2, badDate.Row)
+		assert.Equal(t, "amount must not be zero", *zero.Error)
+	})
+}
+
+func TestMergeMapping(t *testing.T) {
+	profile := imports.CSVMapping{Delimiter: ";", DateColumn: intPtr(0), NameColumn: intPtr(1), Account: strPtr("nubank")}
+
+	merged := imports.MergeMapping(profile, imports.CSVMapping{NameColumn: intPtr(3), InvertAmounts: true})
+
+	assert.Equal(t, ";", merged.Delimiter)
+	assert.Equal(t, 0, *merged.DateColumn)
+	assert.Equal(t, 3, *merged.NameColumn)
+	assert.True(t, merged.InvertAmounts)
+	assert.Equal(t, "nubank", *merged.Account)
+}
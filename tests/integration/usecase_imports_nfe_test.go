@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/imports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const nfeXML = `<?xml version="1.0" encoding="UTF-8"?>
+<nfeProc xmlns="http://www.portalfiscal.inf.br/nfe" versao="4.00">
+  <NFe>
+    <infNFe Id="NFe35250412345678000199550010000012341000123451" versao="4.00">
+      <ide><mod>55</mod><serie>1</serie><nNF>1234</nNF><dhEmi>2025-04-03T10:20:30-03:00</dhEmi></ide>
+      <emit><CNPJ>12345678000199</CNPJ><xNome>Eletro Comercio LTDA</xNome><xFant>Eletro Loja</xFant></emit>
+      <det nItem="1">
+        <prod><cProd>789</cProd><xProd>Notebook</xProd><uCom>UN</uCom><qCom>1.0000</qCom><vUnCom>3000.0000000000</vUnCom><vProd>3000.00</vProd><vDesc>100.00</vDesc></prod>
+      </det>
+      <det nItem="2">
+        <prod><cProd>790</cProd><xProd>Mouse</xProd><uCom>UN</uCom><qCom>2.0000</qCom><vUnCom>50.0000000000</vUnCom><vProd>100.00</vProd></prod>
+      </det>
+      <total><ICMSTot><vProd>3100.00</vProd><vDesc>100.00</vDesc><vNF>3000.00</vNF></ICMSTot></total>
+      <cobr>
+        <dup><nDup>001</nDup><dVenc>2025-05-03</dVenc><vDup>1000.00</vDup></dup>
+        <dup><nDup>002</nDup><dVenc>2025-06-03</dVenc><vDup>1000.00</vDup></dup>
+        <dup><nDup>003</nDup><dVenc>2025-07-03</dVenc><vDup>1000.00</vDup></dup>
+      </cobr>
+      <pag><detPag><tPag>15</tPag><vPag>3000.00</vPag></detPag></pag>
+    </infNFe>
+  </NFe>
+  <protNFe versao="4.00"><infProt><chNFe>35250412345678000199550010000012341000123451</chNFe></infProt></protNFe>
+</nfeProc>`
+
+const nfceXML = `<NFe xmlns="http://www.portalfiscal.inf.br/nfe">
+  <infNFe Id="NFe35250412345678000199650010000009871000987650" versao="3.10">
+    <ide><mod>65</mod><serie>1</serie><nNF>987</nNF><dhEmi>2025-04-10T08:00:00-03:00</dhEmi></ide>
+    <emit><CNPJ>12345678000199</CNPJ><xNome>Padaria Pão Quente</xNome></emit>
+    <det nItem="1"><prod><cProd>1</cProd><xProd>Pão francês</xProd><uCom>KG</uCom><qCom>0.5000</qCom><vUnCom>18.90</vUnCom><vProd>9.45</vProd></prod></det>
+    <total><ICMSTot><vNF>9.45</vNF></ICMSTot></total>
+    <pag><tPag>17</tPag><vPag>9.45</vPag></pag>
+  </infNFe>
+</NFe>`
+
+func TestValidAccessKey(t *testing.T) {
+	assert.True(t, imports.ValidAccessKey("35250412345678000199550010000012341000123451"))
+	assert.False(t, imports.ValidAccessKey("35250412345678000199550010000012341000123452"))
+	assert.False(t, imports.ValidAccessKey("3525041234567800019955001000001234100012345"))
+	assert.False(t, imports.ValidAccessKey("3525041234567800019955001000001234100012345a"))
+}
+
+func TestParseNFe(t *testing.T) {
+	t.Run("should read an NF-e with installments", func(t *testing.T) {
+		invoice, err := imports.ParseNFe([]byte(nfeXML))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "35250412345678000199550010000012341000123451", invoice.AccessKey)
+		assert.Equal(t, "1234", invoice.Number)
+		assert.Equal(t, "2025-04-03", invoice.IssueDate)
+		assert.Equal(t, "Eletro Loja", invoice.IssuerName)
+		assert.Equal(t, "12.345.678/0001-99", invoice.IssuerDocument)
+		assert.Equal(t, 3000.0, invoice.Total)
+		assert.Len(t, invoice.Items, 2)
+		assert.Equal(t, "Notebook", invoice.Items[0].Description)
+		assert.Equal(t, 2900.0, invoice.Items[0].Amount)
+		assert.Equal(t, 2.0, invoice.Items[1].Quantity)
+		assert.Len(t, invoice.Installments, 3)
+		assert.Equal(t, "Boleto Bancário", invoice.Payments[0].Description)
+
+		transaction := imports.NFeTransaction(invoice)
+
+		assert.Equal(t, constants.Installment, transaction.Type)
+		assert.Equal(t, "Eletro Loja", *transaction.Payee)
+		assert.Equal(t, "nfe:35250412345678000199550010000012341000123451", *transaction.ExternalID)
+		assert.Equal(t, "NF-e 1234 · CNPJ 12.345.678/0001-99 · Boleto Bancário", *transaction.Note)
+		assert.Len(t, transaction.Entries, 3)
+		assert.Equal(t, -1000.0, transaction.Entries[0].Amount)
+		assert.Equal(t, "2025-05-03", transaction.Entries[0].ReferenceDate)
+		assert.Len(t, transaction.Items, 2)
+	})
+
+	t.Run("should read an NFC-e without the protocol", func(t *testing.T) {
+		invoice, err := imports.ParseNFe([]byte(nfceXML))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Padaria Pão Quente", invoice.IssuerName)
+		assert.Equal(t, "PIX", invoice.Payments[0].Description)
+
+		transaction := imports.NFeTransaction(invoice)
+
+		assert.Equal(t, constants.SimpleExpense, transaction.Type)
+		assert.Equal(t, "NFC-e 987 · CNPJ 12.345.678/0001-99 · PIX", *transaction.Note)
+		assert.Equal(t, -9.45, transaction.Entries[0].Amount)
+		assert.Equal(t, "2025-04-10", transaction.Entries[0].ReferenceDate)
+	})
+
+	t.Run("should reject invalid invoices", func(t *testing.T) {
+		_, err := imports.ParseNFe([]byte("<html></html>"))
+		assert.ErrorIs(t, err, imports.ErrInvalidNFe)
+
+		_, err = imports.ParseNFe([]byte("not xml"))
+		assert.ErrorIs(t, err, imports.ErrInvalidNFe)
+
+		tampered := strings.Replace(nfceXML, "1000987650", "1000987651", 1)
+		_, err = imports.ParseNFe([]byte(tampered))
+		assert.ErrorIs(t, err, imports.ErrInvalidAccessKey)
+	})
+}
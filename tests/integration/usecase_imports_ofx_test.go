@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/imports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sgmlOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+CHARSET:1252
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20250410120000[-3:BRT]<LANGUAGE>POR</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>1001
+<STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM>
+<BANKID>0260
+<ACCTID>12345-6
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20250401000000[-3:BRT]
+<DTEND>20250410000000[-3:BRT]
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250403000000[-3:BRT]
+<TRNAMT>-52,90
+<FITID>abc-1
+<NAME>Padaria S` + "\xe3" + `o Jo` + "\xe3" + `o
+<MEMO>Compra no d` + "\xe9" + `bito
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250405
+<TRNAMT>5000.00
+<FITID>abc-2
+<MEMO>Sal` + "\xe1" + `rio
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>4947.10
+<DTASOF>20250410
+</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlOFX = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <CURDEF>BRL</CURDEF>
+        <CCACCTFROM><ACCTID>4111</ACCTID></CCACCTFROM>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20250403</DTPOSTED>
+            <TRNAMT>-10.00</TRNAMT>
+            <NAME>Caf&#233; &amp; Cia</NAME>
+            <MEMO></MEMO>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20250403</DTPOSTED>
+            <TRNAMT>-10.00</TRNAMT>
+            <NAME>Caf&#233; &amp; Cia</NAME>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>OTHER</TRNTYPE>
+            <DTPOSTED>2025</DTPOSTED>
+            <TRNAMT>1.00</TRNAMT>
+            <FITID>bad</FITID>
+            <NAME>Broken</NAME>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>-20.00</BALAMT><DTASOF>20250410120000</DTASOF></LEDGERBAL>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+// empty leaves left unclosed, as some banks send them
+const emptyLeavesOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM><BANKID>0001<ACCTID>999<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250403
+<MEMO>
+<TRNAMT>-15.00
+<FITID>empty-1
+<NAME>Farm` + "\xe1" + `cia
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250404
+<NAME>
+<TRNAMT>-20.00
+<FITID>empty-2
+<MEMO>Posto Shell
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>100.00<DTASOF>20250410</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	t.Run("should read an OFX 1.x statement", func(t *testing.T) {
+		statements, err := imports.ParseOFX([]byte(sgmlOFX))
+
+		assert.NoError(t, err)
+		assert.Len(t, statements, 1)
+
+		statement := statements[0]
+		assert.Equal(t, "bank", statement.AccountType)
+		assert.Equal(t, "0260", *statement.BankID)
+		assert.Equal(t, "12345-6", *statement.AccountID)
+		assert.Equal(t, "BRL", *statement.Currency)
+		assert.Equal(t, "2025-04-01", *statement.StartDate)
+		assert.Equal(t, "2025-04-10", *statement.EndDate)
+		assert.Equal(t, 4947.10, *statement.LedgerBalance)
+		assert.Equal(t, "2025-04-10", *statement.LedgerBalanceDate)
+		assert.Equal(t, 2, statement.TransactionCount)
+
+		debit, credit := statement.Transactions[0], statement.Transactions[1]
+		assert.Equal(t, "abc-1", debit.FITID)
+		assert.Equal(t, "Padaria São João", debit.Name)
+		assert.Equal(t, "Compra no débito", *debit.Note)
+		assert.Equal(t, -52.9, debit.Amount)
+		assert.Equal(t, "2025-04-03", debit.Date)
+		assert.Equal(t, constants.SimpleExpense, debit.Type)
+		assert.Nil(t, debit.Error)
+
+		assert.Equal(t, "Salário", credit.Name)
+		assert.Nil(t, credit.Note)
+		assert.Equal(t, constants.Income, credit.Type)
+		assert.Equal(t, 2, credit.Row)
+	})
+
+	t.Run("should read an OFX 2.x credit card statement", func(t *testing.T) {
+		statements, err := imports.ParseOFX([]byte(xmlOFX))
+
+		assert.NoError(t, err)
+		assert.Len(t, statements, 1)
+
+		statement := statements[0]
+		assert.Equal(t, "credit_card", statement.AccountType)
+		assert.Nil(t, statement.BankID)
+		assert.Equal(t, "4111", *statement.AccountID)
+		assert.Equal(t, -20.0, *statement.LedgerBalance)
+		assert.Len(t, statement.Transactions, 3)
+
+		first, second := statement.Transactions[0], statement.Transactions[1]
+		assert.Equal(t, "Café & Cia", first.Name)
+		assert.Nil(t, first.Note)
+		// same day repeats without FITID still get different IDs
+		assert.NotEmpty(t, first.FITID)
+		assert.NotEqual(t, first.FITID, second.FITID)
+
+		again, _ := imports.ParseOFX([]byte(xmlOFX))
+		assert.Equal(t, first.FITID, again[0].Transactions[0].FITID)
+
+		broken := statement.Transactions[2]
+		assert.Equal(t, "invalid date '2025'", *broken.Error)
+	})
+
+	t.Run("should read the fields after an empty unclosed leaf", func(t *testing.T) {
+		statements, err := imports.ParseOFX([]byte(emptyLeavesOFX))
+
+		assert.NoError(t, err)
+		assert.Len(t, statements, 1)
+		assert.Equal(t, 100.0, *statements[0].LedgerBalance)
+		assert.Len(t, statements[0].Transactions, 2)
+
+		first, second := statements[0].Transactions[0], statements[0].Transactions[1]
+		assert.Equal(t, "empty-1", first.FITID)
+		assert.Equal(t, "Farmácia", first.Name)
+		assert.Nil(t, first.Note)
+		assert.Equal(t, -15.0, first.Amount)
+		assert.Nil(t, first.Error)
+
+		assert.Equal(t, "empty-2", second.FITID)
+		assert.Equal(t, "Posto Shell", second.Name)
+		assert.Equal(t, -20.0, second.Amount)
+		assert.Nil(t, second.Error)
+	})
+
+	t.Run("should reject files that are not OFX", func(t *testing.T) {
+		_, err := imports.ParseOFX([]byte("date;name;amount\n"))
+		assert.ErrorIs(t, err, imports.ErrInvalidOFX)
+
+		_, err = imports.ParseOFX([]byte("<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>"))
+		assert.ErrorIs(t, err, imports.ErrInvalidOFX)
+	})
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/imports"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const qifFile = `!Type:Cat
+NAlimentação
+E
+^
+NAlimentação:Mercado
+E
+^
+!Account
+NNubank
+TCCard
+^
+!Type:CCard
+D04/03'25
+T-1,234.56
+PSupermercado Extra
+MCompras do mês
+LAlimentação:Mercado
+^
+D4/ 5/2025
+T-100.00
+PFarmácia
+SSaúde
+EVitaminas
+$-60.00
+SHigiene
+$-40.00
+^
+D04/06/2025
+T500.00
+PTransfer
+L[Poupança]
+^
+!Type:Invst
+D04/07/2025
+NBuy
+T10.00
+^
+!Type:Bank
+D13/13/2025
+T-5.00
+PBroken
+^
+`
+
+func TestParseQIF(t *testing.T) {
+	t.Run("should read categories, accounts and splits", func(t *testing.T) {
+		file, err := imports.ParseQIF([]byte(qifFile), "MM/DD/YYYY")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Alimentação", "Alimentação:Mercado"}, file.Categories)
+		// the investment record is skipped
+		assert.Len(t, file.Transactions, 4)
+
+		groceries := file.Transactions[0]
+		assert.Equal(t, "2025-04-03", groceries.Date)
+		assert.Equal(t, -1234.56, groceries.Amount)
+		assert.Equal(t, "Supermercado Extra", *groceries.Payee)
+		assert.Equal(t, "Compras do mês", *groceries.Memo)
+		assert.Equal(t, "Alimentação:Mercado", *groceries.Category)
+		assert.Equal(t, "Nubank", *groceries.Account)
+		assert.Nil(t, groceries.Error)
+
+		pharmacy := file.Transactions[1]
+		assert.Equal(t, "2025-04-05", pharmacy.Date)
+		assert.Len(t, pharmacy.Splits, 2)
+		assert.Equal(t, "Saúde", *pharmacy.Splits[0].Category)
+		assert.Equal(t, "Vitaminas", *pharmacy.Splits[0].Memo)
+		assert.Equal(t, -60.0, pharmacy.Splits[0].Amount)
+		assert.Nil(t, pharmacy.Splits[1].Memo)
+		assert.Equal(t, -40.0, pharmacy.Splits[1].Amount)
+
+		transfer := file.Transactions[2]
+		assert.Nil(t, transfer.Category)
+
+		broken := file.Transactions[3]
+		assert.Equal(t, 4, broken.Row)
+		assert.Equal(t, "invalid date '13/13/2025'", *broken.Error)
+	})
+
+	t.Run("should read day first dates", func(t *testing.T) {
+		file, err := imports.ParseQIF([]byte("!Type:Bank\nD13/04/2025\nT-5,50\nPPadaria\n^\n"), "DD/MM/YYYY")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-04-13", file.Transactions[0].Date)
+		assert.Equal(t, -5.5, file.Transactions[0].Amount)
+	})
+
+	t.Run("should not carry the account to a section without !Account", func(t *testing.T) {
+		data := "!Account\nNNubank\nTBank\n^\n!Type:Bank\nD04/03/2025\nT-1.00\nPUber\n^\n" +
+			"!Type:Cash\nD04/04/2025\nT-2.00\nPPadaria\n^\n"
+
+		file, err := imports.ParseQIF([]byte(data), "MM/DD/YYYY")
+
+		assert.NoError(t, err)
+		assert.Len(t, file.Transactions, 2)
+		assert.Equal(t, "Nubank", *file.Transactions[0].Account)
+		assert.Nil(t, file.Transactions[1].Account)
+	})
+
+	t.Run("should reject splits that don't add up to the total", func(t *testing.T) {
+		data := "!Type:Bank\nD04/05/2025\nT-100.00\nPFarmácia\nSSaúde\n$-60.00\nSHigiene\n$-30.00\n^\n"
+
+		file, err := imports.ParseQIF([]byte(data), "MM/DD/YYYY")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "splits add up to -90.00 instead of the total -100.00", *file.Transactions[0].Error)
+	})
+
+	t.Run("should derive the same external ids when parsing the file again", func(t *testing.T) {
+		data := "!Type:Bank\nD04/03/2025\nT-5.00\nPPadaria\n^\nD04/03/2025\nT-5.00\nPPadaria\n^\n" +
+			"D04/03/2025\nT-6.00\nPPadaria\n^\n"
+
+		first, err := imports.ParseQIF([]byte(data), "MM/DD/YYYY")
+		assert.NoError(t, err)
+		second, err := imports.ParseQIF([]byte(data), "MM/DD/YYYY")
+		assert.NoError(t, err)
+
+		for i := range first.Transactions {
+			assert.True(t, strings.HasPrefix(first.Transactions[i].ExternalID, "qif:sha1:"))
+			assert.Equal(t, first.Transactions[i].ExternalID, second.Transactions[i].ExternalID)
+		}
+		// the same purchase twice on a day is numbered apart
+		assert.NotEqual(t, first.Transactions[0].ExternalID, first.Transactions[1].ExternalID)
+		assert.NotEqual(t, first.Transactions[1].ExternalID, first.Transactions[2].ExternalID)
+	})
+
+	t.Run("should reject files without sections", func(t *testing.T) {
+		_, err := imports.ParseQIF([]byte("D04/03/2025\nT-1.00\n^\n"), "MM/DD/YYYY")
+
+		assert.ErrorIs(t, err, imports.ErrInvalidQIF)
+	})
+}
+
+func TestWriteQIF(t *testing.T) {
+	entries := []transactions.ViewEntry{
+		{
+			Name:          "Salário",
+			Amount:        5000,
+			Type:          constants.Income,
+			ReferenceDate: "2025-04-05",
+		},
+		{
+			Name:              "Notebook",
+			Description:       strPtr("12x sem juros\nloja online"),
+			Amount:            -350.5,
+			Type:              constants.Installment,
+			Installment:       2,
+			TotalInstallments: 12,
+			ReferenceDate:     "2025-04-10",
+			CategoryName:      strPtr("Eletrônicos"),
+			Account:           strPtr("Nubank"),
+		},
+	}
+
+	data := imports.WriteQIF(entries, "MM/DD/YYYY")
+
+	assert.Equal(t, `!Type:Bank
+D04/05/2025
+T5000.00
+PSalário
+^
+!Account
+NNubank
+TBank
+^
+!Type:Bank
+D04/10/2025
+T-350.50
+PNotebook (2/12)
+M12x sem juros loja online
+LEletrônicos
+^
+`, string(data))
+
+	file, err := imports.ParseQIF(data, "MM/DD/YYYY")
+
+	assert.NoError(t, err)
+	assert.Len(t, file.Transactions, 2)
+	assert.Nil(t, file.Transactions[0].Account)
+	assert.Equal(t, "Nubank", *file.Transactions[1].Account)
+	assert.Equal(t, "2025-04-10", file.Transactions[1].Date)
+	assert.Equal(t, -350.5, file.Transactions[1].Amount)
+	assert.Equal(t, "Eletrônicos", *file.Transactions[1].Category)
+}
+
+func TestQIFRoundTrip(t *testing.T) {
+	// ordered by account like ExportQIF lists them, entries without account last
+	entries := []transactions.ViewEntry{
+		{Name: "Mercado", Amount: -120, Type: constants.SimpleExpense, ReferenceDate: "2025-04-02", Account: strPtr("Itaú")},
+		{Name: "Uber", Amount: -25.9, Type: constants.SimpleExpense, ReferenceDate: "2025-04-03", Account: strPtr("Nubank"), CategoryName: strPtr("Transporte")},
+		{Name: "Padaria", Amount: -12.5, Type: constants.SimpleExpense, ReferenceDate: "2025-04-04"},
+		{Name: "Pix recebido", Amount: 80, Type: constants.Income, ReferenceDate: "2025-04-05"},
+	}
+
+	data := imports.WriteQIF(entries, "DD/MM/YYYY")
+
+	assert.True(t, strings.HasPrefix(string(data), "!Type:Bank\nD04/04/2025\n"))
+
+	file, err := imports.ParseQIF(data, "DD/MM/YYYY")
+
+	assert.NoError(t, err)
+	accounts := make(map[string]*string)
+	for _, transaction := range file.Transactions {
+		assert.Nil(t, transaction.Error)
+		accounts[*transaction.Payee] = transaction.Account
+	}
+	assert.Len(t, accounts, 4)
+	assert.Nil(t, accounts["Padaria"])
+	assert.Nil(t, accounts["Pix recebido"])
+	assert.Equal(t, "Itaú", *accounts["Mercado"])
+	assert.Equal(t, "Nubank", *accounts["Uber"])
+}
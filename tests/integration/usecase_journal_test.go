@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/journal"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var journalEntries = []transactions.ViewEntry{
+	{
+		Name:              "Salário",
+		Amount:            5000,
+		Type:              constants.Income,
+		ReferenceDate:     "2025-02-05",
+		Installment:       1,
+		TotalInstallments: 1,
+		CategoryName:      strPtr("salário"),
+		Account:           strPtr("itaú"),
+		Tags:              []string{},
+	},
+	{
+		Name:              "Notebook",
+		Description:       strPtr("sem\njuros"),
+		Amount:            -250,
+		Type:              constants.Installment,
+		ReferenceDate:     "2025-02-10",
+		Installment:       2,
+		TotalInstallments: 4,
+		CategoryName:      strPtr("eletrônicos:informática"),
+		Payee:             strPtr(`Loja "Tech"`),
+		Account:           strPtr("Nubank"),
+		Tags:              []string{"home office", "trabalho"},
+	},
+	{
+		Name:              "Padaria",
+		Amount:            -12.3,
+		Type:              constants.SimpleExpense,
+		ReferenceDate:     "2025-02-11",
+		Installment:       1,
+		TotalInstallments: 1,
+		Tags:              []string{},
+	},
+}
+
+func TestWriteJournal(t *testing.T) {
+	settings := journal.DefaultSettings("user")
+	settings.Accounts = map[string]string{"nubank": "Liabilities:cartão nubank"}
+
+	t.Run("should write a ledger journal", func(t *testing.T) {
+		data := string(journal.WriteJournal(journalEntries, settings, journal.Ledger))
+
+		assert.Contains(t, data, "2025-02-05 Salário\n")
+		assert.Regexp(t, `    Income:Salário +-5000\.00 BRL\n    Assets:Itaú +5000\.00 BRL\n`, data)
+		assert.Contains(t, data, "2025-02-10 Notebook (2/4)\n    ; sem juros\n    ; payee: Loja \"Tech\"\n    ; installment: 2/4\n    ; :home-office:trabalho:\n")
+		assert.Regexp(t, `    Expenses:Eletrônicos:Informática +250\.00 BRL\n    Liabilities:Cartão Nubank +-250\.00 BRL\n`, data)
+		assert.Regexp(t, `    Expenses:Uncategorized +12\.30 BRL\n    Assets:Checking +-12\.30 BRL\n`, data)
+	})
+
+	t.Run("should write the payee and tags the hledger way", func(t *testing.T) {
+		data := string(journal.WriteJournal(journalEntries, settings, journal.HLedger))
+
+		assert.Contains(t, data, "2025-02-10 Loja \"Tech\" | Notebook (2/4)\n")
+		assert.Contains(t, data, "    ; installment:2/4\n    ; home-office:, trabalho:\n")
+	})
+
+	t.Run("should open accounts and rename roots in beancount", func(t *testing.T) {
+		custom := settings
+		custom.ExpensesRoot = "Despesas"
+		custom.Accounts = map[string]string{"nubank": "Liabilities:cartão nubank"}
+
+		data := string(journal.WriteJournal(journalEntries, custom, journal.Beancount))
+
+		assert.True(t, strings.HasPrefix(data, "option \"operating_currency\" \"BRL\"\noption \"name_expenses\" \"Despesas\"\n"))
+		assert.Contains(t, data, "2025-02-05 open Assets:Itaú BRL\n")
+		assert.Contains(t, data, "2025-02-10 open Liabilities:Cartão-Nubank BRL\n")
+		assert.Contains(t, data, "2025-02-10 open Despesas:Eletrônicos:Informática BRL\n")
+		assert.Contains(t, data, "2025-02-10 * \"Loja \\\"Tech\\\"\" \"Notebook (2/4)\" #home-office #trabalho\n  installment: \"2/4\"\n  note: \"sem juros\"\n")
+		assert.Contains(t, data, "2025-02-11 * \"Padaria\"\n")
+		assert.NotContains(t, data, "name_assets")
+	})
+}
+
+func TestValidateJournalSettings(t *testing.T) {
+	valid := journal.DefaultSettings("user")
+	assert.NoError(t, journal.ValidateSettings(valid))
+
+	cases := []struct {
+		name   string
+		change func(s *journal.Settings)
+		err    error
+	}{
+		{"lowercase root", func(s *journal.Settings) { s.AssetsRoot = "ativos" }, journal.ErrInvalidRoot},
+		{"root with a subaccount", func(s *journal.Settings) { s.IncomeRoot = "Income:Work" }, journal.ErrInvalidRoot},
+		{"repeated root", func(s *journal.Settings) { s.ExpensesRoot = "Assets" }, journal.ErrInvalidRoot},
+		{"empty default account", func(s *journal.Settings) { s.DefaultAccount = " - " }, journal.ErrInvalidAccountName},
+		{"symbol commodity", func(s *journal.Settings) { s.Commodity = "R$" }, journal.ErrInvalidCommodity},
+		{"mapped to an expense", func(s *journal.Settings) { s.Accounts = map[string]string{"nubank": "Expenses:Nubank"} }, journal.ErrInvalidMappedAccount},
+		{"mapped to a root", func(s *journal.Settings) { s.Accounts = map[string]string{"nubank": "Liabilities"} }, journal.ErrInvalidMappedAccount},
+	}
+
+	for _, c := range cases {
+		t.Run("should reject "+c.name, func(t *testing.T) {
+			settings := journal.DefaultSettings("user")
+			c.change(&settings)
+			assert.ErrorIs(t, journal.ValidateSettings(settings), c.err)
+		})
+	}
+}
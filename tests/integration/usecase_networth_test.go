@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/networth"
+	networthMocks "github.com/felipe1496/open-wallet/internal/resources/networth/mocks"
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	summaryMocks "github.com/felipe1496/open-wallet/internal/resources/summary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNetWorthAt(t *testing.T) {
+	items := []networth.Item{
+		{ID: "car", Name: "Car", Kind: networth.Asset},
+		{ID: "loan", Name: "Loan", Kind: networth.Liability},
+		{ID: "house", Name: "House", Kind: networth.Asset},
+	}
+	valuations := []networth.Valuation{
+		{ItemID: "car", Value: 50000, ValuedAt: "2025-01-10"},
+		{ItemID: "loan", Value: 20000, ValuedAt: "2025-01-31"},
+		{ItemID: "car", Value: 48000, ValuedAt: "2025-02-28"},
+		{ItemID: "loan", Value: 18000, ValuedAt: "2025-03-01"},
+	}
+
+	t.Run("should use the last valuation up to the end of the period", func(t *testing.T) {
+		point := networth.NetWorthAt("202502", 1000, items, valuations)
+
+		assert.Equal(t, 48000.0, point.Assets)
+		assert.Equal(t, 20000.0, point.Liabilities)
+		assert.Equal(t, 29000.0, point.NetWorth)
+		assert.Equal(t, "2025-02-28", *point.Items[0].ValuedAt)
+	})
+
+	t.Run("should value items without valuations as zero", func(t *testing.T) {
+		point := networth.NetWorthAt("202412", -100, items, valuations)
+
+		assert.Equal(t, 0.0, point.Assets)
+		assert.Equal(t, -100.0, point.NetWorth)
+		assert.Nil(t, point.Items[2].ValuedAt)
+		assert.Len(t, point.Items, 3)
+	})
+}
+
+// netWorthPeriods runs NetWorth over the periods from..to with no items and
+// returns the periods that got a point
+func netWorthPeriods(t *testing.T, from string, to string, periods []string, interval string) []string {
+	networthRepo := new(networthMocks.MockNetWorthRepo)
+	summaryRepo := new(summaryMocks.MockSummaryRepo)
+	uc := networth.NewNetWorthUseCase(networthRepo, summary.NewSummaryUseCase(summaryRepo, nil), nil)
+
+	summaries := make([]summary.PeriodSummary, len(periods))
+	for i, period := range periods {
+		summaries[i] = summary.PeriodSummary{Period: period}
+	}
+	summaryRepo.On("ListPeriodSummaries", mock.Anything, "user-1", from, to).Return(summaries, nil)
+	networthRepo.On("ListItems", mock.Anything, mock.Anything).Return([]networth.Item{}, nil)
+	networthRepo.On("ListValuations", mock.Anything, mock.Anything).Return([]networth.Valuation{}, nil)
+
+	points, err := uc.NetWorth("user-1", from, to, interval)
+	assert.NoError(t, err)
+
+	result := make([]string, len(points))
+	for i, point := range points {
+		result[i] = point.Period
+	}
+	return result
+}
+
+func TestNetWorthUseCase_NetWorth(t *testing.T) {
+	t.Run("should close quarters on their calendar months and keep the partial last one", func(t *testing.T) {
+		periods := []string{"202502", "202503", "202504", "202505", "202506", "202507", "202508"}
+
+		assert.Equal(t, []string{"202503", "202506", "202508"}, netWorthPeriods(t, "202502", "202508", periods, "quarter"))
+	})
+
+	t.Run("should close years in December", func(t *testing.T) {
+		periods := []string{"202411", "202412", "202501", "202502"}
+
+		assert.Equal(t, []string{"202412", "202502"}, netWorthPeriods(t, "202411", "202502", periods, "year"))
+	})
+
+	t.Run("should have a point for every month by default", func(t *testing.T) {
+		periods := []string{"202501", "202502"}
+
+		assert.Equal(t, periods, netWorthPeriods(t, "202501", "202502", periods, ""))
+	})
+}
@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+type paginatedRow struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type nullableRow struct {
+	ID    string  `json:"id"`
+	Payee *string `json:"payee,omitempty"`
+	Kind  string  `json:"type" db:"category"`
+}
+
+func TestQueryOpts_Keyset(t *testing.T) {
+	t.Run("should build keyset condition after the cursor", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("name", "desc").OrderBy("id", "desc").Limit(11)
+		qo.After(utils.Cursor{Values: []any{"b", "01"}})
+
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("t"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM t WHERE ((name < ?) OR (name = ? AND id < ?)) ORDER BY name DESC NULLS FIRST, id DESC NULLS FIRST LIMIT 11", sql)
+		assert.Equal(t, []any{"b", "b", "01"}, args)
+	})
+
+	t.Run("should reverse the order when going backward", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("id", "asc")
+		qo.After(utils.Cursor{Values: []any{"05"}, Backward: true})
+
+		sql, _, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("t"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM t WHERE ((id < ?)) ORDER BY id DESC NULLS FIRST", sql)
+	})
+
+	t.Run("should keep nulls after every value going forward", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("payee", "asc").OrderBy("id", "asc")
+		qo.After(utils.Cursor{Values: []any{"b", "01"}})
+
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("t"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM t WHERE (((payee > ? OR payee IS NULL)) OR (payee = ? AND (id > ? OR id IS NULL))) ORDER BY payee ASC NULLS LAST, id ASC NULLS LAST", sql)
+		assert.Equal(t, []any{"b", "b", "01"}, args)
+	})
+
+	t.Run("should page through nulls from a null cursor", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("payee", "asc").OrderBy("id", "asc")
+		qo.After(utils.Cursor{Values: []any{nil, "01"}})
+
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("t"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM t WHERE ((payee IS NULL AND (id > ? OR id IS NULL))) ORDER BY payee ASC NULLS LAST, id ASC NULLS LAST", sql)
+		assert.Equal(t, []any{"01"}, args)
+	})
+
+	t.Run("should reach the non null values going backward from a null cursor", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("payee", "asc").OrderBy("id", "asc")
+		qo.After(utils.Cursor{Values: []any{nil, "01"}, Backward: true})
+
+		sql, _, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("t"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM t WHERE ((payee IS NOT NULL) OR (payee IS NULL AND id < ?)) ORDER BY payee DESC NULLS FIRST, id DESC NULLS FIRST", sql)
+	})
+}
+
+// countOf is the count func of a listing with total rows
+func countOf(total int) func(filter *utils.QueryOptsBuilder) (int, error) {
+	return func(filter *utils.QueryOptsBuilder) (int, error) {
+		return total, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("should keep offset meta when not paginating by cursor", func(t *testing.T) {
+		qo := utils.QueryOpts().Limit(3)
+		rows := []paginatedRow{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+		items, meta, err := utils.Paginate(rows, qo, 1, 2, countOf(5))
+
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.True(t, meta.NextPage)
+		assert.Equal(t, 3, meta.TotalPages)
+		assert.Nil(t, meta.NextCursor)
+	})
+
+	t.Run("should build cursors that round trip", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("name", "asc").OrderBy("id", "asc")
+		qo.CursorPagination = true
+		qo.After(utils.Cursor{Orders: qo.OrderKeys(), Values: []any{"a", "0"}})
+		rows := []paginatedRow{{ID: "1", Name: "b"}, {ID: "2", Name: "c"}, {ID: "3", Name: "d"}}
+
+		items, meta, err := utils.Paginate(rows, qo, 1, 2, countOf(10))
+
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.NotNil(t, meta.NextCursor)
+		assert.NotNil(t, meta.PrevCursor)
+
+		next, err := utils.DecodeCursor(*meta.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name:asc", "id:asc"}, next.Orders)
+		assert.Equal(t, []any{"c", "2"}, next.Values)
+		assert.False(t, next.Backward)
+
+		prev, err := utils.DecodeCursor(*meta.PrevCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"b", "1"}, prev.Values)
+		assert.True(t, prev.Backward)
+	})
+
+	t.Run("should restore natural order of a backward page", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("id", "asc")
+		qo.CursorPagination = true
+		qo.After(utils.Cursor{Orders: qo.OrderKeys(), Values: []any{"5"}, Backward: true})
+		rows := []paginatedRow{{ID: "4"}, {ID: "3"}}
+
+		items, meta, err := utils.Paginate(rows, qo, 1, 2, countOf(10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []paginatedRow{{ID: "3"}, {ID: "4"}}, items)
+		assert.NotNil(t, meta.NextCursor)
+		assert.Nil(t, meta.PrevCursor)
+	})
+
+	t.Run("should read cursor values by column, nulls included", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("category", "asc").OrderBy("payee", "asc").OrderBy("id", "asc")
+		qo.CursorPagination = true
+		rows := []nullableRow{{ID: "1", Kind: "income", Payee: strPtr("Loja")}, {ID: "2", Kind: "income"}}
+
+		_, meta, err := utils.Paginate(rows, qo, 1, 1, countOf(2))
+
+		assert.NoError(t, err)
+		next, err := utils.DecodeCursor(*meta.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"income", "Loja", "1"}, next.Values)
+
+		_, meta, err = utils.Paginate([]nullableRow{rows[1], rows[0]}, qo, 1, 1, countOf(2))
+
+		assert.NoError(t, err)
+		next, err = utils.DecodeCursor(*meta.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"income", nil, "2"}, next.Values)
+	})
+
+	t.Run("should reject order fields that are not columns of the row", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("type", "asc").OrderBy("id", "asc")
+		qo.CursorPagination = true
+
+		_, _, err := utils.Paginate([]nullableRow{{ID: "1"}, {ID: "2"}}, qo, 1, 1, countOf(2))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should count the rows from a backward cursor to tell if there's a next page", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("id", "asc").And("user_id", "eq", "u")
+		qo.CursorPagination = true
+		qo.After(utils.Cursor{Orders: qo.OrderKeys(), Values: []any{"5"}, Backward: true})
+
+		counted := make([]string, 0)
+		items, meta, err := utils.Paginate([]paginatedRow{{ID: "4"}, {ID: "3"}}, qo, 1, 2, func(filter *utils.QueryOptsBuilder) (int, error) {
+			sql, _, _ := utils.QueryOptsToSquirrel(squirrel.Select("COUNT(*)").From("t"), filter).ToSql()
+			counted = append(counted, sql)
+			if len(counted) == 1 {
+				return 4, nil
+			}
+			return 0, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"SELECT COUNT(*) FROM t WHERE user_id = ?",
+			"SELECT COUNT(*) FROM t WHERE user_id = ? AND (((id > ? OR id IS NULL)) OR (id = ?))",
+		}, counted)
+		assert.Len(t, items, 2)
+		assert.Equal(t, 4, meta.TotalItems)
+		assert.Nil(t, meta.NextCursor)
+		assert.False(t, meta.NextPage)
+	})
+
+	t.Run("should only count every row when not going backward", func(t *testing.T) {
+		qo := utils.QueryOpts().OrderBy("id", "asc")
+		qo.CursorPagination = true
+		qo.After(utils.Cursor{Orders: qo.OrderKeys(), Values: []any{"2"}})
+
+		calls := 0
+		_, _, err := utils.Paginate([]paginatedRow{{ID: "3"}}, qo, 1, 2, func(filter *utils.QueryOptsBuilder) (int, error) {
+			calls++
+			return 3, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should return the error of the count", func(t *testing.T) {
+		_, _, err := utils.Paginate([]paginatedRow{}, utils.QueryOpts(), 1, 2, func(filter *utils.QueryOptsBuilder) (int, error) {
+			return 0, errors.New("db error")
+		})
+
+		assert.EqualError(t, err, "db error")
+	})
+}
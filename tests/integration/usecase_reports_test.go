@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/reports"
+	"github.com/felipe1496/open-wallet/internal/resources/reports/mocks"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestReportsUseCase_Aggregate(t *testing.T) {
+	t.Run("should reject unknown dimensions", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		_, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"merchant"}}, utils.QueryOpts())
+
+		assert.EqualError(t, err, "unknown dimension 'merchant'")
+		mockRepo.AssertNotCalled(t, "Aggregate")
+	})
+
+	t.Run("should reject filters on unknown fields", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		_, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"period"}},
+			utils.QueryOpts().And("1=1; drop table users; --", "eq", 1))
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Aggregate")
+	})
+
+	t.Run("should default measures to sum", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		mockRepo.On("Aggregate", mock.Anything, []string{"period"}, []string{"sum"}, mock.Anything).
+			Return(reports.AggregateReport{Columns: []string{"period", "sum"}}, nil)
+
+		_, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"period"}}, utils.QueryOpts())
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error when repo fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		mockRepo.On("Aggregate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(reports.AggregateReport{}, errors.New("db error"))
+
+		_, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"type"}}, utils.QueryOpts())
+
+		assert.ErrorIs(t, err, reports.ErrFailedToAggregate)
+	})
+
+	t.Run("should pivot a dimension into columns", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		mockRepo.On("Aggregate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(reports.AggregateReport{
+				Columns: []string{"period", "category", "sum"},
+				Rows: [][]any{
+					{strPtr("202501"), strPtr("food"), -100.0},
+					{strPtr("202501"), (*string)(nil), -5.0},
+					{strPtr("202502"), strPtr("food"), -80.0},
+				},
+			}, nil)
+
+		pivot := "category"
+		report, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"period", "category"}, Pivot: &pivot}, utils.QueryOpts())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"period", "food:sum", "null:sum"}, report.Columns)
+		assert.Equal(t, [][]any{
+			{strPtr("202501"), -100.0, -5.0},
+			{strPtr("202502"), -80.0, nil},
+		}, report.Rows)
+	})
+
+	t.Run("should pivot periods into columns with every measure", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		mockRepo.On("Aggregate", mock.Anything, []string{"type", "period"}, []string{"sum", "count"}, mock.Anything).
+			Return(reports.AggregateReport{
+				Columns: []string{"type", "period", "sum", "count"},
+				Rows: [][]any{
+					{strPtr("income"), strPtr("202412"), 5000.0, int64(1)},
+					{strPtr("income"), strPtr("202501"), 5200.0, int64(2)},
+					{strPtr("simple_expense"), strPtr("202501"), -320.5, int64(4)},
+				},
+			}, nil)
+
+		pivot := "period"
+		report, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"type", "period"}, Measures: []string{"sum", "count"}, Pivot: &pivot}, utils.QueryOpts())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"type", "202412:sum", "202412:count", "202501:sum", "202501:count"}, report.Columns)
+		assert.Equal(t, [][]any{
+			{strPtr("income"), 5000.0, int64(1), 5200.0, int64(2)},
+			{strPtr("simple_expense"), nil, nil, -320.5, int64(4)},
+		}, report.Rows)
+	})
+
+	t.Run("should reject a pivot that is not grouped by", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		pivot := "category"
+		_, err := uc.Aggregate(reports.AggregateDTO{GroupBy: []string{"period"}, Pivot: &pivot}, utils.QueryOpts())
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Aggregate")
+	})
+}
+
+func TestReportsUseCase_ListCommitments(t *testing.T) {
+	t.Run("should reject invalid from period", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		_, err := uc.ListCommitments("user-1", "2025-01", 12)
+
+		assert.ErrorIs(t, err, reports.ErrInvalidPeriod)
+	})
+
+	t.Run("should group installments by period", func(t *testing.T) {
+		mockRepo := new(mocks.MockReportsRepo)
+		uc := reports.NewReportsUseCase(mockRepo, nil)
+
+		mockRepo.On("ListFutureInstallments", mock.Anything, "user-1", "202511", "202602").
+			Return([]reports.CommittedInstallment{
+				{TransactionID: "t1", Period: "202511", Amount: -100, Installment: 2, TotalInstallments: 3, RemainingInstallments: 2, RemainingAmount: -200},
+				{TransactionID: "t2", Period: "202511", Amount: -50, Installment: 1, TotalInstallments: 2, RemainingInstallments: 2, RemainingAmount: -100},
+				{TransactionID: "t1", Period: "202512", Amount: -100, Installment: 3, TotalInstallments: 3, RemainingInstallments: 1, RemainingAmount: -100},
+			}, nil)
+
+		periods, err := uc.ListCommitments("user-1", "202511", 4)
+
+		assert.NoError(t, err)
+		assert.Len(t, periods, 2)
+		assert.Equal(t, -150.0, periods[0].Total)
+		assert.Len(t, periods[0].Installments, 2)
+		assert.Equal(t, "202512", periods[1].Period)
+		assert.Equal(t, -100.0, periods[1].Total)
+		mockRepo.AssertExpectations(t)
+	})
+}
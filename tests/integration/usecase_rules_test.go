@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/rules"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestApplyRules(t *testing.T) {
+	expense := constants.SimpleExpense
+	ruleList := []rules.Rule{
+		{
+			ID:         "uber",
+			Conditions: rules.RuleConditions{NameContains: strPtr("UBER"), Type: &expense},
+			Actions:    rules.RuleActions{Rename: strPtr("Uber"), SetCategoryID: strPtr("transport"), AddTags: []string{"ride"}},
+		},
+		{
+			ID:         "small",
+			Conditions: rules.RuleConditions{AmountMin: floatPtr(0), AmountMax: floatPtr(50)},
+			Actions:    rules.RuleActions{SetCategoryID: strPtr("misc"), SetPayee: strPtr("Small"), AddTags: []string{"small", "ride"}},
+		},
+		{
+			ID:         "card",
+			Conditions: rules.RuleConditions{Account: strPtr("nubank")},
+			Actions:    rules.RuleActions{AddTags: []string{"card"}},
+		},
+	}
+
+	t.Run("should apply matching rules in priority order", func(t *testing.T) {
+		result, matched := rules.ApplyRules(ruleList, rules.Subject{
+			Name:    "uber *trip",
+			Type:    constants.SimpleExpense,
+			Amount:  23.5,
+			Account: strPtr("Nubank"),
+		}, false)
+
+		assert.Equal(t, []string{"uber", "small", "card"}, matched)
+		assert.Equal(t, "Uber", result.Name)
+		assert.Equal(t, "transport", *result.CategoryID)
+		assert.Equal(t, "Small", *result.Payee)
+		assert.Equal(t, []string{"ride", "small", "card"}, result.Tags)
+	})
+
+	t.Run("should keep the category already set unless overwriting", func(t *testing.T) {
+		subject := rules.Subject{Name: "Bakery", Type: constants.SimpleExpense, Amount: 10, CategoryID: strPtr("food"), Tags: []string{}}
+
+		kept, _ := rules.ApplyRules(ruleList, subject, false)
+		overwritten, _ := rules.ApplyRules(ruleList, subject, true)
+
+		assert.Equal(t, "food", *kept.CategoryID)
+		assert.Equal(t, "misc", *overwritten.CategoryID)
+		assert.Empty(t, subject.Tags)
+	})
+
+	t.Run("should not match when a condition fails", func(t *testing.T) {
+		result, matched := rules.ApplyRules(ruleList, rules.Subject{Name: "Uber", Type: constants.Income, Amount: 100}, false)
+
+		assert.Empty(t, matched)
+		assert.Nil(t, result.CategoryID)
+		assert.Equal(t, []string{}, result.Tags)
+	})
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/summary"
+	"github.com/felipe1496/open-wallet/internal/resources/summary/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSummaryUseCase_ListPeriodSummaries(t *testing.T) {
+	t.Run("should reject invalid periods", func(t *testing.T) {
+		mockRepo := new(mocks.MockSummaryRepo)
+		uc := summary.NewSummaryUseCase(mockRepo, nil)
+
+		_, err := uc.ListPeriodSummaries("user-1", "2025-01", "202512")
+		assert.ErrorIs(t, err, summary.ErrInvalidPeriod)
+
+		_, err = uc.ListPeriodSummaries("user-1", "202501", "202513")
+		assert.ErrorIs(t, err, summary.ErrInvalidPeriod)
+
+		mockRepo.AssertNotCalled(t, "ListPeriodSummaries")
+	})
+
+	t.Run("should reject from after to", func(t *testing.T) {
+		mockRepo := new(mocks.MockSummaryRepo)
+		uc := summary.NewSummaryUseCase(mockRepo, nil)
+
+		_, err := uc.ListPeriodSummaries("user-1", "202502", "202501")
+
+		assert.ErrorIs(t, err, summary.ErrInvalidPeriodRange)
+		mockRepo.AssertNotCalled(t, "ListPeriodSummaries")
+	})
+
+	t.Run("should accept a single period", func(t *testing.T) {
+		mockRepo := new(mocks.MockSummaryRepo)
+		uc := summary.NewSummaryUseCase(mockRepo, nil)
+
+		mockRepo.On("ListPeriodSummaries", mock.Anything, "user-1", "202501", "202501").
+			Return([]summary.PeriodSummary{{Period: "202501"}}, nil)
+
+		summaries, err := uc.ListPeriodSummaries("user-1", "202501", "202501")
+
+		assert.NoError(t, err)
+		assert.Len(t, summaries, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should limit the range to 120 periods", func(t *testing.T) {
+		mockRepo := new(mocks.MockSummaryRepo)
+		uc := summary.NewSummaryUseCase(mockRepo, nil)
+
+		mockRepo.On("ListPeriodSummaries", mock.Anything, "user-1", "202001", "202912").
+			Return([]summary.PeriodSummary{}, nil)
+
+		_, err := uc.ListPeriodSummaries("user-1", "202001", "202912")
+		assert.NoError(t, err)
+
+		_, err = uc.ListPeriodSummaries("user-1", "201912", "202912")
+		assert.ErrorIs(t, err, summary.ErrPeriodRangeTooLong)
+
+		mockRepo.AssertNumberOfCalls(t, "ListPeriodSummaries", 1)
+	})
+
+	t.Run("should return error when repo fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockSummaryRepo)
+		uc := summary.NewSummaryUseCase(mockRepo, nil)
+
+		mockRepo.On("ListPeriodSummaries", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]summary.PeriodSummary{}, errors.New("db error"))
+
+		_, err := uc.ListPeriodSummaries("user-1", "202501", "202512")
+
+		assert.ErrorIs(t, err, summary.ErrFailedToListSummaries)
+	})
+}
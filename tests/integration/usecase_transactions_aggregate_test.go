@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions/mocks"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// listEntriesOpts is what QueryOptsMiddleware builds for the second page of a
+// filtered entries list
+func listEntriesOpts() *utils.QueryOptsBuilder {
+	return utils.QueryOpts().
+		And("user_id", "eq", "user-1").
+		And("period", "eq", "202501").
+		OrderBy("reference_date", "desc").
+		Limit(11).
+		Offset(10)
+}
+
+func TestTransactionsUseCase_AggregateViewEntries(t *testing.T) {
+	t.Run("should aggregate every entry matching the filter, not just the page", func(t *testing.T) {
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("COUNT(*)").From("v_entries"), utils.ForCount(listEntriesOpts())).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM v_entries WHERE user_id = ? AND period = ?", sql)
+		assert.Equal(t, []any{"user-1", "202501"}, args)
+	})
+
+	t.Run("should return the totals of the repo", func(t *testing.T) {
+		mockRepo := new(mocks.MockTransactionsRepo)
+		uc := transactions.NewTransactionsUseCase(mockRepo, nil, nil, nil)
+		aggregate := transactions.EntriesAggregate{
+			Income:   5000,
+			Expenses: -1350.5,
+			Net:      3649.5,
+			CountByType: map[constants.TransactionType]int{
+				constants.SimpleExpense: 3,
+				constants.Income:        1,
+				constants.Installment:   2,
+			},
+		}
+
+		mockRepo.On("AggregateViewEntries", mock.Anything, mock.MatchedBy(func(filter *utils.QueryOptsBuilder) bool {
+			return len(filter.AndConditions) == 2 && filter.LimitValue == nil && filter.OffsetValue == nil && len(filter.Orders) == 0
+		})).Return(aggregate, nil)
+
+		result, err := uc.AggregateViewEntries(utils.ForCount(listEntriesOpts()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, aggregate, result)
+		assert.Equal(t, result.Income+result.Expenses, result.Net)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error when repo fails", func(t *testing.T) {
+		mockRepo := new(mocks.MockTransactionsRepo)
+		uc := transactions.NewTransactionsUseCase(mockRepo, nil, nil, nil)
+
+		mockRepo.On("AggregateViewEntries", mock.Anything, mock.Anything).
+			Return(transactions.EntriesAggregate{}, errors.New("db error"))
+
+		_, err := uc.AggregateViewEntries(utils.ForCount(listEntriesOpts()))
+
+		assert.ErrorIs(t, err, transactions.ErrToAggregateEntries)
+	})
+
+	t.Run("should count every entry matching the filter", func(t *testing.T) {
+		mockRepo := new(mocks.MockTransactionsRepo)
+		uc := transactions.NewTransactionsUseCase(mockRepo, nil, nil, nil)
+
+		mockRepo.On("CountViewEntries", mock.Anything, mock.MatchedBy(func(filter *utils.QueryOptsBuilder) bool {
+			return len(filter.AndConditions) == 2 && filter.LimitValue == nil && filter.OffsetValue == nil
+		})).Return(42, nil)
+
+		count, err := uc.CountViewEntries(utils.ForCount(listEntriesOpts()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, count)
+	})
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportEntries = []transactions.ViewEntry{
+	{
+		Name:              "Notebook",
+		Description:       strPtr("parcelado, sem juros"),
+		Amount:            -1234.5,
+		Type:              constants.Installment,
+		Installment:       2,
+		TotalInstallments: 10,
+		ReferenceDate:     "2025-02-10",
+		CategoryName:      strPtr("Eletrônicos"),
+		Payee:             strPtr("Loja <Tech> & Cia"),
+		Account:           strPtr("nubank"),
+		Tags:              []string{"trabalho", "casa"},
+	},
+	{
+		Name:              "Salário",
+		Amount:            5000,
+		Type:              constants.Income,
+		Installment:       1,
+		TotalInstallments: 1,
+		ReferenceDate:     "2025-02-05",
+		Tags:              []string{},
+	},
+}
+
+func writeEntries(t *testing.T, format string, decimalSeparator string) []byte {
+	var buf bytes.Buffer
+	writer, err := transactions.NewEntriesWriter(&buf, format, decimalSeparator)
+	require.NoError(t, err)
+
+	for _, entry := range exportEntries {
+		require.NoError(t, writer.Write(entry))
+	}
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func TestExportEntriesCSV(t *testing.T) {
+	t.Run("should write a row per entry with installment and category", func(t *testing.T) {
+		records, err := csv.NewReader(bytes.NewReader(writeEntries(t, "csv", "."))).ReadAll()
+
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"date", "name", "description", "category", "type", "installment", "amount", "payee", "account", "tags"}, records[0])
+		assert.Equal(t, []string{"2025-02-10", "Notebook", "parcelado, sem juros", "Eletrônicos", "installment", "2/10", "-1234.50", "Loja <Tech> & Cia", "nubank", "trabalho, casa"}, records[1])
+		assert.Equal(t, []string{"2025-02-05", "Salário", "", "", "income", "1/1", "5000.00", "", "", ""}, records[2])
+	})
+
+	t.Run("should use semicolons and a BOM with a comma as decimal separator", func(t *testing.T) {
+		data := writeEntries(t, "csv", ",")
+
+		assert.True(t, bytes.HasPrefix(data, []byte("\ufeff")))
+
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+		reader.Comma = ';'
+		records, err := reader.ReadAll()
+
+		require.NoError(t, err)
+		assert.Equal(t, "date", records[0][0])
+		assert.Equal(t, "-1234,50", records[1][6])
+		assert.Equal(t, "5000,00", records[2][6])
+	})
+
+	t.Run("should keep user text from being read as a formula", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer, err := transactions.NewEntriesWriter(&buf, "csv", ".")
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(transactions.ViewEntry{
+			Name:          `=HYPERLINK("http://evil.example","x")`,
+			Description:   strPtr("+55 11 9999"),
+			CategoryName:  strPtr("-taxas"),
+			Payee:         strPtr("@loja"),
+			Account:       strPtr("conta = corrente"),
+			Amount:        -10,
+			Type:          constants.SimpleExpense,
+			ReferenceDate: "2025-02-10",
+			Tags:          []string{},
+		}))
+		require.NoError(t, writer.Close())
+
+		records, err := csv.NewReader(&buf).ReadAll()
+
+		require.NoError(t, err)
+		assert.Equal(t, `'=HYPERLINK("http://evil.example","x")`, records[1][1])
+		assert.Equal(t, "'+55 11 9999", records[1][2])
+		assert.Equal(t, "'-taxas", records[1][3])
+		assert.Equal(t, "'@loja", records[1][7])
+		assert.Equal(t, "conta = corrente", records[1][8])
+		assert.Equal(t, "-10.00", records[1][6])
+	})
+
+	t.Run("should reject unknown options", func(t *testing.T) {
+		_, err := transactions.NewEntriesWriter(io.Discard, "pdf", ".")
+		assert.ErrorIs(t, err, transactions.ErrInvalidExportFormat)
+
+		_, err = transactions.NewEntriesWriter(io.Discard, "csv", ";")
+		assert.ErrorIs(t, err, transactions.ErrInvalidDecimalSeparator)
+	})
+}
+
+func TestExportEntriesXLSX(t *testing.T) {
+	data := writeEntries(t, "xlsx", ",")
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		parts[file.Name] = string(content)
+	}
+
+	t.Run("should hold the parts of a workbook", func(t *testing.T) {
+		for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+			assert.Contains(t, parts, name)
+		}
+	})
+
+	t.Run("should write dates and amounts as numbers and escape text", func(t *testing.T) {
+		sheet := parts["xl/worksheets/sheet1.xml"]
+
+		assert.Equal(t, 3, strings.Count(sheet, "<row "))
+		assert.Contains(t, sheet, `<c r="A2" s="1"><v>45698</v></c>`)
+		assert.Contains(t, sheet, `<c r="G2" s="2"><v>-1234.50</v></c>`)
+		assert.Contains(t, sheet, `<c r="F2" t="inlineStr"><is><t xml:space="preserve">2/10</t></is></c>`)
+		assert.Contains(t, sheet, "Loja &lt;Tech&gt; &amp; Cia")
+		assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+	})
+
+	t.Run("should write text starting like a formula as an inline string", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer, err := transactions.NewEntriesWriter(&buf, "xlsx", ".")
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(transactions.ViewEntry{Name: "=1+1", Amount: -1, Type: constants.SimpleExpense, ReferenceDate: "2025-02-10", Tags: []string{}}))
+		require.NoError(t, writer.Close())
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		rc, err := archive.File[len(archive.File)-1].Open()
+		require.NoError(t, err)
+		sheet, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		assert.Contains(t, string(sheet), `<c r="B2" t="inlineStr"><is><t xml:space="preserve">=1+1</t></is></c>`)
+		assert.NotContains(t, string(sheet), "<f>")
+	})
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/constants"
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// example of the BR Code manual
+const staticPix = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+const dynamicPix = "00020101021226540014BR.GOV.BCB.PIX0119padaria@example.com0209Pedido 42520400005303986540525.905802BR5925PADARIA SAO JOAO COMERCIO6009SAO PAULO62120508TX123ABC6304DCAE"
+
+func TestParsePix(t *testing.T) {
+	t.Run("should read a static BR Code without amount", func(t *testing.T) {
+		pix, err := transactions.ParsePix(staticPix)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123e4567-e12b-12d1-a456-426655440000", *pix.Key)
+		assert.Equal(t, "Fulano de Tal", *pix.MerchantName)
+		assert.Equal(t, "BRASILIA", *pix.MerchantCity)
+		assert.Nil(t, pix.Amount)
+		assert.Nil(t, pix.TxID)
+		assert.False(t, pix.Dynamic)
+	})
+
+	t.Run("should read amount, message and txid", func(t *testing.T) {
+		pix, err := transactions.ParsePix("  " + dynamicPix + "\n")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "padaria@example.com", *pix.Key)
+		assert.Equal(t, "Pedido 42", *pix.Message)
+		assert.Equal(t, 25.9, *pix.Amount)
+		assert.Equal(t, "TX123ABC", *pix.TxID)
+		assert.True(t, pix.Dynamic)
+	})
+
+	t.Run("should reject invalid payloads", func(t *testing.T) {
+		_, err := transactions.ParsePix(staticPix[:len(staticPix)-4] + "1D3E")
+		assert.ErrorIs(t, err, transactions.ErrInvalidPixCRC)
+
+		_, err = transactions.ParsePix("000201260")
+		assert.ErrorIs(t, err, transactions.ErrInvalidPixPayload)
+
+		other := "00020126220010br.gov.xyz0104abcd5802BR6304"
+		_, err = transactions.ParsePix(other + transactions.PixCRC16(other))
+		assert.ErrorIs(t, err, transactions.ErrNotPixPayload)
+	})
+}
+
+func TestPixDraft(t *testing.T) {
+	pix, _ := transactions.ParsePix(dynamicPix)
+	payees := []transactions.PayeeUsage{
+		{Payee: "Mercado Central", Count: 10},
+		{Payee: "Padaria São João Comércio de Pães", CategoryID: strPtr("food"), Count: 4},
+	}
+
+	t.Run("should match a payee by the truncated merchant name", func(t *testing.T) {
+		match := transactions.MatchPayee(payees, *pix.MerchantName)
+		draft := transactions.BuildPixDraft(pix, match, "2025-04-20")
+
+		assert.True(t, draft.MatchedPayee)
+		assert.Equal(t, "Padaria São João Comércio de Pães", draft.Transaction.Name)
+		assert.Equal(t, "food", *draft.Transaction.CategoryID)
+		assert.Equal(t, constants.SimpleExpense, draft.Transaction.Type)
+		assert.Equal(t, "Pedido 42", *draft.Transaction.Note)
+		assert.Equal(t, -25.9, draft.Transaction.Entries[0].Amount)
+		assert.Equal(t, "2025-04-20", draft.Transaction.Entries[0].ReferenceDate)
+		assert.Empty(t, draft.Missing)
+	})
+
+	t.Run("should use the merchant name when nothing matches", func(t *testing.T) {
+		static, _ := transactions.ParsePix(staticPix)
+		draft := transactions.BuildPixDraft(static, transactions.MatchPayee(payees, *static.MerchantName), "2025-04-20")
+
+		assert.False(t, draft.MatchedPayee)
+		assert.Equal(t, "Fulano de Tal", *draft.Transaction.Payee)
+		assert.Equal(t, []string{"amount"}, draft.Missing)
+		assert.Empty(t, draft.Transaction.Entries)
+	})
+
+	t.Run("should only match names that were cut by prefix", func(t *testing.T) {
+		assert.Nil(t, transactions.MatchPayee(payees, "MERCADO"))
+		assert.Equal(t, "Mercado Central", transactions.MatchPayee(payees, "MERCADO CENTRAL").Payee)
+	})
+}
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/felipe1496/open-wallet/internal/resources/transactions"
+	"github.com/felipe1496/open-wallet/internal/utils"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryOpts_Exprs(t *testing.T) {
+	t.Run("should add the search condition and keep it when counting", func(t *testing.T) {
+		qo := utils.QueryOpts().And("user_id", "eq", "u1").OrderBy("name", "asc")
+		qo.AndExpr(transactions.SearchCondition("farmácia"))
+
+		sql, args, err := utils.QueryOptsToSquirrel(squirrel.Select("COUNT(*)").From("v_entries"), utils.ForCount(qo)).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM v_entries WHERE user_id = ? AND transaction_id IN (SELECT id FROM transactions WHERE to_tsvector('portuguese_unaccent', coalesce(name, '') || ' ' || coalesce(description, '')) @@ websearch_to_tsquery('portuguese_unaccent', ?))", sql)
+		assert.Equal(t, []any{"u1", "farmácia"}, args)
+	})
+
+	t.Run("should accept le and ge as comparison operators", func(t *testing.T) {
+		qo := utils.QueryOpts().And("amount", "ge", -10).And("period", "le", "202501")
+
+		sql, _, err := utils.QueryOptsToSquirrel(squirrel.Select("id").From("v_entries"), qo).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM v_entries WHERE amount >= ? AND period <= ?", sql)
+	})
+
+	t.Run("should escape the text of the snippet before highlighting it", func(t *testing.T) {
+		sql, args, err := squirrel.Select().Column(transactions.SearchSnippet("farmácia")).ToSql()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT ts_headline('portuguese_unaccent', "+
+			`replace(replace(replace(replace(replace(name || coalesce(' - ' || description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), `+
+			"websearch_to_tsquery('portuguese_unaccent', ?), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')", sql)
+		assert.Equal(t, []any{"farmácia"}, args)
+	})
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/userdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleArchive() userdata.Archive {
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	return userdata.Archive{
+		Version:    userdata.ArchiveVersion,
+		ExportedAt: createdAt,
+		User:       userdata.ArchiveUser{Name: "Ana", Email: "ana@example.com", Username: "ana", EnvelopeBudgetingSince: strPtr("202501")},
+		Categories: []userdata.ArchiveCategory{
+			{ID: "cat-1", Name: "Mercado", Color: "#00ff00", CreatedAt: createdAt},
+			{ID: "cat-2", Name: "Salário", Color: "#0000ff", CreatedAt: createdAt},
+		},
+		Transactions: []userdata.ArchiveTransaction{
+			{
+				ID:         "tx-1",
+				Type:       "installment",
+				Name:       "Geladeira",
+				CategoryID: strPtr("cat-1"),
+				Tags:       []string{"casa"},
+				CreatedAt:  createdAt,
+				Entries: []userdata.ArchiveEntry{
+					{ID: "en-1", Amount: -500, ReferenceDate: "2025-01-10", CreatedAt: createdAt},
+					{ID: "en-2", Amount: -500, ReferenceDate: "2025-02-10", CreatedAt: createdAt},
+				},
+				Items: []userdata.ArchiveItem{
+					{ID: "it-1", Position: 1, Description: "Geladeira", Quantity: 1, UnitPrice: 1000, Amount: 1000},
+				},
+			},
+		},
+		Budgets: []userdata.ArchiveBudget{
+			{ID: "bu-1", CategoryID: "cat-1", Amount: 800, CreatedAt: createdAt},
+		},
+		EnvelopeMovements: []userdata.ArchiveMovement{
+			{ID: "mv-1", Period: "202501", ToCategoryID: strPtr("cat-1"), Amount: 100, CreatedAt: createdAt},
+		},
+		RecurringTransactions: []userdata.ArchiveRecurring{
+			{ID: "re-1", CategoryID: strPtr("cat-2"), Name: "Salário", Type: "income", Amount: 5000, StartPeriod: "202501", CreatedAt: createdAt},
+		},
+		NetWorthItems: []userdata.ArchiveNetWorthItem{
+			{ID: "nw-1", Name: "Poupança", Kind: "asset", CreatedAt: createdAt, Valuations: []userdata.ArchiveValuation{
+				{ID: "va-1", Value: 1000, ValuedAt: "2025-01-31", CreatedAt: createdAt},
+			}},
+		},
+		Rules: []userdata.ArchiveRule{
+			{ID: "ru-1", Name: "Mercado", Conditions: json.RawMessage(`{"name_contains":"mercado"}`), Actions: json.RawMessage(`{"set_category_id":"cat-1","add_tags":["feira"]}`), CreatedAt: createdAt},
+		},
+		ImportProfiles: []userdata.ArchiveImportProfile{
+			{ID: "ip-1", Name: "Nubank", Mapping: json.RawMessage(`{"date":"Data"}`), CreatedAt: createdAt},
+		},
+	}
+}
+
+func sequentialIDs() func() string {
+	next := 0
+	return func() string {
+		next++
+		return fmt.Sprintf("new-%d", next)
+	}
+}
+
+func TestArchiveEncoding(t *testing.T) {
+	for _, format := range []string{userdata.FormatJSON, userdata.FormatZip} {
+		t.Run("should read back a "+format+" archive", func(t *testing.T) {
+			data, err := userdata.EncodeArchive(sampleArchive(), format)
+			require.NoError(t, err)
+
+			archive, err := userdata.DecodeArchive(data)
+
+			require.NoError(t, err)
+			assert.Equal(t, sampleArchive().Transactions, archive.Transactions)
+			assert.Equal(t, sampleArchive().NetWorthItems, archive.NetWorthItems)
+		})
+	}
+
+	t.Run("should refuse archives of a newer version", func(t *testing.T) {
+		archive := sampleArchive()
+		archive.Version = userdata.ArchiveVersion + 1
+		data, _ := userdata.EncodeArchive(archive, userdata.FormatJSON)
+
+		_, err := userdata.DecodeArchive(data)
+		assert.ErrorIs(t, err, userdata.ErrUnsupportedArchiveVersion)
+	})
+
+	t.Run("should refuse files that aren't archives", func(t *testing.T) {
+		_, err := userdata.DecodeArchive([]byte(`{"transactions":[]}`))
+		assert.ErrorIs(t, err, userdata.ErrInvalidArchive)
+
+		_, err = userdata.DecodeArchive([]byte("PK\x03\x04 not really a zip"))
+		assert.ErrorIs(t, err, userdata.ErrInvalidArchive)
+	})
+}
+
+func TestRemapArchive(t *testing.T) {
+	t.Run("should give new ids and follow the references", func(t *testing.T) {
+		archive, err := userdata.RemapArchive(sampleArchive(), sequentialIDs())
+		require.NoError(t, err)
+
+		mercado := archive.Categories[0].ID
+		salario := archive.Categories[1].ID
+		assert.Equal(t, "new-1", mercado)
+		assert.Equal(t, "new-2", salario)
+
+		transaction := archive.Transactions[0]
+		assert.Equal(t, "new-3", transaction.ID)
+		assert.Equal(t, mercado, *transaction.CategoryID)
+		assert.Equal(t, []string{"new-4", "new-5"}, []string{transaction.Entries[0].ID, transaction.Entries[1].ID})
+		assert.Equal(t, "new-6", transaction.Items[0].ID)
+
+		assert.Equal(t, mercado, archive.Budgets[0].CategoryID)
+		assert.Nil(t, archive.EnvelopeMovements[0].FromCategoryID)
+		assert.Equal(t, mercado, *archive.EnvelopeMovements[0].ToCategoryID)
+		assert.Equal(t, salario, *archive.RecurringTransactions[0].CategoryID)
+		assert.NotEqual(t, "va-1", archive.NetWorthItems[0].Valuations[0].ID)
+		assert.JSONEq(t, `{"set_category_id":"new-1","add_tags":["feira"]}`, string(archive.Rules[0].Actions))
+		assert.JSONEq(t, `{"name_contains":"mercado"}`, string(archive.Rules[0].Conditions))
+	})
+
+	t.Run("should not change the archive it was given", func(t *testing.T) {
+		original := sampleArchive()
+		_, err := userdata.RemapArchive(original, sequentialIDs())
+
+		require.NoError(t, err)
+		assert.Equal(t, sampleArchive(), original)
+	})
+
+	t.Run("should refuse references to records outside the archive", func(t *testing.T) {
+		archive := sampleArchive()
+		archive.Budgets[0].CategoryID = "cat-9"
+		_, err := userdata.RemapArchive(archive, sequentialIDs())
+		assert.ErrorIs(t, err, userdata.ErrArchiveBrokenReference)
+
+		archive = sampleArchive()
+		archive.Rules[0].Actions = json.RawMessage(`{"set_category_id":"cat-9"}`)
+		_, err = userdata.RemapArchive(archive, sequentialIDs())
+		assert.ErrorIs(t, err, userdata.ErrArchiveBrokenReference)
+	})
+
+	t.Run("should refuse repeated ids", func(t *testing.T) {
+		archive := sampleArchive()
+		archive.Categories[1].ID = "cat-1"
+		_, err := userdata.RemapArchive(archive, sequentialIDs())
+		assert.ErrorIs(t, err, userdata.ErrInvalidArchive)
+	})
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipe1496/open-wallet/internal/resources/userdata"
+	userdataMocks "github.com/felipe1496/open-wallet/internal/resources/userdata/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccountDeletion(t *testing.T) {
+	t.Run("should only confirm with the username", func(t *testing.T) {
+		assert.True(t, userdata.ConfirmsDeletion("ana", "ana"))
+		assert.True(t, userdata.ConfirmsDeletion("ana", " ana\n"))
+		assert.False(t, userdata.ConfirmsDeletion("ana", "Ana"))
+		assert.False(t, userdata.ConfirmsDeletion("ana", "ana@example.com"))
+		assert.False(t, userdata.ConfirmsDeletion("", ""))
+	})
+
+	t.Run("should hash the user id for the erasure record", func(t *testing.T) {
+		subject := userdata.ErasureSubject("01JABCDEF")
+
+		assert.Len(t, subject, 64)
+		assert.Equal(t, subject, userdata.ErasureSubject("01JABCDEF"))
+		assert.NotEqual(t, subject, userdata.ErasureSubject("01JABCDEG"))
+		assert.NotContains(t, subject, "01JABCDEF")
+	})
+}
+
+func TestEraseDueAccounts(t *testing.T) {
+	now := time.Date(2025, 5, 1, 3, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-userdata.DeletionGracePeriod - time.Hour)
+	due := &userdata.AccountDeletion{RequestedAt: requestedAt, ScheduledFor: requestedAt.Add(userdata.DeletionGracePeriod)}
+
+	setup := func(t *testing.T) (*userdataMocks.MockUserDataRepo, *TxDB, userdata.UserDataUseCase) {
+		repo := new(userdataMocks.MockUserDataRepo)
+		db := SetupTxDB(t)
+		repo.On("LockUser", mock.Anything, mock.Anything).Return(nil)
+		return repo, db, userdata.NewUserDataUseCase(repo, db.DB)
+	}
+
+	t.Run("should skip an account whose deletion was cancelled after being listed", func(t *testing.T) {
+		repo, db, uc := setup(t)
+		repo.On("ListDueDeletions", mock.Anything, now).Return([]string{"u1"}, nil)
+		repo.On("GetDeletion", mock.Anything, "u1").Return((*userdata.AccountDeletion)(nil), nil)
+
+		erased, err := uc.EraseDueAccounts(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, erased)
+		repo.AssertNotCalled(t, "EraseRecords", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+		assert.Equal(t, 0, db.Commits)
+		assert.Equal(t, 1, db.Rollbacks)
+	})
+
+	t.Run("should record the erased counts with the user", func(t *testing.T) {
+		repo, db, uc := setup(t)
+		repo.On("ListDueDeletions", mock.Anything, now).Return([]string{"u1"}, nil)
+		repo.On("GetDeletion", mock.Anything, "u1").Return(due, nil)
+		repo.On("EraseRecords", mock.Anything, "u1").Return(map[string]int64{"transactions": 3, "entries": 5}, nil)
+		repo.On("DeleteUser", mock.Anything, "u1").Return(nil)
+		repo.On("CreateErasure", mock.Anything, userdata.AccountErasure{
+			SubjectHash:   userdata.ErasureSubject("u1"),
+			RequestedAt:   requestedAt,
+			ErasedRecords: map[string]int64{"transactions": 3, "entries": 5, "users": 1},
+		}).Return(userdata.AccountErasure{}, nil)
+
+		erased, err := uc.EraseDueAccounts(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, erased)
+		repo.AssertExpectations(t)
+		assert.Equal(t, 1, db.Commits)
+	})
+
+	t.Run("should keep erasing the others when one fails", func(t *testing.T) {
+		repo, db, uc := setup(t)
+		repo.On("ListDueDeletions", mock.Anything, now).Return([]string{"u1", "u2"}, nil)
+		repo.On("GetDeletion", mock.Anything, mock.Anything).Return(due, nil)
+		repo.On("EraseRecords", mock.Anything, "u1").Return(map[string]int64(nil), errors.New("deadlock"))
+		repo.On("EraseRecords", mock.Anything, "u2").Return(map[string]int64{}, nil)
+		repo.On("DeleteUser", mock.Anything, "u2").Return(nil)
+		repo.On("CreateErasure", mock.Anything, mock.Anything).Return(userdata.AccountErasure{}, nil)
+
+		erased, err := uc.EraseDueAccounts(now)
+
+		assert.EqualError(t, err, "deadlock")
+		assert.Equal(t, 1, erased)
+		repo.AssertNotCalled(t, "DeleteUser", mock.Anything, "u1")
+		assert.Equal(t, 1, db.Commits)
+		assert.Equal(t, 1, db.Rollbacks)
+	})
+}
+
+// deleteRecorder answers each delete with its position as the rows affected
+type deleteRecorder struct {
+	tables []string
+}
+
+func (r *deleteRecorder) Exec(query string, args ...any) (sql.Result, error) {
+	r.tables = append(r.tables, strings.Fields(query)[2])
+	return driver.RowsAffected(len(r.tables)), nil
+}
+
+func (r *deleteRecorder) Query(query string, args ...any) (*sql.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (r *deleteRecorder) QueryRow(query string, args ...any) *sql.Row {
+	return nil
+}
+
+func (r *deleteRecorder) Prepare(query string) (*sql.Stmt, error) {
+	return nil, errors.New("unexpected prepare")
+}
+
+// foreignKeys reads the child -> parent references out of the migrations
+func foreignKeys(t *testing.T) [][2]string {
+	files, err := filepath.Glob("../../migrations/*.up.sql")
+	assert.NoError(t, err)
+
+	table := regexp.MustCompile(`^(?:create|alter) table (\w+)`)
+	reference := regexp.MustCompile(`references (\w+)\(`)
+
+	keys := make([][2]string, 0)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		current := ""
+		for _, line := range strings.Split(string(data), "\n") {
+			if match := table.FindStringSubmatch(line); match != nil {
+				current = match[1]
+			}
+			for _, match := range reference.FindAllStringSubmatch(line, -1) {
+				keys = append(keys, [2]string{current, match[1]})
+			}
+		}
+	}
+	return keys
+}
+
+func TestEraseRecords(t *testing.T) {
+	recorder := &deleteRecorder{}
+
+	erased, err := userdata.NewUserDataRepo(nil).EraseRecords(recorder, "u1")
+	assert.NoError(t, err)
+
+	position := make(map[string]int)
+	for i, table := range recorder.tables {
+		position[table] = i
+		assert.Equal(t, int64(i+1), erased[table], table)
+	}
+	assert.Len(t, erased, len(recorder.tables))
+
+	t.Run("should erase every table that references a user", func(t *testing.T) {
+		for _, key := range foreignKeys(t) {
+			if key[1] == "users" {
+				assert.Contains(t, position, key[0])
+			}
+		}
+	})
+
+	t.Run("should erase the children before the rows they reference", func(t *testing.T) {
+		keys := foreignKeys(t)
+		assert.NotEmpty(t, keys)
+
+		for _, key := range keys {
+			if key[1] == "users" {
+				continue
+			}
+			assert.Less(t, position[key[0]], position[key[1]], "%s references %s", key[0], key[1])
+		}
+	})
+}